@@ -0,0 +1,48 @@
+package workflow
+
+import "context"
+
+// PipeResult is the outcome of a Pipe execution, delivered by ExecuteAsync.
+type PipeResult[T any] struct {
+	Value T
+	Err   error
+}
+
+// ExecuteAsync runs Execute in a new goroutine, and delivers its error, nil on success, on the returned channel.
+// The channel is buffered and receives a single value, so the goroutine ends once the workflow is done, even if the
+// caller never reads the channel. The channel is not closed.
+// The workflow is cancelled through the ctx, as for Execute: a ctx cancelled once the caller is done, like the one of an
+// HTTP request, stops the workflow, so a detached ctx must be provided for a fire and forget execution.
+// The returned cancel function cancels the ctx passed to the steps, derived from the ctx, so the workflow stops before
+// its next step. The running step stops only if it honors its ctx. Calling the cancel function after the workflow is
+// done has no effect.
+func (s *Sequential[T]) ExecuteAsync(ctx context.Context, req T) (<-chan error, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	res := make(chan error, 1)
+	go func() {
+		defer cancel()
+		res <- s.Execute(ctx, req)
+	}()
+
+	return res, cancel
+}
+
+// ExecuteAsync runs Execute in a new goroutine, and delivers its output and error on the returned channel.
+// The channel is buffered and receives a single value, so the goroutine ends once the workflow is done, even if the
+// caller never reads the channel. The channel is not closed.
+// The workflow is cancelled through the ctx, as for Execute: a ctx cancelled once the caller is done, like the one of an
+// HTTP request, stops the workflow, so a detached ctx must be provided for a fire and forget execution.
+// The returned cancel function cancels the ctx passed to the steps, derived from the ctx, so the workflow stops before
+// its next step. The running step stops only if it honors its ctx. Calling the cancel function after the workflow is
+// done has no effect.
+func (p *Pipe[T]) ExecuteAsync(ctx context.Context, req T) (<-chan PipeResult[T], context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	res := make(chan PipeResult[T], 1)
+	go func() {
+		defer cancel()
+		out, err := p.Execute(ctx, req)
+		res <- PipeResult[T]{Value: out, Err: err}
+	}()
+
+	return res, cancel
+}
@@ -0,0 +1,136 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSequentialExecuteAsync(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name           string
+		input          []SequentialStepConfig[any]
+		expectedOutput error
+	}{
+		{
+			name:           "a succeeding workflow should deliver a nil error",
+			input:          []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}},
+			expectedOutput: nil,
+		},
+		{
+			name:           "a failing workflow should deliver the step error",
+			input:          []SequentialStepConfig[any]{{Step: newStepFailedNonRetryable("step 1", anyErr)}},
+			expectedOutput: anyErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, cancel := NewSequential("some-workflow", tt.input).ExecuteAsync(context.TODO(), nil)
+			defer cancel()
+
+			select {
+			case err := <-res:
+				if !errors.Is(err, tt.expectedOutput) || (tt.expectedOutput == nil && err != nil) {
+					t.Errorf("The delivered error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("The workflow result was not delivered")
+			}
+		})
+	}
+}
+
+func TestPipeExecuteAsync(t *testing.T) {
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: newPipeStepAppend("step 2", "-b")},
+	}
+
+	res, cancel := NewPipe("some-workflow", input).ExecuteAsync(context.TODO(), "x")
+	defer cancel()
+
+	select {
+	case r := <-res:
+		expectedOutput := PipeResult[string]{Value: "x-a-b"}
+		if r != expectedOutput {
+			t.Errorf("The delivered result is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, r)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("The workflow result was not delivered")
+	}
+}
+
+func TestSequentialExecuteAsyncBehaviourOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	input := []SequentialStepConfig[any]{
+		{Step: stepFuncMock(func(ctx context.Context, request any) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})},
+	}
+
+	res, _ := NewSequential("some-workflow", input).ExecuteAsync(ctx, nil)
+	<-started
+	cancel()
+
+	select {
+	case err := <-res:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("The delivered error is not as expected: \n expected = %#v, \n actual = %#v", context.Canceled, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("The workflow result was not delivered")
+	}
+}
+
+func TestSequentialExecuteAsyncCancel(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	// the first step doesn't honor its ctx, so it runs to completion, but the following step doesn't run.
+	step1 := stepFuncMock(func(ctx context.Context, request any) error {
+		close(started)
+		<-release
+		return nil
+	})
+	step2 := newStepSuccessful("step 2")
+	input := []SequentialStepConfig[any]{{Step: step1}, {Step: step2}}
+
+	res, cancel := NewSequential("some-workflow", input).ExecuteAsync(context.Background(), nil)
+	<-started
+	cancel()
+	close(release)
+
+	if err := <-res; !errors.Is(err, context.Canceled) {
+		t.Errorf("The delivered error is not as expected: \n expected = %#v, \n actual = %#v", context.Canceled, err)
+	}
+	if step2.invocationCount != 0 {
+		t.Errorf("The step following the cancellation ran: \n invocation count = %#v", step2.invocationCount)
+	}
+}
+
+func TestPipeExecuteAsyncCancel(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	step1 := newPipeStepFunc("step 1", func(req string) (string, error) {
+		close(started)
+		<-release
+		return req + "-a", nil
+	})
+	step2 := newPipeStepAppend("step 2", "-b")
+	input := []PipeStepConfig[string]{{Step: step1}, {Step: step2}}
+
+	res, cancel := NewPipe("some-workflow", input).ExecuteAsync(context.Background(), "x")
+	<-started
+	cancel()
+	close(release)
+
+	r := <-res
+	if !errors.Is(r.Err, context.Canceled) || r.Value != "x-a" {
+		t.Errorf("The delivered result is not as expected: \n expected = %#v, %#v, \n actual = %#v", "x-a", context.Canceled, r)
+	}
+	if step2.invocationCount != 0 {
+		t.Errorf("The step following the cancellation ran: \n invocation count = %#v", step2.invocationCount)
+	}
+}
@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"math"
+	"time"
+)
+
+// BackoffStrategy computes the delay to wait before a retry attempt.
+// The attempt starts from 1, for the first retry.
+//
+// A BackoffStrategy only decides how long to wait between attempts, the number of attempts is still provided
+// by the RetryConfigProvider, so a step config having a BackoffStrategy but no RetryConfigProvider is never retried.
+type BackoffStrategy interface {
+	Delay(attempt uint) time.Duration
+}
+
+// ConstantBackoff waits the same amount of time before every retry attempt.
+type ConstantBackoff time.Duration
+
+// Delay implements the BackoffStrategy interface.
+func (c ConstantBackoff) Delay(_ uint) time.Duration {
+	return time.Duration(c)
+}
+
+// LinearBackoff waits Base before the first retry attempt, and increases the delay by Increment for every following attempt.
+// The delay never exceeds Max, if Max is greater than 0.
+type LinearBackoff struct {
+	Base      time.Duration
+	Increment time.Duration
+	Max       time.Duration
+}
+
+// Delay implements the BackoffStrategy interface.
+func (l LinearBackoff) Delay(attempt uint) time.Duration {
+	if attempt == 0 {
+		attempt = 1
+	}
+	d := float64(l.Base) + float64(l.Increment)*float64(attempt-1)
+
+	return capDelay(d, l.Max)
+}
+
+// ExponentialBackoff waits Base before the first retry attempt, and multiplies the delay by Factor for every following attempt.
+// A Factor lower or equal to 1 is replaced by 2.
+// The delay never exceeds Max, if Max is greater than 0.
+type ExponentialBackoff struct {
+	Base   time.Duration
+	Max    time.Duration
+	Factor float64
+}
+
+// Delay implements the BackoffStrategy interface.
+func (e ExponentialBackoff) Delay(attempt uint) time.Duration {
+	if attempt == 0 {
+		attempt = 1
+	}
+	factor := e.Factor
+	if factor <= 1 {
+		factor = 2
+	}
+	d := float64(e.Base) * math.Pow(factor, float64(attempt-1))
+
+	return capDelay(d, e.Max)
+}
+
+// capDelay converts d to a time.Duration, bounded by maxDelay if maxDelay is greater than 0.
+// It also prevents the overflow of the time.Duration for very large values.
+func capDelay(d float64, maxDelay time.Duration) time.Duration {
+	if maxDelay > 0 && d > float64(maxDelay) {
+		return maxDelay
+	}
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	if d < 0 {
+		return 0
+	}
+
+	return time.Duration(d)
+}
+
+// MaxRetryAttempts caps the maximum number of retry attempts provided by a RetryConfigProvider, so an accidentally huge
+// value can't retry a step for ever. A higher value is replaced by MaxRetryAttempts, and reported by the Validate methods.
+const MaxRetryAttempts uint = 1000
+
+// retryConfig returns the retry configuration provided by the provider, with the maxAttempts capped to MaxRetryAttempts,
+// or no retry if the provider is nil.
+func retryConfig(provider func() (uint, time.Duration)) (maxAttempts uint, attemptDelay time.Duration) {
+	if provider == nil {
+		return 0, 0
+	}
+	maxAttempts, attemptDelay = provider()
+	if maxAttempts > MaxRetryAttempts {
+		maxAttempts = MaxRetryAttempts
+	}
+
+	return maxAttempts, attemptDelay
+}
+
+// WithRetryBackoffCap bounds the delay before every retry attempt to maxDelay, whatever computes it: a BackoffStrategy,
+// including a custom one, or the attemptDelay provided by a RetryConfigProvider. It also bounds the delay before the
+// workflow retry attempts. A maxDelay lower or equal to 0 means no bound, which is the default.
+func WithRetryBackoffCap(maxDelay time.Duration) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.retryBackoffCap = maxDelay
+	}
+}
+
+// WithPipeRetryBackoffCap is the Pipe version of WithRetryBackoffCap.
+func WithPipeRetryBackoffCap(maxDelay time.Duration) PipeOption {
+	return func(o *pipeOptions) {
+		o.retryBackoffCap = maxDelay
+	}
+}
+
+// retryDelay computes the delay before the retry attempt of the step, following the failure of the previous attempt with
+// the lastErr, using its RetryDelay if not nil, otherwise its Backoff or the attemptDelay, bounded by maxDelay if
+// maxDelay is greater than 0.
+func (c SequentialStepConfig[T]) retryDelay(attempt uint, lastErr error, attemptDelay, maxDelay time.Duration) time.Duration {
+	if c.RetryDelay != nil {
+		return retryDelay(nil, attempt, c.RetryDelay(attempt, lastErr), maxDelay)
+	}
+
+	return retryDelay(c.Backoff, attempt, attemptDelay, maxDelay)
+}
+
+// retryDelay computes the delay before the retry attempt of the step, same as for the SequentialStepConfig.
+func (c PipeStepConfig[T]) retryDelay(attempt uint, lastErr error, attemptDelay, maxDelay time.Duration) time.Duration {
+	if c.RetryDelay != nil {
+		return retryDelay(nil, attempt, c.RetryDelay(attempt, lastErr), maxDelay)
+	}
+
+	return retryDelay(c.Backoff, attempt, attemptDelay, maxDelay)
+}
+
+// retryDelay computes the delay before the retry attempt, using the backoff if not nil, otherwise the attemptDelay,
+// bounded by maxDelay if maxDelay is greater than 0.
+func retryDelay(backoff BackoffStrategy, attempt uint, attemptDelay, maxDelay time.Duration) time.Duration {
+	d := attemptDelay
+	if backoff != nil {
+		d = backoff.Delay(attempt)
+	}
+	if maxDelay > 0 && d > maxDelay {
+		return maxDelay
+	}
+
+	return d
+}
@@ -0,0 +1,244 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/silviutanasa/workflow/workflowtest"
+)
+
+func TestBackoffStrategyDelay(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          BackoffStrategy
+		expectedOutput []time.Duration
+	}{
+		{
+			name:           "a constant backoff should return the same delay for every attempt",
+			input:          ConstantBackoff(time.Second),
+			expectedOutput: []time.Duration{time.Second, time.Second, time.Second, time.Second},
+		},
+		{
+			name:           "a linear backoff should increase the delay by the increment for every attempt",
+			input:          LinearBackoff{Base: time.Second, Increment: 2 * time.Second},
+			expectedOutput: []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 7 * time.Second},
+		},
+		{
+			name:           "a linear backoff should not exceed the max delay",
+			input:          LinearBackoff{Base: time.Second, Increment: 2 * time.Second, Max: 4 * time.Second},
+			expectedOutput: []time.Duration{time.Second, 3 * time.Second, 4 * time.Second, 4 * time.Second},
+		},
+		{
+			name:           "an exponential backoff should multiply the delay by the factor for every attempt",
+			input:          ExponentialBackoff{Base: time.Second, Factor: 3},
+			expectedOutput: []time.Duration{time.Second, 3 * time.Second, 9 * time.Second, 27 * time.Second},
+		},
+		{
+			name:           "an exponential backoff without a valid factor should double the delay for every attempt",
+			input:          ExponentialBackoff{Base: time.Second},
+			expectedOutput: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:           "an exponential backoff should not exceed the max delay",
+			input:          ExponentialBackoff{Base: time.Second, Max: 5 * time.Second},
+			expectedOutput: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualOutput := make([]time.Duration, 0, len(tt.expectedOutput))
+			for attempt := uint(1); attempt <= uint(len(tt.expectedOutput)); attempt++ {
+				actualOutput = append(actualOutput, tt.input.Delay(attempt))
+			}
+
+			if !reflect.DeepEqual(actualOutput, tt.expectedOutput) {
+				t.Errorf("The backoff delays are not as expected: \n expected = %v, \n actual = %v", tt.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoffDelayDoesNotOverflow(t *testing.T) {
+	b := ExponentialBackoff{Base: time.Hour, Factor: 10}
+
+	actualOutput := b.Delay(1000)
+	if actualOutput != math.MaxInt64 {
+		t.Errorf("The backoff delay overflowed: \n expected = %v, \n actual = %v", time.Duration(math.MaxInt64), actualOutput)
+	}
+}
+
+func TestExecuteUsesTheBackoffStrategy(t *testing.T) {
+	anyErr := errors.New("any-err")
+
+	t.Run("sequential", func(t *testing.T) {
+		b := &backoffMock{}
+		input := []SequentialStepConfig[any]{
+			{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, Backoff: b},
+		}
+		NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+		expectedOutput := []uint{1, 2}
+		if !reflect.DeepEqual(b.attempts, expectedOutput) {
+			t.Errorf("The backoff was not consulted as expected: \n expected = %v, \n actual = %v", expectedOutput, b.attempts)
+		}
+	})
+	t.Run("pipe", func(t *testing.T) {
+		b := &backoffMock{}
+		input := []PipeStepConfig[any]{
+			{Step: newPipeStepFailedRetryable[any]("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, Backoff: b},
+		}
+		NewPipe("some-workflow", input, nil).Execute(context.TODO(), nil)
+
+		expectedOutput := []uint{1, 2}
+		if !reflect.DeepEqual(b.attempts, expectedOutput) {
+			t.Errorf("The backoff was not consulted as expected: \n expected = %v, \n actual = %v", expectedOutput, b.attempts)
+		}
+	})
+}
+
+func TestRetryDelayNeverExceedsTheCap(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    BackoffStrategy
+		inputCap time.Duration
+	}{
+		{
+			name:  "an exponential backoff should not exceed its max delay at high attempt counts",
+			input: ExponentialBackoff{Base: 100 * time.Millisecond, Max: 5 * time.Second},
+		},
+		{
+			name:     "an uncapped exponential backoff should not exceed the cap at high attempt counts",
+			input:    ExponentialBackoff{Base: 100 * time.Millisecond},
+			inputCap: 5 * time.Second,
+		},
+		{
+			name:     "a constant backoff should not exceed the cap",
+			input:    ConstantBackoff(time.Hour),
+			inputCap: 5 * time.Second,
+		},
+		{
+			name:     "a custom backoff should not exceed the cap",
+			input:    &backoffMock{delay: time.Hour},
+			inputCap: 5 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for attempt := uint(1); attempt <= MaxRetryAttempts; attempt++ {
+				actualOutput := retryDelay(tt.input, attempt, 0, tt.inputCap)
+				if actualOutput > 5*time.Second || actualOutput < 0 {
+					t.Fatalf("The delay of the attempt: %d exceeds the cap: \n expected <= %v, \n actual = %v", attempt, 5*time.Second, actualOutput)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteBehaviourOnRetryBackoffCap(t *testing.T) {
+	anyErr := errors.New("any-err")
+	// 2 retry attempts, each waiting the capped delay.
+	expectedOutput := 10 * time.Second
+
+	t.Run("sequential", func(t *testing.T) {
+		clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+		input := []SequentialStepConfig[any]{
+			{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, Backoff: ConstantBackoff(time.Hour)},
+		}
+		NewSequential("some-workflow", input, WithClock(clock), WithRetryBackoffCap(5*time.Second)).Execute(context.TODO(), nil)
+
+		if actualOutput := clock.Now().Sub(time.Time{}); actualOutput != expectedOutput {
+			t.Errorf("The waited time is not as expected: \n expected = %v, \n actual = %v", expectedOutput, actualOutput)
+		}
+	})
+	t.Run("pipe", func(t *testing.T) {
+		clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+		input := []PipeStepConfig[any]{
+			{Step: newPipeStepFailedRetryable[any]("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, Backoff: ConstantBackoff(time.Hour)},
+		}
+		NewPipe("some-workflow", input, WithPipeClock(clock), WithPipeRetryBackoffCap(5*time.Second)).Execute(context.TODO(), nil)
+
+		if actualOutput := clock.Now().Sub(time.Time{}); actualOutput != expectedOutput {
+			t.Errorf("The waited time is not as expected: \n expected = %v, \n actual = %v", expectedOutput, actualOutput)
+		}
+	})
+}
+
+func TestExecuteBehaviourOnRetryDelay(t *testing.T) {
+	// the first attempt is rate limited for a minute, the second one for a second.
+	errs := []error{retryAfterErr(time.Minute), retryAfterErr(time.Second), nil}
+	retryDelay := func(attempt uint, lastErr error) time.Duration {
+		var after retryAfterErr
+		if errors.As(lastErr, &after) {
+			return time.Duration(after)
+		}
+
+		return time.Hour
+	}
+	// the delays depend on the errors, whatever the Backoff.
+	expectedOutput := time.Minute + time.Second
+
+	t.Run("sequential", func(t *testing.T) {
+		clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+		var invocationCount int
+		step := retryableStepFuncMock(func(ctx context.Context, request any) error {
+			invocationCount++
+
+			return errs[invocationCount-1]
+		})
+		input := []SequentialStepConfig[any]{
+			{Step: step, RetryConfigProvider: defaultRetryConfigProviderTest, Backoff: ConstantBackoff(time.Hour), RetryDelay: retryDelay},
+		}
+		err := NewSequential("some-workflow", input, WithClock(clock)).Execute(context.TODO(), nil)
+
+		if actualOutput := clock.Now().Sub(time.Time{}); err != nil || actualOutput != expectedOutput {
+			t.Errorf("The waited time is not as expected: \n expected = %v, %v, \n actual = %v, %v", expectedOutput, nil, actualOutput, err)
+		}
+	})
+	t.Run("pipe", func(t *testing.T) {
+		clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+		var invocationCount int
+		step := newPipeStepFunc("step 1", func(req string) (string, error) {
+			invocationCount++
+
+			return req, errs[invocationCount-1]
+		})
+		input := []PipeStepConfig[string]{
+			{Step: step, RetryConfigProvider: defaultRetryConfigProviderTest, RetryIf: retryAlways, RetryDelay: retryDelay},
+		}
+		_, err := NewPipe("some-workflow", input, WithPipeClock(clock)).Execute(context.TODO(), "x")
+
+		if actualOutput := clock.Now().Sub(time.Time{}); err != nil || actualOutput != expectedOutput {
+			t.Errorf("The waited time is not as expected: \n expected = %v, %v, \n actual = %v, %v", expectedOutput, nil, actualOutput, err)
+		}
+	})
+}
+
+// MOCKS/STUBS
+// retryAfterErr is the error of a rate limited call, holding the delay after which it can be retried.
+type retryAfterErr time.Duration
+
+func (e retryAfterErr) Error() string {
+	return "retry after: " + time.Duration(e).String()
+}
+
+func retryAlways(_ context.Context, _ error, _ uint) bool {
+	return true
+}
+
+type backoffMock struct {
+	attempts []uint
+	delay    time.Duration // time.Nanosecond if 0
+}
+
+func (b *backoffMock) Delay(attempt uint) time.Duration {
+	b.attempts = append(b.attempts, attempt)
+	if b.delay == 0 {
+		return time.Nanosecond
+	}
+
+	return b.delay
+}
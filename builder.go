@@ -0,0 +1,192 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errNoStep is the error of a Builder or PipeBuilder step modifier called before any step was added.
+func errNoStep(modifier string) error {
+	return fmt.Errorf("%s is called before any step was added", modifier)
+}
+
+// Builder builds a Sequential workflow fluently, as an alternative to the SequentialStepConfig literals, e.g.
+//
+//	wf, err := NewBuilder[T]("some-workflow").
+//		AddStep(step1).ContinueOnError().
+//		AddStep(step2).WithRetry(retryConfig).
+//		Build()
+//
+// The step modifiers, like ContinueOnError, configure the last added step. The built workflow is the same as the one
+// built by NewSequential from the equivalent SequentialStepConfig literals.
+// The builder validates the steps as it goes, as described by Sequential.Validate: every added step is checked by Add,
+// and its retry configuration is checked again by WithRetry, the problems found being recorded on the spot, and
+// returned by Build.
+type Builder[T any] struct {
+	name  string
+	steps []SequentialStepConfig[T]
+	opts  []SequentialOption
+	errs  []error
+	v     stepsValidator // collects the problems of the steps, as they are added and modified
+}
+
+// NewBuilder is the Builder constructor.
+func NewBuilder[T any](name string) *Builder[T] {
+	return &Builder[T]{name: name, v: newStepsValidator(0)}
+}
+
+// AddStep adds the step, with the default configuration.
+func (b *Builder[T]) AddStep(step SequentialStep[T]) *Builder[T] {
+	return b.Add(SequentialStepConfig[T]{Step: step})
+}
+
+// Add adds the step configured by the stepCfg, e.g. to set the fields having no modifier, and validates it.
+func (b *Builder[T]) Add(stepCfg SequentialStepConfig[T]) *Builder[T] {
+	b.v.checkStep(len(b.steps), stepCfg.checked())
+	b.steps = append(b.steps, stepCfg)
+
+	return b
+}
+
+// ContinueOnError sets the SequentialStepConfig.ContinueWorkflowOnError of the last added step.
+func (b *Builder[T]) ContinueOnError() *Builder[T] {
+	if stepCfg := b.last("ContinueOnError"); stepCfg != nil {
+		stepCfg.ContinueWorkflowOnError = true
+	}
+
+	return b
+}
+
+// WithRetry sets the SequentialStepConfig.RetryConfigProvider of the last added step, and validates it, e.g. rejecting
+// the retry configuration of a step which can't be retried.
+func (b *Builder[T]) WithRetry(provider func() (maxAttempts uint, attemptDelay time.Duration)) *Builder[T] {
+	if stepCfg := b.last("WithRetry"); stepCfg != nil {
+		stepCfg.RetryConfigProvider = provider
+		b.v.checkRetry(stepCfg.checked())
+	}
+
+	return b
+}
+
+// WithTimeout sets the SequentialStepConfig.Timeout of the last added step.
+func (b *Builder[T]) WithTimeout(d time.Duration) *Builder[T] {
+	if stepCfg := b.last("WithTimeout"); stepCfg != nil {
+		stepCfg.Timeout = d
+	}
+
+	return b
+}
+
+// Options adds the opts the workflow is built with.
+func (b *Builder[T]) Options(opts ...SequentialOption) *Builder[T] {
+	b.opts = append(b.opts, opts...)
+
+	return b
+}
+
+// Build builds the workflow, unless a problem was found while adding and modifying the steps. The problems found,
+// along with the step modifiers called before any step was added, are joined in the returned error, in which case no
+// workflow is returned.
+func (b *Builder[T]) Build() (*Sequential[T], error) {
+	if err := errors.Join(append(b.errs, b.v.err(b.name))...); err != nil {
+		return nil, err
+	}
+
+	// the workflow gets its own steps, so it's not changed by the builder afterwards.
+	return NewSequential(b.name, append([]SequentialStepConfig[T](nil), b.steps...), b.opts...), nil
+}
+
+// last returns the config of the last added step, or nil, recording the error, if no step was added.
+func (b *Builder[T]) last(modifier string) *SequentialStepConfig[T] {
+	if len(b.steps) == 0 {
+		b.errs = append(b.errs, errNoStep(modifier))
+
+		return nil
+	}
+
+	return &b.steps[len(b.steps)-1]
+}
+
+// PipeBuilder builds a Pipe workflow fluently, and validates its steps as it goes, as described by Pipe.Validate, same
+// as the Builder.
+type PipeBuilder[T any] struct {
+	name  string
+	steps []PipeStepConfig[T]
+	opts  []PipeOption
+	errs  []error
+	v     stepsValidator // collects the problems of the steps, as they are added and modified
+}
+
+// NewPipeBuilder is the PipeBuilder constructor.
+func NewPipeBuilder[T any](name string) *PipeBuilder[T] {
+	return &PipeBuilder[T]{name: name, v: newStepsValidator(0)}
+}
+
+// AddStep adds the step, with the default configuration.
+func (b *PipeBuilder[T]) AddStep(step PipeStep[T]) *PipeBuilder[T] {
+	return b.Add(PipeStepConfig[T]{Step: step})
+}
+
+// Add adds the step configured by the stepCfg, e.g. to set the fields having no modifier, and validates it.
+func (b *PipeBuilder[T]) Add(stepCfg PipeStepConfig[T]) *PipeBuilder[T] {
+	b.v.checkStep(len(b.steps), stepCfg.checked())
+	b.steps = append(b.steps, stepCfg)
+
+	return b
+}
+
+// ContinueOnError sets the PipeStepConfig.ContinueOnError of the last added step.
+func (b *PipeBuilder[T]) ContinueOnError() *PipeBuilder[T] {
+	if stepCfg := b.last("ContinueOnError"); stepCfg != nil {
+		stepCfg.ContinueOnError = true
+	}
+
+	return b
+}
+
+// WithRetry sets the PipeStepConfig.RetryConfigProvider of the last added step, and validates it, same as the Builder.
+func (b *PipeBuilder[T]) WithRetry(provider func() (maxAttempts uint, attemptDelay time.Duration)) *PipeBuilder[T] {
+	if stepCfg := b.last("WithRetry"); stepCfg != nil {
+		stepCfg.RetryConfigProvider = provider
+		b.v.checkRetry(stepCfg.checked())
+	}
+
+	return b
+}
+
+// WithTimeout sets the PipeStepConfig.Timeout of the last added step.
+func (b *PipeBuilder[T]) WithTimeout(d time.Duration) *PipeBuilder[T] {
+	if stepCfg := b.last("WithTimeout"); stepCfg != nil {
+		stepCfg.Timeout = d
+	}
+
+	return b
+}
+
+// Options adds the opts the workflow is built with.
+func (b *PipeBuilder[T]) Options(opts ...PipeOption) *PipeBuilder[T] {
+	b.opts = append(b.opts, opts...)
+
+	return b
+}
+
+// Build builds the workflow, unless a problem was found while adding and modifying the steps, same as Builder.Build.
+func (b *PipeBuilder[T]) Build() (*Pipe[T], error) {
+	if err := errors.Join(append(b.errs, b.v.err(b.name))...); err != nil {
+		return nil, err
+	}
+
+	return NewPipe(b.name, append([]PipeStepConfig[T](nil), b.steps...), b.opts...), nil
+}
+
+// last returns the config of the last added step, or nil, recording the error, if no step was added.
+func (b *PipeBuilder[T]) last(modifier string) *PipeStepConfig[T] {
+	if len(b.steps) == 0 {
+		b.errs = append(b.errs, errNoStep(modifier))
+
+		return nil
+	}
+
+	return &b.steps[len(b.steps)-1]
+}
@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuilderBuild(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step1 := newStepFailedRetryable("step 1", anyErr)
+	step2 := newStepSuccessful("step 2")
+	literal := []SequentialStepConfig[any]{
+		{Step: step1, ContinueWorkflowOnError: true, RetryConfigProvider: defaultRetryConfigProviderTest},
+		{Step: step2, Timeout: time.Second, IdempotencyKey: "key-2"},
+	}
+
+	wf, err := NewBuilder[any]("some-workflow").
+		AddStep(step1).ContinueOnError().WithRetry(defaultRetryConfigProviderTest).
+		Add(SequentialStepConfig[any]{Step: step2, IdempotencyKey: "key-2"}).WithTimeout(time.Second).
+		Build()
+
+	if err != nil {
+		t.Fatalf("The builder returned an unexpected error: %v", err)
+	}
+	if wf.name != "some-workflow" || len(wf.stepsConfig) != len(literal) {
+		t.Fatalf("The built workflow is not as expected: \n actual = %#v, %#v", wf.name, wf.stepsConfig)
+	}
+	for i, expectedOutput := range literal {
+		actualOutput := wf.stepsConfig[i]
+		if actualOutput.Step != expectedOutput.Step ||
+			actualOutput.ContinueWorkflowOnError != expectedOutput.ContinueWorkflowOnError ||
+			actualOutput.Timeout != expectedOutput.Timeout ||
+			actualOutput.IdempotencyKey != expectedOutput.IdempotencyKey ||
+			(actualOutput.RetryConfigProvider == nil) != (expectedOutput.RetryConfigProvider == nil) {
+			t.Errorf("The step config at index: %d is not as expected: \n expected = %#v, \n actual = %#v", i, expectedOutput, actualOutput)
+		}
+	}
+	// the built workflow runs as the one built from the literal.
+	if err = wf.Execute(context.TODO(), nil); !errors.Is(err, anyErr) || step1.invocationCount != 3 || step2.invocationCount != 1 {
+		t.Errorf("The built workflow execution is not as expected: \n actual = %#v, %#v, %#v", err, step1.invocationCount, step2.invocationCount)
+	}
+}
+
+func TestBuilderBuildBehaviourOnInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          *Builder[any]
+		expectedOutput []string // the substrings of the error
+	}{
+		{
+			name:           "a modifier called before any step should fail the build",
+			input:          NewBuilder[any]("some-workflow").ContinueOnError().AddStep(newStepSuccessful("step 1")),
+			expectedOutput: []string{"ContinueOnError is called before any step was added"},
+		},
+		{
+			name: "a retry config on a step which can't be retried should fail the build",
+			input: NewBuilder[any]("some-workflow").
+				AddStep(&dagStepMock{name: "step 1"}).WithRetry(defaultRetryConfigProviderTest),
+			expectedOutput: []string{"the step: step 1 has a RetryConfigProvider, but can't be retried"},
+		},
+		{
+			name:           "the problems should be joined",
+			input:          NewBuilder[any]("some-workflow").WithTimeout(time.Second).AddStep(nil),
+			expectedOutput: []string{"WithTimeout is called before any step was added", "the step at index: 0 is nil"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf, err := tt.input.Build()
+
+			if wf != nil || err == nil {
+				t.Fatalf("The build result is not as expected: \n actual = %#v, %#v", wf, err)
+			}
+			for _, expectedOutput := range tt.expectedOutput {
+				if !strings.Contains(err.Error(), expectedOutput) {
+					t.Errorf("The build error is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestBuilderBehaviourOnValidatingAsItGoes(t *testing.T) {
+	b := NewBuilder[any]("some-workflow").AddStep(&dagStepMock{name: "step 1"})
+	if len(b.v.errs) != 0 {
+		t.Fatalf("The builder recorded an unexpected problem: \n actual = %#v", b.v.errs)
+	}
+
+	// the retry config of a step which can't be retried is rejected by the modifier, before the build.
+	b.WithRetry(defaultRetryConfigProviderTest)
+
+	if len(b.v.errs) != 1 {
+		t.Errorf("The builder did not record the problem on the spot: \n actual = %#v", b.v.errs)
+	}
+	b.AddStep(newStepSuccessful("step 1"))
+	if len(b.v.errs) != 2 {
+		t.Errorf("The builder did not record the duplicated step on the spot: \n actual = %#v", b.v.errs)
+	}
+}
+
+func TestPipeBuilderBuild(t *testing.T) {
+	anyErr := errors.New("any-err")
+	wf, err := NewPipeBuilder[string]("some-workflow").
+		AddStep(newPipeStepAppend("step 1", "-a")).
+		AddStep(newPipeStepFailedRetryable[string]("step 2", anyErr)).ContinueOnError().WithRetry(defaultRetryConfigProviderTest).
+		AddStep(newPipeStepAppend("step 3", "-c")).
+		Build()
+	if err != nil {
+		t.Fatalf("The builder returned an unexpected error: %v", err)
+	}
+
+	// the same as the literal form.
+	expectedOut, expectedErr := NewPipe("some-workflow", []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: newPipeStepFailedRetryable[string]("step 2", anyErr), ContinueOnError: true, RetryConfigProvider: defaultRetryConfigProviderTest},
+		{Step: newPipeStepAppend("step 3", "-c")},
+	}).Execute(context.TODO(), "x")
+	out, err := wf.Execute(context.TODO(), "x")
+
+	if out != expectedOut || !errors.Is(err, anyErr) || err.Error() != expectedErr.Error() {
+		t.Errorf("The built workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", expectedOut, expectedErr, out, err)
+	}
+	if _, err = NewPipeBuilder[string]("some-workflow").WithRetry(defaultRetryConfigProviderTest).Build(); err == nil {
+		t.Errorf("The build error is not as expected: \n expected = %#v, \n actual = %#v", "an error", err)
+	}
+}
+
+func TestPipeBuilderWithTimeout(t *testing.T) {
+	var hasDeadline bool
+	step := &ctxPipeStepMock{name: "step 1", fn: func(ctx context.Context, req string) string {
+		_, hasDeadline = ctx.Deadline()
+
+		return req
+	}}
+
+	wf, err := NewPipeBuilder[string]("some-workflow").AddStep(step).WithTimeout(time.Second).Build()
+	if err != nil {
+		t.Fatalf("The builder returned an unexpected error: %v", err)
+	}
+	_, _ = wf.Execute(context.TODO(), "x")
+
+	if wf.stepsConfig[0].Timeout != time.Second || !hasDeadline {
+		t.Errorf("The step timeout is not as expected: \n expected = %#v, \n actual = %#v, %#v", time.Second, wf.stepsConfig[0].Timeout, hasDeadline)
+	}
+	if _, err = NewPipeBuilder[string]("some-workflow").WithTimeout(time.Second).Build(); err == nil {
+		t.Errorf("The build error is not as expected: \n expected = %#v, \n actual = %#v", "an error", err)
+	}
+}
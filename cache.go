@@ -0,0 +1,66 @@
+package workflow
+
+import "sync"
+
+// Cacheable is implemented by the steps whose result depends only on their request, so that the steps running more
+// than once in a workflow execution, e.g. configured several times, or sharing an expensive computation, run only once
+// for a cache key. The later runs are skipped, and reuse the result of the first one: the Pipe steps reuse its output,
+// while the Sequential and DAG steps are just skipped. Only the succeeded runs are cached, and the cache is dropped at
+// the end of the execution, so it's distinct from the storage based replay, which skips the steps across executions.
+// The steps run concurrently by a DAG are cached only once one of them succeeded, so they may still run concurrently.
+type Cacheable[T any] interface {
+	// CacheKey returns the key identifying the step result for the req. The steps returning the same key share their
+	// result, so the key must identify the computation as well, e.g. "user:" + id. An empty key means the result is
+	// not cached.
+	CacheKey(req T) string
+}
+
+// stepCacheKey returns the cache key of the step result for the req, and reports whether the step result is cached.
+func stepCacheKey[T any](step any, req T) (string, bool) {
+	c, ok := step.(Cacheable[T])
+	if !ok {
+		return "", false
+	}
+	key := c.CacheKey(req)
+
+	return key, key != ""
+}
+
+// pipeResults holds the outputs of the Cacheable steps which succeeded, for a Pipe execution.
+// The Pipe steps run one at a time, so it's not safe for concurrent use.
+type pipeResults[T any] map[string]T
+
+// put caches the output of a step for the key k, allocating the map on the first call, so the executions without
+// Cacheable steps don't pay for it.
+func (r *pipeResults[T]) put(k string, out T) {
+	if *r == nil {
+		*r = make(pipeResults[T])
+	}
+	(*r)[k] = out
+}
+
+// succeededSteps holds the cache keys of the Cacheable steps which succeeded, for a Sequential or DAG execution.
+// It is safe for concurrent use, as the DAG steps run concurrently.
+type succeededSteps struct {
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+// has reports whether a step succeeded for the key k.
+func (c *succeededSteps) has(k string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.keys[k]
+
+	return ok
+}
+
+// add records the success of a step for the key k.
+func (c *succeededSteps) add(k string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keys == nil {
+		c.keys = make(map[string]struct{})
+	}
+	c.keys[k] = struct{}{}
+}
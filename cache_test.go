@@ -0,0 +1,155 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnCacheableSteps(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name                    string
+		input                   []string // the cache keys of the runs of the step
+		err                     error    // the error of the first run
+		expectedInvocationCount int
+	}{
+		{
+			name:                    "a step run twice with the same cache key should execute once",
+			input:                   []string{"key-1", "key-1"},
+			expectedInvocationCount: 1,
+		},
+		{
+			name:                    "a step run with distinct cache keys should execute every time",
+			input:                   []string{"key-1", "key-2"},
+			expectedInvocationCount: 2,
+		},
+		{
+			name:                    "a step with an empty cache key should not be cached",
+			input:                   []string{"", ""},
+			expectedInvocationCount: 2,
+		},
+		{
+			name:                    "a failed run should not be cached",
+			input:                   []string{"key-1", "key-1"},
+			err:                     anyErr,
+			expectedInvocationCount: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var invocationCount int
+			var stepsCfg []SequentialStepConfig[any]
+			for _, key := range tt.input {
+				step := &cacheableStepMock{key: key, execute: func() error {
+					invocationCount++
+					if invocationCount == 1 {
+						return tt.err
+					}
+					return nil
+				}}
+				stepsCfg = append(stepsCfg, SequentialStepConfig[any]{Step: step, ContinueWorkflowOnError: true})
+			}
+
+			NewSequential("some-workflow", stepsCfg).Execute(context.TODO(), nil)
+
+			if invocationCount != tt.expectedInvocationCount {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedInvocationCount, invocationCount)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnCacheableStepsAcrossExecutions(t *testing.T) {
+	var invocationCount int
+	step := &cacheableStepMock{key: "key-1", execute: func() error { invocationCount++; return nil }}
+	wf := NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: step}})
+
+	wf.Execute(context.TODO(), nil)
+	wf.Execute(context.TODO(), nil)
+
+	// the cache is dropped at the end of every execution.
+	if invocationCount != 2 {
+		t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", 2, invocationCount)
+	}
+}
+
+func TestDAGExecuteBehaviourOnCacheableSteps(t *testing.T) {
+	var invocationCount int
+	execute := func() error { invocationCount++; return nil }
+	// the steps share the cache key, and run one after the other.
+	input := []DAGStepConfig[any]{
+		{Step: &cacheableStepMock{name: "a", key: "key-1", execute: execute}},
+		{Step: &cacheableStepMock{name: "b", key: "key-1", execute: execute}, DependsOn: []string{"a"}},
+	}
+	d, err := NewDAG("some-workflow", input)
+	if err != nil {
+		t.Fatalf("The DAG construction failed: %v", err)
+	}
+
+	if err = d.Execute(context.TODO(), nil); err != nil || invocationCount != 1 {
+		t.Errorf("The DAG execution is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", nil, 1, err, invocationCount)
+	}
+}
+
+func TestPipeExecuteBehaviourOnCacheableSteps(t *testing.T) {
+	var invocationCount int
+	cacheable := &cacheablePipeStepMock{key: "key-1", fn: func(req string) string {
+		invocationCount++
+		return req + "-a"
+	}}
+	input := []PipeStepConfig[string]{
+		{Step: cacheable, NameOverride: "step 1"},
+		{Step: newPipeStepAppend("step 2", "-b")},
+		{Step: cacheable, NameOverride: "step 3"},
+	}
+
+	out, err := NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+
+	// the last step is skipped, and outputs the cached output of the first one.
+	if err != nil || out != "x-a" {
+		t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x-a", nil, out, err)
+	}
+	if invocationCount != 1 {
+		t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", 1, invocationCount)
+	}
+}
+
+// MOCKS/STUBS
+
+// cacheableStepMock is a SequentialStep whose result is cached for its key.
+type cacheableStepMock struct {
+	name    string
+	key     string
+	execute func() error
+}
+
+func (s *cacheableStepMock) Name() string {
+	return s.name
+}
+
+func (s *cacheableStepMock) Execute(_ context.Context, _ any) error {
+	return s.execute()
+}
+
+func (s *cacheableStepMock) CacheKey(_ any) string {
+	return s.key
+}
+
+// cacheablePipeStepMock is a PipeStep whose output is cached for its key.
+type cacheablePipeStepMock struct {
+	key string
+	fn  func(req string) string
+}
+
+func (s *cacheablePipeStepMock) Name() string {
+	return "cacheable"
+}
+
+func (s *cacheablePipeStepMock) Execute(_ context.Context, req string) (string, error) {
+	return s.fn(req), nil
+}
+
+func (s *cacheablePipeStepMock) CacheKey(_ string) string {
+	return s.key
+}
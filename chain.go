@@ -0,0 +1,75 @@
+package workflow
+
+import "context"
+
+// ChainStep describes a step of execution, whose output type may differ from its input type.
+type ChainStep[In, Out any] interface {
+	// Name provides the identity of the step.
+	Name() string
+	// Execute is the step central processing unit.
+	// It accepts a context and a request, and returns the request of the next step.
+	Execute(ctx context.Context, req In) (Out, error)
+}
+
+// Chain is a workflow that runs its steps in sequence, every step receiving as request the output of the previous one,
+// like a Pipe whose steps may transform the value from a type to another, e.g. a string into a request and then into a
+// response. It is built step by step, by NewChain and Then, as the steps of different types can't be held by a slice.
+// The Chain has no retry, storage or logging support, which are provided by the Pipe, so a step needing them can be
+// a Pipe, wrapped in a ChainStep.
+// The Chain holds no execution state, so it can be executed concurrently.
+type Chain[In, Out any] struct {
+	name string
+	run  func(ctx context.Context, req In) (Out, error)
+}
+
+// NewChain is the constructor of a Chain whose single step is the step. The next steps are appended by Then.
+func NewChain[In, Out any](name string, step ChainStep[In, Out]) *Chain[In, Out] {
+	return &Chain[In, Out]{
+		name: name,
+		run: func(ctx context.Context, req In) (Out, error) {
+			return executeChainStep(ctx, step, req)
+		},
+	}
+}
+
+// Then returns a new Chain running the steps of c, followed by the step, which receives the output of the last step of c.
+// The c is not changed, so a Chain can be the base of several chains.
+func Then[In, Mid, Out any](c *Chain[In, Mid], step ChainStep[Mid, Out]) *Chain[In, Out] {
+	run := c.run
+
+	return &Chain[In, Out]{
+		name: c.name,
+		run: func(ctx context.Context, req In) (Out, error) {
+			mid, err := run(ctx, req)
+			if err != nil {
+				var out Out
+
+				return out, err
+			}
+
+			return executeChainStep(ctx, step, mid)
+		},
+	}
+}
+
+// Name returns the name of the workflow.
+func (c *Chain[In, Out]) Name() string {
+	return c.name
+}
+
+// Execute passes the ctx and the req to the first step, and the output of every step to the next one, returning the
+// output of the last step.
+// The workflow stops at the first failing step, and returns the error produced by the step, wrapped in a StepError.
+func (c *Chain[In, Out]) Execute(ctx context.Context, req In) (Out, error) {
+	return c.run(ctx, req)
+}
+
+// executeChainStep executes the step, wrapping its error in a StepError.
+func executeChainStep[In, Out any](ctx context.Context, step ChainStep[In, Out], req In) (Out, error) {
+	out, err := step.Execute(ctx, req)
+	if err != nil {
+		return out, StepError{StepName: step.Name(), Attempts: 1, Err: err}
+	}
+
+	return out, nil
+}
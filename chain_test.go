@@ -0,0 +1,80 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestChainExecute(t *testing.T) {
+	anyErr := errors.New("any-err")
+	parse := chainStepMock[string, int]{name: "parse", fn: strconv.Atoi}
+	tests := []struct {
+		name           string
+		input          *Chain[string, string]
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name: "the chained steps should transform the request step by step",
+			input: Then[string, int, string](
+				NewChain[string, int]("some-workflow", parse),
+				chainStepMock[int, string]{name: "double", fn: func(req int) (string, error) { return strconv.Itoa(req * 2), nil }},
+			),
+			expectedOutput: "84",
+		},
+		{
+			name: "a failing step should stop the chain and return its error wrapped in a StepError",
+			input: Then[string, string, string](
+				Then[string, int, string](
+					NewChain[string, int]("some-workflow", parse),
+					chainStepMock[int, string]{name: "fail", fn: func(req int) (string, error) { return "", anyErr }},
+				),
+				chainStepMock[string, string]{name: "unreachable", fn: func(req string) (string, error) { return "unreachable", nil }},
+			),
+			expectedOutput: "",
+			expectedErr:    StepError{StepName: "fail", Attempts: 1, Err: anyErr},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualOutput, err := tt.input.Execute(context.TODO(), "42")
+
+			if actualOutput != tt.expectedOutput {
+				t.Errorf("The output is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, actualOutput)
+			}
+			if !errors.Is(err, tt.expectedErr) || (tt.expectedErr == nil && err != nil) {
+				t.Errorf("The error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil && !errors.Is(err, anyErr) {
+				t.Errorf("The step error is not wrapped: \n actual = %#v", err)
+			}
+		})
+	}
+}
+
+func TestChainAsPipeStep(t *testing.T) {
+	chain := NewChain[string, string]("nested", chainStepMock[string, string]{name: "append", fn: func(req string) (string, error) { return req + "-a", nil }})
+	p := NewPipe("some-workflow", []PipeStepConfig[string]{{Step: chain}, {Step: newPipeStepAppend("step 2", "-b")}})
+
+	actualOutput, err := p.Execute(context.TODO(), "x")
+
+	if actualOutput != "x-a-b" || err != nil {
+		t.Errorf("The output is not as expected: \n expected = %#v, \n actual = %#v, %v", "x-a-b", actualOutput, err)
+	}
+}
+
+// MOCKS/STUBS
+type chainStepMock[In, Out any] struct {
+	name string
+	fn   func(req In) (Out, error)
+}
+
+func (s chainStepMock[In, Out]) Name() string {
+	return s.name
+}
+
+func (s chainStepMock[In, Out]) Execute(_ context.Context, req In) (Out, error) {
+	return s.fn(req)
+}
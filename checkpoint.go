@@ -0,0 +1,124 @@
+package workflow
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCheckpoint is returned by Resume for a token which is not valid, or which doesn't match the workflow steps.
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint token")
+
+// checkpointToken is the position of a paused execution, encoded as the token returned by ExecuteUntilCheckpoint and
+// Resume: the base64 URL encoding, without padding, of the JSON object
+//
+//	{"workflow":"<workflow name>","step":"<checkpoint step name>","next":<index of the step to resume from>}
+type checkpointToken struct {
+	Workflow string `json:"workflow"`
+	Step     string `json:"step"`
+	Next     int    `json:"next"`
+}
+
+// encode returns the token.
+func (t checkpointToken) encode() string {
+	b, _ := json.Marshal(t)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCheckpointToken decodes the token returned by ExecuteUntilCheckpoint or Resume.
+func decodeCheckpointToken(token string) (checkpointToken, error) {
+	var t checkpointToken
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(b, &t)
+	}
+	if err != nil {
+		return t, fmt.Errorf("%w: %v", ErrInvalidCheckpoint, err)
+	}
+
+	return t, nil
+}
+
+// checkpoints holds the state of an execution pausing at the checkpoint steps.
+type checkpoints struct {
+	from   *checkpointToken // the checkpoint the execution resumes from, nil for a new execution
+	paused *checkpointToken // the checkpoint the execution paused at, nil if it ran to the end
+}
+
+// paused reports whether the execution paused at a checkpoint.
+func (e *execution) paused() bool {
+	return e.checkpoints != nil && e.checkpoints.paused != nil
+}
+
+// ExecuteUntilCheckpoint executes the workflow, as described by Execute, but pauses it once a step configured with
+// SequentialStepConfig.Checkpoint succeeds, unless it's the last step, e.g. to split a long workflow across processes.
+// The returned token identifies the paused execution, and is passed to Resume to run the steps following the
+// checkpoint. An empty token means the workflow ran to the end, or stopped on a failure.
+// The errors of the steps continuing the workflow on error, which ran before the checkpoint, are returned along with
+// the token. The stored results of the paused execution are not cleared, and the end hooks are called on pause as well.
+// The checkpoints are honored only by ExecuteUntilCheckpoint and Resume, not by the other Execute methods, nor by the
+// nested workflows, and not for the steps run concurrently by WithStepConcurrency.
+func (s *Sequential[T]) ExecuteUntilCheckpoint(ctx context.Context, req T) (string, error) {
+	return s.executeCheckpoints(ctx, req, &checkpoints{})
+}
+
+// Resume executes the workflow from the step following the checkpoint of the token returned by ExecuteUntilCheckpoint
+// or by a previous Resume, possibly in another process, until the next checkpoint, as described by
+// ExecuteUntilCheckpoint. The req is passed again, as the token doesn't hold it, e.g. rebuilt from its persisted state.
+// It fails with ErrInvalidCheckpoint, before running any step, if the token is not valid, or if it's not the one of a
+// checkpoint step of the workflow, e.g. because the steps changed since the execution paused.
+func (s *Sequential[T]) Resume(ctx context.Context, token string, req T) (string, error) {
+	t, err := decodeCheckpointToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	return s.executeCheckpoints(ctx, req, &checkpoints{from: &t})
+}
+
+// executeCheckpoints executes the workflow with the state of the checkpoints c, and returns the token of the checkpoint
+// it paused at, if any.
+func (s *Sequential[T]) executeCheckpoints(ctx context.Context, req T, c *checkpoints) (string, error) {
+	ctx = s.execID.context(ctx)
+	err := s.end.end(ctx, wrapWorkflowErr(s.wrapErrs, s.name, s.execute(ctx, req, nil, execution{checkpoints: c})))
+	if c.paused == nil {
+		return "", err
+	}
+
+	return c.paused.encode(), err
+}
+
+// firstStep returns the index of the first step of the execution, among the stepsCfg: the step following the
+// checkpoint the exec resumes from, if any. It fails if the checkpoint is not the one of a checkpoint step of the
+// workflow.
+func (s *Sequential[T]) firstStep(stepsCfg []SequentialStepConfig[T], exec *execution) (int, error) {
+	if exec.checkpoints == nil || exec.checkpoints.from == nil {
+		return 0, nil
+	}
+	t := exec.checkpoints.from
+	if t.Workflow != s.name || t.Next <= 0 || t.Next >= len(stepsCfg) {
+		return 0, fmt.Errorf("%w: it's not the one of a step of workflow: %s", ErrInvalidCheckpoint, s.name)
+	}
+	if stepCfg := stepsCfg[t.Next-1]; stepCfg.Step == nil || !stepCfg.Checkpoint || stepCfg.name() != t.Step {
+		return 0, fmt.Errorf("%w: the step at index: %d is not the checkpoint step: %s", ErrInvalidCheckpoint, t.Next-1, t.Step)
+	}
+
+	return t.Next, nil
+}
+
+// pause reports whether the exec pauses after the succeeded step found at the index i of the stepsCfg, which it does
+// for a checkpoint step, unless it's the last one, and records the checkpoint.
+func (s *Sequential[T]) pause(ctx context.Context, stepsCfg []SequentialStepConfig[T], i int, exec *execution) bool {
+	stepCfg := stepsCfg[i]
+	if exec.checkpoints == nil || !stepCfg.Checkpoint || i == len(stepsCfg)-1 {
+		return false
+	}
+	stepName := stepCfg.name()
+	exec.checkpoints.paused = &checkpointToken{Workflow: s.name, Step: stepName, Next: i + 1}
+	s.log.print(ctx, LevelInfo, concatStr("pausing workflow: ", s.name, ", at checkpoint step: ", stepName))
+
+	return true
+}
@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSequentialExecuteUntilCheckpointAndResume(t *testing.T) {
+	// every execution builds its own steps, as if it ran in another process.
+	newWorkflow := func() (*Sequential[any], []*stepMock) {
+		steps := []*stepMock{
+			newStepSuccessful("step 1"),
+			newStepSuccessful("step 2"),
+			newStepSuccessful("step 3"),
+			newStepSuccessful("step 4"),
+			newStepSuccessful("step 5"),
+		}
+		input := []SequentialStepConfig[any]{
+			{Step: steps[0]},
+			{Step: steps[1], Checkpoint: true},
+			{Step: steps[2]},
+			{Step: steps[3], Checkpoint: true},
+			{Step: steps[4]},
+		}
+
+		return NewSequential("some-workflow", input, nil), steps
+	}
+	invocations := func(steps []*stepMock) []int {
+		counts := make([]int, len(steps))
+		for i, step := range steps {
+			counts[i] = step.invocationCount
+		}
+		return counts
+	}
+
+	wf, steps := newWorkflow()
+	token, err := wf.ExecuteUntilCheckpoint(context.TODO(), nil)
+	if err != nil || token == "" {
+		t.Fatalf("The workflow did not pause at the first checkpoint: \n token = %#v, \n err = %v", token, err)
+	}
+	if actualOutput, expectedOutput := invocations(steps), []int{1, 1, 0, 0, 0}; !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps invocation counts are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+
+	wf, steps = newWorkflow()
+	token, err = wf.Resume(context.TODO(), token, nil)
+	if err != nil || token == "" {
+		t.Fatalf("The workflow did not pause at the second checkpoint: \n token = %#v, \n err = %v", token, err)
+	}
+	if actualOutput, expectedOutput := invocations(steps), []int{0, 0, 1, 1, 0}; !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps invocation counts are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+
+	wf, steps = newWorkflow()
+	token, err = wf.Resume(context.TODO(), token, nil)
+	if err != nil || token != "" {
+		t.Fatalf("The workflow did not run to the end: \n token = %#v, \n err = %v", token, err)
+	}
+	if actualOutput, expectedOutput := invocations(steps), []int{0, 0, 0, 0, 1}; !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps invocation counts are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestSequentialExecuteUntilCheckpointToken(t *testing.T) {
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1"), Checkpoint: true},
+		{Step: newStepSuccessful("step 2")},
+	}
+
+	token, _ := NewSequential("some-workflow", input, nil).ExecuteUntilCheckpoint(context.TODO(), nil)
+
+	actualOutput, err := base64.RawURLEncoding.DecodeString(token)
+	expectedOutput := `{"workflow":"some-workflow","step":"step 1","next":1}`
+	if err != nil || string(actualOutput) != expectedOutput {
+		t.Errorf("The token is not as expected: \n expected = %#v, \n actual = %#v, \n err = %v", expectedOutput, string(actualOutput), err)
+	}
+}
+
+func TestSequentialExecuteUntilCheckpointBehaviourOnNoPause(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name          string
+		input         []SequentialStepConfig[any]
+		expectedError error
+	}{
+		{
+			name:  "a last checkpoint step should not pause the workflow",
+			input: []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}, {Step: newStepSuccessful("step 2"), Checkpoint: true}},
+		},
+		{
+			name: "a failed checkpoint step should not pause the workflow",
+			input: []SequentialStepConfig[any]{
+				{Step: newStepFailedNonRetryable("step 1", anyErr), Checkpoint: true, ContinueWorkflowOnError: true},
+				{Step: newStepSuccessful("step 2")},
+			},
+			expectedError: anyErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := NewSequential("some-workflow", tt.input, nil).ExecuteUntilCheckpoint(context.TODO(), nil)
+
+			if token != "" {
+				t.Errorf("The workflow paused unexpectedly: \n token = %#v", token)
+			}
+			if !errors.Is(err, tt.expectedError) || (tt.expectedError == nil && err != nil) {
+				t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedError, err)
+			}
+			if last := tt.input[len(tt.input)-1].Step.(*stepMock); last.invocationCount != 1 {
+				t.Errorf("The last step did not run")
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnCheckpoint(t *testing.T) {
+	step := newStepSuccessful("step 2")
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1"), Checkpoint: true},
+		{Step: step},
+	}
+
+	err := NewSequential("some-workflow", input, nil).Execute(context.TODO(), nil)
+
+	// Execute doesn't honor the checkpoints.
+	if err != nil || step.invocationCount != 1 {
+		t.Errorf("The workflow did not run to the end: \n err = %v", err)
+	}
+}
+
+func TestSequentialResumeBehaviourOnInvalidToken(t *testing.T) {
+	encode := func(t checkpointToken) string { return t.encode() }
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "a token which is not base64 should be rejected", input: "not a token!"},
+		{name: "a token which is not JSON should be rejected", input: base64.RawURLEncoding.EncodeToString([]byte("{"))},
+		{name: "the token of another workflow should be rejected", input: encode(checkpointToken{Workflow: "other", Step: "step 1", Next: 1})},
+		{name: "a token out of the steps should be rejected", input: encode(checkpointToken{Workflow: "some-workflow", Step: "step 2", Next: 2})},
+		{name: "the token of a renamed step should be rejected", input: encode(checkpointToken{Workflow: "some-workflow", Step: "old", Next: 1})},
+		{name: "the token of a step which is not a checkpoint should be rejected", input: encode(checkpointToken{Workflow: "some-workflow", Step: "step 0", Next: 0})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := newStepSuccessful("step 2")
+			input := []SequentialStepConfig[any]{
+				{Step: newStepSuccessful("step 1"), Checkpoint: true},
+				{Step: step},
+			}
+
+			token, err := NewSequential("some-workflow", input, nil).Resume(context.TODO(), tt.input, nil)
+
+			if !errors.Is(err, ErrInvalidCheckpoint) || token != "" {
+				t.Errorf("The workflow result is not as expected: \n expected = %#v, \n actual = %#v, %#v", ErrInvalidCheckpoint, token, err)
+			}
+			if step.invocationCount != 0 {
+				t.Errorf("The workflow ran a step for an invalid token")
+			}
+		})
+	}
+}
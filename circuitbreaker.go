@@ -0,0 +1,136 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped in a StepError, by the steps not executed because their CircuitBreaker is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState string
+
+const (
+	// CircuitClosed lets the step execute, and counts its consecutive failures.
+	CircuitClosed CircuitState = "CLOSED"
+	// CircuitOpen short-circuits the step with ErrCircuitOpen, until the cool-down elapses.
+	CircuitOpen CircuitState = "OPEN"
+	// CircuitHalfOpen lets a single trial execution of the step through, once the cool-down elapsed: its success closes
+	// the circuit, and its failure opens it again.
+	CircuitHalfOpen CircuitState = "HALF_OPEN"
+)
+
+// CircuitBreaker stops executing a step for a cool-down, once the step failed a number of consecutive times, e.g. to
+// stop hammering a flaky downstream. It is set on the step config, as SequentialStepConfig.CircuitBreaker, and its state
+// outlives the workflow, so a single CircuitBreaker can be shared by the workflows built for every run, and by the
+// steps calling the same downstream. It is safe for concurrent use.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold uint          // the consecutive failures opening the circuit
+	coolDown  time.Duration // the time the circuit stays open
+	clock     Clock         // the system time is used if nil
+	state     CircuitState
+	failures  uint      // the consecutive failures, while closed
+	openedAt  time.Time // the time the circuit opened
+	trial     bool      // a trial execution is in flight, while half open
+}
+
+// CircuitBreakerOption configures a CircuitBreaker.
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// WithCircuitBreakerClock sets the Clock measuring the cool-down, e.g. to test it without waiting.
+// If not provided, or if nil is provided, the system time is used.
+func WithCircuitBreakerClock(clock Clock) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.clock = clock
+	}
+}
+
+// NewCircuitBreaker is the CircuitBreaker constructor. The circuit opens once the step failed threshold consecutive
+// execution attempts, and stays open for the coolDown. A threshold of 0 is replaced by 1.
+// Every error of an execution attempt counts as a failure, the retries included.
+func NewCircuitBreaker(threshold uint, coolDown time.Duration, opts ...CircuitBreakerOption) *CircuitBreaker {
+	if threshold == 0 {
+		threshold = 1
+	}
+	b := CircuitBreaker{threshold: threshold, coolDown: coolDown, state: CircuitClosed}
+	for _, opt := range opts {
+		opt(&b)
+	}
+
+	return &b
+}
+
+// State returns the current state of the circuit. An open circuit whose cool-down elapsed is reported as half open.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && now(b.clock).Sub(b.openedAt) >= b.coolDown {
+		return CircuitHalfOpen
+	}
+
+	return b.state
+}
+
+// allow reports whether an execution attempt of the step can run, by returning nil, or ErrCircuitOpen otherwise.
+// Once the cool-down elapsed, it lets a single trial attempt through, and reports it as the trial, to be passed to
+// record. A nil CircuitBreaker allows every attempt.
+func (b *CircuitBreaker) allow() (bool, error) {
+	if b == nil {
+		return false, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && now(b.clock).Sub(b.openedAt) >= b.coolDown {
+		b.state = CircuitHalfOpen
+	}
+	switch {
+	case b.state == CircuitClosed:
+		return false, nil
+	case b.state == CircuitHalfOpen && !b.trial:
+		b.trial = true
+
+		return true, nil
+	default:
+		return false, ErrCircuitOpen
+	}
+}
+
+// record updates the circuit with the err of an execution attempt allowed by allow, the trial being the one reported
+// by allow. Only the trial decides the state of a half open circuit, so the result of an attempt allowed before the
+// circuit opened, and finishing after, e.g. by another step sharing the CircuitBreaker, is ignored.
+func (b *CircuitBreaker) record(trial bool, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if trial {
+		b.trial = false
+	} else if b.state != CircuitClosed {
+		return
+	}
+	if err == nil {
+		b.state, b.failures = CircuitClosed, 0
+
+		return
+	}
+	b.failures++
+	if trial || b.failures >= b.threshold {
+		b.state, b.openedAt, b.failures = CircuitOpen, now(b.clock), 0
+	}
+}
+
+// checkCircuit returns ErrCircuitOpen, and logs it, if the circuit of the step is open, otherwise it reports whether the
+// attempt is the trial of a half open circuit.
+func checkCircuit(ctx context.Context, log logger, breaker *CircuitBreaker, stepName string, attempt uint) (bool, error) {
+	trial, err := breaker.allow()
+	if err != nil {
+		log.printStep(ctx, LevelError, concatStr("step: ", stepName, " is not executed, its circuit is open"), stepName, attempt)
+	}
+
+	return trial, err
+}
@@ -0,0 +1,125 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/silviutanasa/workflow/workflowtest"
+)
+
+func TestCircuitBreakerStateTransitions(t *testing.T) {
+	anyErr := errors.New("any-err")
+	clock := workflowtest.NewFakeClock(time.Now())
+	b := NewCircuitBreaker(2, time.Minute, WithCircuitBreakerClock(clock))
+	assertState := func(expectedOutput CircuitState) {
+		t.Helper()
+		if actualOutput := b.State(); actualOutput != expectedOutput {
+			t.Errorf("The circuit state is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+		}
+	}
+	// assertAllow returns whether the allowed attempt is the trial, to be passed to record.
+	assertAllow := func(expectedOutput error) bool {
+		t.Helper()
+		trial, actualOutput := b.allow()
+		if actualOutput != expectedOutput {
+			t.Errorf("The circuit decision is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+		}
+		return trial
+	}
+
+	// a failure below the threshold, or a success, keeps the circuit closed.
+	b.record(assertAllow(nil), anyErr)
+	b.record(assertAllow(nil), nil)
+	b.record(assertAllow(nil), anyErr)
+	assertState(CircuitClosed)
+
+	// the consecutive failures reaching the threshold open the circuit.
+	b.record(assertAllow(nil), anyErr)
+	assertState(CircuitOpen)
+	assertAllow(ErrCircuitOpen)
+
+	// once the cool-down elapsed, a single trial is let through, and its failure opens the circuit again.
+	clock.Advance(time.Minute)
+	assertState(CircuitHalfOpen)
+	trial := assertAllow(nil)
+	assertAllow(ErrCircuitOpen)
+	b.record(trial, anyErr)
+	assertState(CircuitOpen)
+	assertAllow(ErrCircuitOpen)
+
+	// a succeeded trial closes the circuit.
+	clock.Advance(time.Minute)
+	b.record(assertAllow(nil), nil)
+	assertState(CircuitClosed)
+	assertAllow(nil)
+}
+
+func TestCircuitBreakerBehaviourOnConcurrentAttempts(t *testing.T) {
+	anyErr := errors.New("any-err")
+	clock := workflowtest.NewFakeClock(time.Now())
+	b := NewCircuitBreaker(1, time.Minute, WithCircuitBreakerClock(clock))
+
+	// the slow attempt is allowed while the circuit is closed, and finishes after another attempt opened it.
+	slow, _ := b.allow()
+	failing, _ := b.allow()
+	b.record(failing, anyErr)
+	clock.Advance(time.Minute)
+	trial, err := b.allow()
+	if !trial || err != nil {
+		t.Fatalf("The half open circuit did not let the trial through: \n trial = %#v, \n err = %v", trial, err)
+	}
+	b.record(slow, nil)
+
+	// the success of the slow attempt doesn't close the circuit, whose state is decided by the trial in flight.
+	if actualOutput := b.State(); actualOutput != CircuitHalfOpen {
+		t.Errorf("The circuit state is not as expected: \n expected = %#v, \n actual = %#v", CircuitHalfOpen, actualOutput)
+	}
+	if _, err = b.allow(); err != ErrCircuitOpen {
+		t.Errorf("The circuit let an attempt through while the trial is in flight: \n err = %v", err)
+	}
+	b.record(trial, anyErr)
+	if actualOutput := b.State(); actualOutput != CircuitOpen {
+		t.Errorf("The circuit state is not as expected: \n expected = %#v, \n actual = %#v", CircuitOpen, actualOutput)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnCircuitBreaker(t *testing.T) {
+	anyErr := errors.New("any-err")
+	clock := workflowtest.NewAutoAdvancingFakeClock(time.Now())
+	// the breaker outlives the workflows, built for every run.
+	b := NewCircuitBreaker(2, time.Hour, WithCircuitBreakerClock(clock))
+	step := newStepFailedRetryable("step 1", anyErr)
+	run := func() error {
+		input := []SequentialStepConfig[any]{
+			{Step: step, RetryConfigProvider: defaultRetryConfigProviderTest, CircuitBreaker: b},
+		}
+
+		return NewSequential("some-workflow", input, WithClock(clock)).Execute(context.TODO(), nil)
+	}
+
+	// the second attempt opens the circuit, so the last retry is short-circuited.
+	err := run()
+	if !errors.Is(err, anyErr) || !errors.Is(err, ErrCircuitOpen) || step.invocationCount != 2 {
+		t.Errorf("The first run is not as expected: \n actual = %#v, %#v", err, step.invocationCount)
+	}
+	err = run()
+	if !errors.Is(err, ErrCircuitOpen) || step.invocationCount != 2 {
+		t.Errorf("The second run is not as expected: \n actual = %#v, %#v", err, step.invocationCount)
+	}
+}
+
+func TestPipeExecuteBehaviourOnCircuitBreaker(t *testing.T) {
+	anyErr := errors.New("any-err")
+	b := NewCircuitBreaker(1, time.Hour)
+	step := newPipeStepFunc("step 1", func(req string) (string, error) { return "", anyErr })
+	input := []PipeStepConfig[string]{{Step: step, CircuitBreaker: b}}
+
+	NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+	out, err := NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+
+	if !errors.Is(err, ErrCircuitOpen) || step.invocationCount != 1 || out != "" {
+		t.Errorf("The workflow result is not as expected: \n actual = %#v, %#v, %#v", out, err, step.invocationCount)
+	}
+}
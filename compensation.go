@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+)
+
+// Compensable is implemented by the steps able to undo their effects.
+// When the compensation is enabled by WithCompensation, and a step failure stops the Sequential workflow, the previously
+// succeeded steps implementing Compensable are compensated in reverse order.
+type Compensable[T any] interface {
+	Compensate(ctx context.Context, req T) error
+}
+
+// CompensationError holds the errors returned by the steps compensation, so they can be told apart from the steps errors,
+// by using errors.As against the error returned by the workflow.
+type CompensationError struct {
+	Errs []error
+}
+
+// Error implements the error interface.
+func (e *CompensationError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return "compensation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the compensation errors, so they can be checked using errors.Is or errors.As.
+func (e *CompensationError) Unwrap() []error {
+	return e.Errs
+}
+
+// WithCompensation enables the compensation of the succeeded steps implementing the Compensable interface, when a step
+// failure stops the workflow. A failing step configured with ContinueWorkflowOnError doesn't trigger the compensation.
+// The steps skipped because they already succeeded for the correlation ID are compensated as well, while the Cacheable
+// steps skipped because they already succeeded in the execution, for their cache key, are compensated once, for the
+// occurrence which ran.
+func WithCompensation() SequentialOption {
+	return func(o *sequentialOptions) {
+		o.compensation = true
+	}
+}
+
+// compensate calls Compensate, in reverse order, on the succeeded steps implementing the Compensable interface.
+// The succeeded holds the indexes of the succeeded steps from the stepsCfg, in execution order.
+// The compensation goes on even if a step compensation fails, and all the compensation errors are returned in a CompensationError.
+func (s *Sequential[T]) compensate(ctx context.Context, req T, stepsCfg []SequentialStepConfig[T], succeeded []int) error {
+	var errs []error
+	for i := len(succeeded) - 1; i >= 0; i-- {
+		stepCfg := stepsCfg[succeeded[i]]
+		c, ok := stepCfg.Step.(Compensable[T])
+		if !ok {
+			continue
+		}
+		stepName := stepCfg.name()
+		log := s.log.override(stepCfg.Logger)
+		if err := c.Compensate(ctx, stepCfg.request(ctx, req)); err != nil {
+			log.printStep(ctx, LevelError, concatStr(failed, " compensating step: ", stepName, ", err: ", err.Error()), stepName, 0)
+			errs = append(errs, err)
+
+			continue
+		}
+		log.printStep(ctx, LevelInfo, concatStr(succeed, " compensating step: ", stepName), stepName, 0)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &CompensationError{Errs: errs}
+}
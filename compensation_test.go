@@ -0,0 +1,124 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnCompensation(t *testing.T) {
+	anyErr := errors.New("any-err")
+	compensationErr := errors.New("compensation-err")
+	tests := []struct {
+		name                 string
+		opts                 []SequentialOption
+		lastStepContinues    bool
+		compensationFailsFor string
+		expectedCompensated  []string
+		expectedErrs         []error
+	}{
+		{
+			name:                "a workflow with the compensation enabled should compensate the succeeded steps in reverse order",
+			opts:                []SequentialOption{WithCompensation()},
+			expectedCompensated: []string{"step 3", "step 1"},
+			expectedErrs:        []error{anyErr},
+		},
+		{
+			name:                "a workflow without the compensation enabled should not compensate the succeeded steps",
+			expectedCompensated: nil,
+			expectedErrs:        []error{anyErr},
+		},
+		{
+			name:                "a workflow with the compensation enabled should not compensate if the failing step doesn't stop it",
+			opts:                []SequentialOption{WithCompensation()},
+			lastStepContinues:   true,
+			expectedCompensated: nil,
+			expectedErrs:        []error{anyErr},
+		},
+		{
+			name:                 "a workflow with a failing compensation should go on compensating and return the compensation error",
+			opts:                 []SequentialOption{WithCompensation()},
+			compensationFailsFor: "step 3",
+			expectedCompensated:  []string{"step 3", "step 1"},
+			expectedErrs:         []error{anyErr, compensationErr},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var compensated []string
+			newStep := func(name string) *compensableStepMock {
+				s := &compensableStepMock{stepMock: stepMock{name: name}, compensated: &compensated}
+				if name == tt.compensationFailsFor {
+					s.compensateErr = compensationErr
+				}
+				return s
+			}
+			input := []SequentialStepConfig[any]{
+				{Step: newStep("step 1")},
+				{Step: newStepSuccessful("step 2")},
+				{Step: newStep("step 3")},
+				{Step: newStepFailedNonRetryable("step 4", anyErr), ContinueWorkflowOnError: tt.lastStepContinues},
+			}
+
+			err := NewSequential("some-workflow", input, tt.opts...).Execute(context.TODO(), nil)
+
+			if !reflect.DeepEqual(compensated, tt.expectedCompensated) {
+				t.Errorf("The compensated steps are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedCompensated, compensated)
+			}
+			for _, expectedErr := range tt.expectedErrs {
+				if !errors.Is(err, expectedErr) {
+					t.Errorf("The workflow error does not wrap the expected error: \n expected = %#v, \n actual = %#v", expectedErr, err)
+				}
+			}
+			var cErr *CompensationError
+			if errors.As(err, &cErr) != (tt.compensationFailsFor != "") {
+				t.Errorf("The workflow error holds an unexpected CompensationError: \n actual = %#v", err)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnCompensationOfCacheableSteps(t *testing.T) {
+	var compensated []string
+	step := &cacheableCompensableStepMock{
+		compensableStepMock: compensableStepMock{stepMock: stepMock{name: "step 1"}, compensated: &compensated},
+		key:                 "some-key",
+	}
+	input := []SequentialStepConfig[any]{
+		{Step: step},
+		{Step: step, NameOverride: "step 1 again"},
+		{Step: newStepFailedNonRetryable("step 2", errors.New("any-err"))},
+	}
+
+	_ = NewSequential("some-workflow", input, WithCompensation()).Execute(context.TODO(), nil)
+
+	// the step reached twice ran once, so its effect is undone once.
+	expectedOutput := []string{"step 1"}
+	if step.invocationCount != 1 || !reflect.DeepEqual(compensated, expectedOutput) {
+		t.Errorf("The compensated steps are not as expected: \n expected = %#v, \n actual = %#v, \n invocations = %d", expectedOutput, compensated, step.invocationCount)
+	}
+}
+
+// MOCKS/STUBS
+type compensableStepMock struct {
+	stepMock
+	compensateErr error
+	compensated   *[]string
+}
+
+func (c *compensableStepMock) Compensate(ctx context.Context, request any) error {
+	*c.compensated = append(*c.compensated, c.name)
+
+	return c.compensateErr
+}
+
+// cacheableCompensableStepMock is a compensableStepMock whose result is cached for its key.
+type cacheableCompensableStepMock struct {
+	compensableStepMock
+	key string
+}
+
+func (c *cacheableCompensableStepMock) CacheKey(_ any) string {
+	return c.key
+}
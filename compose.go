@@ -0,0 +1,41 @@
+package workflow
+
+import "context"
+
+// AsSequentialStep adapts the pipe, transforming a value of type V, to the SequentialStep interface, so it can be a step
+// of a Sequential workflow, e.g. a Pipe normalizing a field of the req shared by the steps, without a wrapper dropping
+// the pipe output. The pipe receives the value returned by the input for the req, and its output is passed, along with
+// the req, to the applyResult, e.g. to set it in the req, once the pipe succeeds. The failed pipe applies no result.
+// The adapted step is named after the pipe, and is retried as the pipe is, if it implements the ErrorRetryDecider or the
+// RetryDecider interface.
+func AsSequentialStep[T, V any](pipe PipeStep[V], input func(req T) V, applyResult func(req T, out V)) SequentialStep[T] {
+	return &pipeStepAdapter[T, V]{pipe: pipe, input: input, applyResult: applyResult}
+}
+
+// pipeStepAdapter is the SequentialStep built by AsSequentialStep.
+type pipeStepAdapter[T, V any] struct {
+	pipe        PipeStep[V]
+	input       func(req T) V
+	applyResult func(req T, out V)
+}
+
+// Name implements the SequentialStep interface.
+func (a *pipeStepAdapter[T, V]) Name() string {
+	return a.pipe.Name()
+}
+
+// Execute implements the SequentialStep interface, applying the pipe output to the req.
+func (a *pipeStepAdapter[T, V]) Execute(ctx context.Context, req T) error {
+	out, err := a.pipe.Execute(ctx, a.input(req))
+	if err != nil {
+		return err
+	}
+	a.applyResult(req, out)
+
+	return nil
+}
+
+// RetryableError implements the ErrorRetryDecider interface, by deferring to the adapted pipe.
+func (a *pipeStepAdapter[T, V]) RetryableError(err error) bool {
+	return canRetry(a.pipe, err)
+}
@@ -0,0 +1,104 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAsSequentialStep(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name           string
+		input          []PipeStepConfig[string]
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name: "the pipe output should be applied to the req",
+			input: []PipeStepConfig[string]{
+				{Step: newPipeStepFunc("trim", func(req string) (string, error) { return strings.TrimSpace(req), nil })},
+				{Step: newPipeStepFunc("upper", func(req string) (string, error) { return strings.ToUpper(req), nil })},
+			},
+			expectedOutput: "SOME STREET",
+		},
+		{
+			name: "the failed pipe output should not be applied to the req",
+			input: []PipeStepConfig[string]{
+				{Step: newPipeStepFunc("trim", func(req string) (string, error) { return strings.TrimSpace(req), nil })},
+				{Step: newPipeStepFailedNonRetryable[string]("upper", anyErr)},
+			},
+			expectedOutput: "  some street ",
+			expectedErr:    anyErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipe := NewPipe("normalize-street", tt.input)
+			step := AsSequentialStep[*address, string](
+				pipe,
+				func(req *address) string { return req.street },
+				func(req *address, out string) { req.street = out },
+			)
+			// the next step sees the applied output.
+			var actualOutput string
+			next := &addressStepMock{name: "next", fn: func(req *address) { actualOutput = req.street }}
+			input := []SequentialStepConfig[*address]{{Step: step, ContinueWorkflowOnError: true}, {Step: next}}
+
+			err := NewSequential("some-workflow", input).Execute(context.TODO(), &address{street: "  some street "})
+
+			if !errors.Is(err, tt.expectedErr) || (tt.expectedErr == nil && err != nil) {
+				t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedErr, err)
+			}
+			if actualOutput != tt.expectedOutput {
+				t.Errorf("The applied output is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, actualOutput)
+			}
+			if step.Name() != "normalize-street" {
+				t.Errorf("The step name is not as expected: \n expected = %#v, \n actual = %#v", "normalize-street", step.Name())
+			}
+		})
+	}
+}
+
+func TestAsSequentialStepBehaviourOnRetry(t *testing.T) {
+	anyErr := errors.New("any-err")
+	pipe := newPipeStepFailedRetryable[string]("retryable-pipe", anyErr)
+	step := AsSequentialStep[*address, string](
+		pipe,
+		func(req *address) string { return req.street },
+		func(req *address, out string) { req.street = out },
+	)
+	input := []SequentialStepConfig[*address]{{Step: step, RetryConfigProvider: defaultRetryConfigProviderTest}}
+
+	err := NewSequential("some-workflow", input).Execute(context.TODO(), &address{})
+
+	// the adapted step is retried as the pipe is.
+	maxAttempts, _ := defaultRetryConfigProviderTest()
+	if !errors.Is(err, anyErr) || pipe.invocationCount != int(maxAttempts)+1 {
+		t.Errorf("The adapted step was not retried: \n expected invocations = %#v, \n actual = %#v, \n err = %v", maxAttempts+1, pipe.invocationCount, err)
+	}
+}
+
+// MOCKS/STUBS
+
+// address is the req shared by the steps, whose street is normalized by a Pipe.
+type address struct {
+	street string
+}
+
+// addressStepMock is a SequentialStep running its fn.
+type addressStepMock struct {
+	name string
+	fn   func(req *address)
+}
+
+func (s *addressStepMock) Name() string {
+	return s.name
+}
+
+func (s *addressStepMock) Execute(_ context.Context, req *address) error {
+	s.fn(req)
+
+	return nil
+}
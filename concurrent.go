@@ -0,0 +1,104 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+)
+
+// WithStepConcurrency runs up to n steps at the same time, among a contiguous run of the steps configured with
+// SequentialStepConfig.ContinueWorkflowOnError, e.g. a cluster of independent best effort side effects, instead of
+// running them one after the other. The steps around the cluster keep the sequential semantics: the cluster starts
+// once the previous step is done, and the next step starts once all the steps of the cluster are done. The errors of
+// all the failing steps of the cluster are returned, in the config order. The ctx is checked once, before the cluster.
+// A n lower or equal to 1 runs all the steps one after the other, which is the default.
+// The steps of a cluster, and so the hooks registered by WithBeforeStep, WithAfterStep and WithOnSkip, and the Logger,
+// may be called concurrently. It has no effect on the DAG and Parallel workflows.
+func WithStepConcurrency(n uint) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.stepConcurrency = n
+	}
+}
+
+// stepsCluster is a contiguous run of steps configured to continue the workflow on error, run concurrently.
+type stepsCluster[T any] struct {
+	steps []SequentialStepConfig[T]
+	first int // the index of the first step of the cluster, in the workflow steps
+}
+
+// cluster returns the cluster of the steps starting at the index i, which has no steps if the step concurrency is not
+// enabled, or if the step at the index i doesn't continue the workflow on error.
+func (s *Sequential[T]) cluster(stepsCfg []SequentialStepConfig[T], i int) stepsCluster[T] {
+	if s.stepConcurrency <= 1 {
+		return stepsCluster[T]{}
+	}
+	n := 0
+	for _, stepCfg := range stepsCfg[i:] {
+		if !stepCfg.ContinueWorkflowOnError || stepCfg.Step == nil {
+			break
+		}
+		n++
+	}
+
+	return stepsCluster[T]{steps: stepsCfg[i : i+n], first: i}
+}
+
+// runCluster runs the steps of the cluster concurrently, as described by WithStepConcurrency, and returns the errors of
+// the failing steps, and the indexes of the succeeded ones, if the compensation is enabled, both in the config order.
+// The steps skipped for their cache key are not reported as succeeded, as they are compensated once, see WithCompensation.
+func (s *Sequential[T]) runCluster(
+	ctx context.Context,
+	req T,
+	c stepsCluster[T],
+	report *ExecutionReport,
+	exec *execution,
+) (errs []error, succeeded []int) {
+	// the steps to run are decided, and their reports are added, before any step runs, so the reports stay valid.
+	run := make([]bool, len(c.steps))
+	firstReport := report.size()
+	for k, stepCfg := range c.steps {
+		run[k] = s.shouldRun(ctx, stepCfg, req)
+		rep := report.add(stepCfg.name())
+		if !run[k] {
+			rep.skip()
+		}
+	}
+	if exec.cache == nil {
+		exec.cache = new(succeededSteps)
+	}
+	// the steps run on copies of the workflow and of the exec, the copy of the exec holding the same state, so the
+	// workflows without a cluster don't escape to the heap.
+	runner := *s
+	shared := &execution{retries: exec.retries, cache: exec.cache, results: exec.results}
+
+	results := make([]error, len(c.steps))
+	cached := make([]bool, len(c.steps))
+	sem := make(chan struct{}, s.stepConcurrency)
+	var wg sync.WaitGroup
+	for k, stepCfg := range c.steps {
+		if !run[k] {
+			continue
+		}
+		rep := report.stepAt(firstReport + k)
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(k int, stepCfg SequentialStepConfig[T]) {
+			defer wg.Done()
+			cached[k], results[k] = runner.processStep(ctx, stepCfg, req, rep, shared)
+			<-sem
+		}(k, stepCfg)
+	}
+	wg.Wait()
+
+	for k, err := range results {
+		switch {
+		case !run[k], cached[k]:
+		case err != nil:
+			errs = append(errs, err)
+			s.logContinue(ctx, c.steps[k].name())
+		case s.compensation:
+			succeeded = append(succeeded, c.first+k)
+		}
+	}
+
+	return errs, succeeded
+}
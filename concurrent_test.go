@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSequentialExecuteRunsTheContinueOnErrorStepsConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+	// every step of the cluster waits for all of them to start, which only happens if they run concurrently.
+	var started sync.WaitGroup
+	started.Add(3)
+	newClusterStep := func(name string) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			started.Done()
+			waitCh := make(chan struct{})
+			go func() {
+				started.Wait()
+				close(waitCh)
+			}()
+			select {
+			case <-waitCh:
+			case <-time.After(time.Second):
+				return errors.New("the steps of the cluster didn't run concurrently")
+			}
+			record("cluster")
+
+			return nil
+		}}
+	}
+	newStep := func(name string) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			record(name)
+
+			return nil
+		}}
+	}
+	input := []SequentialStepConfig[any]{
+		{Step: newStep("step 1")},
+		{Step: newClusterStep("step 2"), ContinueWorkflowOnError: true},
+		{Step: newClusterStep("step 3"), ContinueWorkflowOnError: true},
+		{Step: newClusterStep("step 4"), ContinueWorkflowOnError: true},
+		{Step: newStep("step 5")},
+	}
+
+	report, err := NewSequential("some-workflow", input, WithStepConcurrency(3)).ExecuteWithReport(context.TODO(), nil)
+
+	if err != nil {
+		t.Fatalf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", nil, err)
+	}
+	expectedOutput := []string{"step 1", "cluster", "cluster", "cluster", "step 5"}
+	if !reflect.DeepEqual(order, expectedOutput) {
+		t.Errorf("The steps order is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, order)
+	}
+	var names []string
+	for _, step := range report.Steps {
+		names = append(names, step.Name)
+	}
+	expectedNames := []string{"step 1", "step 2", "step 3", "step 4", "step 5"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("The reported steps are not as expected: \n expected = %#v, \n actual = %#v", expectedNames, names)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStepConcurrencyErrors(t *testing.T) {
+	err1 := errors.New("err-1")
+	err3 := errors.New("err-3")
+	step4 := newStepSuccessful("step 4")
+	input := []SequentialStepConfig[any]{
+		{Step: newStepFailedNonRetryable("step 1", err1), ContinueWorkflowOnError: true},
+		{Step: newStepSuccessful("step 2"), ContinueWorkflowOnError: true},
+		{Step: newStepFailedNonRetryable("step 3", err3), ContinueWorkflowOnError: true},
+		{Step: step4},
+	}
+
+	err := NewSequential("some-workflow", input, WithStepConcurrency(2)).Execute(context.TODO(), nil)
+
+	expectedOutput := []string{"step 1", "step 3"}
+	if actualOutput := FailedSteps(err); !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The failed steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	if !errors.Is(err, err1) || !errors.Is(err, err3) {
+		t.Errorf("The workflow error is not as expected: \n actual = %#v", err)
+	}
+	if step4.invocationCount != 1 {
+		t.Errorf("The step after the cluster invocation count is not as expected: \n expected = %#v, \n actual = %#v", 1, step4.invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStepConcurrencyLimit(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	newStep := func(name string) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			return nil
+		}}
+	}
+	var input []SequentialStepConfig[any]
+	for _, name := range []string{"step 1", "step 2", "step 3", "step 4", "step 5"} {
+		input = append(input, SequentialStepConfig[any]{Step: newStep(name), ContinueWorkflowOnError: true})
+	}
+
+	NewSequential("some-workflow", input, WithStepConcurrency(2)).Execute(context.TODO(), nil)
+
+	if actualOutput := maxRunning.Load(); actualOutput > 2 {
+		t.Errorf("The number of the steps running at the same time is not as expected: \n expected <= %#v, \n actual = %#v", 2, actualOutput)
+	}
+}
@@ -0,0 +1,107 @@
+package workflow
+
+import "context"
+
+// contextKey is the type of the keys of the values carried by the ctx passed to the steps.
+type contextKey int
+
+const (
+	workflowNameKey contextKey = iota
+	stepNameKey
+	executionIDKey
+)
+
+// WithStepNamesInContext makes the workflow pass to every step a ctx carrying the workflow and the step names, read by
+// WorkflowNameFromContext and StepNameFromContext, e.g. for the steps correlating their own logs with the workflow ones.
+// It costs an allocation for every step, so it's disabled by default.
+func WithStepNamesInContext() SequentialOption {
+	return func(o *sequentialOptions) {
+		o.stepNames = true
+	}
+}
+
+// WithPipeStepNamesInContext is the Pipe version of WithStepNamesInContext.
+func WithPipeStepNamesInContext() PipeOption {
+	return func(o *pipeOptions) {
+		o.stepNames = true
+	}
+}
+
+// WithContextEnricher makes the workflow pass to every step the ctx returned by the enricher, e.g. carrying a step
+// scoped logger, or the step credentials. The enricher is called once per step, before its first attempt, with the ctx
+// the step would receive otherwise, which carries the step names if enabled by WithStepNamesInContext. The ctx of
+// every attempt, bounded by the step timeout, if any, is derived from the returned ctx. A nil enricher passes the ctx
+// through, which is the default.
+func WithContextEnricher(enricher func(ctx context.Context, stepName string) context.Context) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.enrichContext = enricher
+	}
+}
+
+// WithPipeContextEnricher is the Pipe version of WithContextEnricher.
+func WithPipeContextEnricher(enricher func(ctx context.Context, stepName string) context.Context) PipeOption {
+	return func(o *pipeOptions) {
+		o.enrichContext = enricher
+	}
+}
+
+// WorkflowNameFromContext returns the name of the workflow running the step the ctx was passed to, and reports whether
+// it is set, which requires WithStepNamesInContext or WithPipeStepNamesInContext. For a nested workflow, it's the name
+// of the innermost one.
+func WorkflowNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(workflowNameKey).(string)
+
+	return name, ok
+}
+
+// StepNameFromContext returns the name of the step the ctx was passed to, and reports whether it is set, which
+// requires WithStepNamesInContext or WithPipeStepNamesInContext. It's the PipeStepConfig or the SequentialStepConfig
+// NameOverride, if set.
+func StepNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(stepNameKey).(string)
+
+	return name, ok
+}
+
+// stepContext is the ctx passed to a step, carrying the workflow and the step names.
+// It holds both names in a single allocation, instead of the two allocations of the context.WithValue calls.
+type stepContext struct {
+	context.Context
+	workflowName string
+	stepName     string
+}
+
+// Value returns the workflow or the step name for their keys, and the value of the parent ctx for any other key.
+func (c *stepContext) Value(key any) any {
+	switch key {
+	case workflowNameKey:
+		return c.workflowName
+	case stepNameKey:
+		return c.stepName
+	}
+
+	return c.Context.Value(key)
+}
+
+// withStepNames returns the ctx carrying the workflow and the step names, if enabled, otherwise the ctx itself.
+func withStepNames(ctx context.Context, enabled bool, workflowName, stepName string) context.Context {
+	if !enabled {
+		return ctx
+	}
+
+	return &stepContext{Context: ctx, workflowName: workflowName, stepName: stepName}
+}
+
+// enrichContext returns the ctx decorated by the enricher for the step named stepName, if the enricher is not nil,
+// otherwise the ctx itself.
+func enrichContext(
+	ctx context.Context,
+	enricher func(ctx context.Context, stepName string) context.Context,
+	stepName string,
+) context.Context {
+	if enricher == nil {
+		return ctx
+	}
+
+	return enricher(ctx, stepName)
+}
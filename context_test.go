@@ -0,0 +1,153 @@
+package workflow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnStepNamesInContext(t *testing.T) {
+	type names struct {
+		workflow, step string
+		ok             bool
+	}
+	var actual []names
+	newStep := func(name string) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			workflowName, ok := WorkflowNameFromContext(ctx)
+			stepName, _ := StepNameFromContext(ctx)
+			actual = append(actual, names{workflow: workflowName, step: stepName, ok: ok})
+
+			return nil
+		}}
+	}
+	nested := NewSequential("nested-workflow", []SequentialStepConfig[any]{{Step: newStep("nested step")}}, WithStepNamesInContext())
+	input := []SequentialStepConfig[any]{
+		{Step: newStep("step 1")},
+		{Step: newStep("step 2"), NameOverride: "step 2 override"},
+		{Step: nested},
+	}
+
+	NewSequential("some-workflow", input, WithStepNamesInContext()).Execute(context.TODO(), nil)
+	NewSequential("no-names-workflow", []SequentialStepConfig[any]{{Step: newStep("step 1")}}).Execute(context.TODO(), nil)
+
+	expectedOutput := []names{
+		{workflow: "some-workflow", step: "step 1", ok: true},
+		{workflow: "some-workflow", step: "step 2 override", ok: true},
+		// the innermost workflow names shadow the parent ones.
+		{workflow: "nested-workflow", step: "nested step", ok: true},
+		// the names are not passed by default.
+		{},
+	}
+	if len(actual) != len(expectedOutput) {
+		t.Fatalf("The number of step executions is not as expected: \n expected = %#v, \n actual = %#v", len(expectedOutput), len(actual))
+	}
+	for i := range expectedOutput {
+		if actual[i] != expectedOutput[i] {
+			t.Errorf("The names in the step ctx are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput[i], actual[i])
+		}
+	}
+}
+
+func TestSequentialExecuteBehaviourOnContextEnricher(t *testing.T) {
+	type key struct{}
+	var actualOutput []any
+	newStep := func(name string) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			actualOutput = append(actualOutput, ctx.Value(key{}))
+
+			return nil
+		}}
+	}
+	input := []SequentialStepConfig[any]{
+		{Step: newStep("step 1")},
+		{Step: newStep("step 2"), NameOverride: "step 2 override"},
+	}
+	enricher := func(ctx context.Context, stepName string) context.Context {
+		// the enricher receives the ctx carrying the step names, if enabled.
+		if name, _ := StepNameFromContext(ctx); name != stepName {
+			t.Errorf("The enricher ctx is not as expected: \n expected = %#v, \n actual = %#v", stepName, name)
+		}
+
+		return context.WithValue(ctx, key{}, "value of "+stepName)
+	}
+
+	err := NewSequential("some-workflow", input, WithStepNamesInContext(), WithContextEnricher(enricher)).Execute(context.TODO(), nil)
+	NewSequential("no-enricher-workflow", []SequentialStepConfig[any]{{Step: newStep("step 1")}}).Execute(context.TODO(), nil)
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	// the ctx is passed through by default.
+	expectedOutput := []any{"value of step 1", "value of step 2 override", nil}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The step ctx values are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestPipeExecuteBehaviourOnContextEnricher(t *testing.T) {
+	type key struct{}
+	step := &ctxPipeStepMock{name: "step 1", fn: func(ctx context.Context, req string) string {
+		v, _ := ctx.Value(key{}).(string)
+
+		return req + "-" + v
+	}}
+	enricher := func(ctx context.Context, stepName string) context.Context {
+		return context.WithValue(ctx, key{}, stepName)
+	}
+
+	actualOutput, err := NewPipe(
+		"some-workflow",
+		[]PipeStepConfig[string]{{Step: step}},
+		WithPipeContextEnricher(enricher),
+	).Execute(context.TODO(), "x")
+
+	if err != nil || actualOutput != "x-step 1" {
+		t.Errorf("The workflow result is not as expected: \n expected = %#v, \n actual = %#v, %v", "x-step 1", actualOutput, err)
+	}
+}
+
+func TestStepContextKeepsTheParentValues(t *testing.T) {
+	type key struct{}
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "some-value"))
+	ctx := withStepNames(parent, true, "some-workflow", "step 1")
+
+	if v := ctx.Value(key{}); v != "some-value" {
+		t.Errorf("The parent ctx value is not as expected: \n expected = %#v, \n actual = %#v", "some-value", v)
+	}
+	cancel()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("The ctx error is not as expected: \n expected = %#v, \n actual = %#v", context.Canceled, ctx.Err())
+	}
+}
+
+func TestPipeExecuteBehaviourOnStepNamesInContext(t *testing.T) {
+	step := &ctxPipeStepMock{name: "step 1", fn: func(ctx context.Context, req string) string {
+		workflowName, _ := WorkflowNameFromContext(ctx)
+		stepName, _ := StepNameFromContext(ctx)
+
+		return req + workflowName + "/" + stepName
+	}}
+
+	out, _ := NewPipe("some-workflow", []PipeStepConfig[string]{{Step: step}}, WithPipeStepNamesInContext()).Execute(context.TODO(), "")
+
+	if out != "some-workflow/step 1" {
+		t.Errorf("The names in the step ctx are not as expected: \n expected = %#v, \n actual = %#v", "some-workflow/step 1", out)
+	}
+}
+
+// MOCKS/STUBS
+
+// ctxPipeStepMock is a PipeStep whose output is computed from its ctx and its req.
+type ctxPipeStepMock struct {
+	name string
+	fn   func(ctx context.Context, req string) string
+}
+
+func (s *ctxPipeStepMock) Name() string {
+	return s.name
+}
+
+func (s *ctxPipeStepMock) Execute(ctx context.Context, req string) (string, error) {
+	return s.fn(ctx, req), nil
+}
@@ -0,0 +1,258 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDependencyFailed is wrapped by the errors reporting the DAG steps skipped because a step they depend on failed.
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// DAGStepConfig provides configuration for a step of a DAG workflow.
+type DAGStepConfig[T any] struct {
+	Step SequentialStep[T]
+	// DependsOn holds the names of the steps that must succeed before the Step runs.
+	DependsOn []string
+	// define this only if the Step implements RetryDecider or ErrorRetryDecider, otherwise it has no effect and no sense!
+	RetryConfigProvider func() (maxAttempts uint, attemptDelay time.Duration) // provides the retry configuration
+	// Backoff computes the delay before every retry attempt, same as for the SequentialStepConfig.
+	Backoff BackoffStrategy
+	// RetryDelay computes the delay before every retry attempt from the error of the previous attempt, same as for the
+	// SequentialStepConfig.
+	RetryDelay func(attempt uint, lastErr error) time.Duration
+	// RetryBudget bounds the total time spent on retrying the Step, same as for the SequentialStepConfig.
+	RetryBudget time.Duration
+	// CircuitBreaker stops executing the Step, same as for the SequentialStepConfig.
+	CircuitBreaker *CircuitBreaker
+}
+
+// DAG is a workflow that runs its steps following their dependencies: a step runs once all the steps it depends on
+// succeeded, and the independent steps run in parallel.
+type DAG[T any] struct {
+	name        string
+	stepsConfig []DAGStepConfig[T]
+	dependents  [][]int // the indexes of the steps depending on every step
+	dependCount []int   // the number of dependencies of every step
+	// runner executes the steps, so the DAG steps are retried, stored, measured and logged as the Sequential ones.
+	runner *Sequential[T]
+}
+
+// the workflow implements the SequentialStep interface, so it can be a step of another workflow.
+var _ SequentialStep[any] = (*DAG[any])(nil)
+
+// NewDAG is the workflow constructor.
+// It returns an error if the configuration is not valid, as described by Sequential.Validate, if a step depends on an
+// unknown step, or if the dependencies form a cycle.
+// The options are the Sequential ones, WithCompensation excepted, which has no effect. The hooks registered by
+// WithBeforeStep and WithAfterStep, and the Logger, may be called concurrently.
+func NewDAG[T any](name string, stepsCfg []DAGStepConfig[T], opts ...SequentialOption) (*DAG[T], error) {
+	v := newStepsValidator(len(stepsCfg))
+	for i, stepCfg := range stepsCfg {
+		if stepCfg.Step == nil {
+			v.checkStep(i, checkedStep{})
+
+			continue
+		}
+		v.checkStep(i, checkedStep{
+			step:      stepCfg.Step,
+			name:      stepCfg.Step.Name(),
+			retry:     stepCfg.RetryConfigProvider,
+			retryable: isRetryable(stepCfg.Step),
+		})
+	}
+	if err := v.err(name); err != nil {
+		return nil, err
+	}
+	indexes := v.indexes
+
+	d := DAG[T]{
+		name:        name,
+		stepsConfig: stepsCfg,
+		dependents:  make([][]int, len(stepsCfg)),
+		dependCount: make([]int, len(stepsCfg)),
+		runner:      NewSequential[T](name, nil, opts...),
+	}
+	for i, stepCfg := range stepsCfg {
+		for _, dep := range stepCfg.DependsOn {
+			j, ok := indexes[dep]
+			if !ok {
+				return nil, fmt.Errorf("workflow: %s, step: %s depends on unknown step: %s", name, stepCfg.Step.Name(), dep)
+			}
+			d.dependents[j] = append(d.dependents[j], i)
+			d.dependCount[i]++
+		}
+	}
+	if err := d.checkCycles(); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// checkCycles returns an error if the steps dependencies form a cycle.
+// It sorts the steps topologically, and the steps left unsorted are the ones part of, or depending on, a cycle.
+func (d *DAG[T]) checkCycles() error {
+	pending := make([]int, len(d.dependCount))
+	copy(pending, d.dependCount)
+	var sorted []int
+	for i, count := range pending {
+		if count == 0 {
+			sorted = append(sorted, i)
+		}
+	}
+	for k := 0; k < len(sorted); k++ {
+		for _, j := range d.dependents[sorted[k]] {
+			pending[j]--
+			if pending[j] == 0 {
+				sorted = append(sorted, j)
+			}
+		}
+	}
+	if len(sorted) == len(d.stepsConfig) {
+		return nil
+	}
+	for i, count := range pending {
+		if count > 0 {
+			return fmt.Errorf("workflow: %s, dependency cycle detected at step: %s", d.name, d.stepsConfig[i].Step.Name())
+		}
+	}
+
+	return nil
+}
+
+// Name returns the name of the workflow.
+func (d *DAG[T]) Name() string {
+	return d.name
+}
+
+// Execute runs the steps in topological order, passing the ctx and the req to every DAGStepConfig.Step, and runs the
+// independent steps in parallel.
+// A failing step doesn't stop the independent steps, but its dependents, direct or not, are skipped, and reported
+// by errors wrapping ErrDependencyFailed.
+// The errors returned by the failing steps are wrapped in a single error, so any error from any failing
+// DAGStepConfig.Step can be checked using errors.Is or errors.As against the returned error. Every step error is wrapped
+// in a StepError, identifying the failing step. The steps errors are joined in the steps declaration order, whatever
+// the order the steps finished in, so the failures summaries are stable.
+// The ctx is checked before starting every step, and a done ctx stops the workflow, the ctx error being returned along
+// with the steps errors, once the running steps are done. The steps not started because the ctx is done are reported
+// only by the ctx error, not by ErrDependencyFailed.
+// If a storage and a correlation ID are configured, the steps that already succeeded for the correlation ID are skipped,
+// and the stored results are cleared once the workflow succeeds.
+// The Cacheable steps which already succeeded in the execution, for their cache key, are skipped.
+func (d *DAG[T]) Execute(ctx context.Context, req T) error {
+	ctx = d.runner.execID.context(ctx)
+
+	return d.runner.end.end(ctx, wrapWorkflowErr(d.runner.wrapErrs, d.name, d.execute(ctx, req)))
+}
+
+// execute runs the workflow, as described by Execute, without wrapping the returned error.
+func (d *DAG[T]) execute(ctx context.Context, req T) error {
+	d.runner.log.print(ctx, LevelInfo, concatStr("[START] executing workflow: ", d.name))
+	defer d.runner.log.printDone(ctx)
+
+	e := dagExecution[T]{
+		dag:     d,
+		req:     req,
+		pending: make([]int, len(d.dependCount)),
+		blocked: make([]bool, len(d.stepsConfig)),
+		results: make(chan dagResult, len(d.stepsConfig)),
+		exec:    execution{retries: d.runner.newGlobalRetries(), cache: new(succeededSteps)},
+	}
+	copy(e.pending, d.dependCount)
+	for i, count := range e.pending {
+		if count == 0 {
+			e.start(ctx, i)
+		}
+	}
+	for e.running > 0 {
+		e.done(ctx, <-e.results)
+	}
+	errs := e.errs.errs()
+	if e.stopped {
+		errs = append(errs, ctx.Err())
+	}
+
+	if len(errs) > 0 {
+		return joinErrs(errs)
+	}
+
+	return d.runner.store.handleErr(ctx, d.runner.log, d.runner.store.clearDB(ctx))
+}
+
+// dagResult is the outcome of a DAG step.
+type dagResult struct {
+	index   int
+	err     error
+	blocked bool // the step didn't run, because a dependency failed
+}
+
+// dagExecution holds the state of a single DAG execution.
+// It is accessed only by the goroutine running DAG.Execute, the steps goroutines sending their results on the results channel.
+type dagExecution[T any] struct {
+	dag     *DAG[T]
+	req     T
+	pending []int  // the number of dependencies of every step, not finished yet
+	blocked []bool // the steps having at least one dependency which failed, or was blocked by a failed one
+	results chan dagResult
+	exec    execution // the state shared by the steps
+	running int
+	stopped bool // a step was not started because the ctx is done
+	errs    orderedErrs
+}
+
+// start runs the step in a new goroutine, unless the ctx is done, in which case the step is skipped, along with its
+// dependents, the skipped steps being reported only by the ctx error.
+func (e *dagExecution[T]) start(ctx context.Context, i int) {
+	stepCfg := e.dag.stepsConfig[i]
+	if ctx.Err() != nil {
+		e.stopped = true
+		// the dependents are released as if the step succeeded, so they are not started either, for the same reason.
+		e.finish(ctx, dagResult{index: i})
+
+		return
+	}
+	e.running++
+	go func() {
+		_, err := e.dag.runner.processStep(ctx, SequentialStepConfig[T]{
+			Step:                stepCfg.Step,
+			RetryConfigProvider: stepCfg.RetryConfigProvider,
+			Backoff:             stepCfg.Backoff,
+			RetryDelay:          stepCfg.RetryDelay,
+			RetryBudget:         stepCfg.RetryBudget,
+			CircuitBreaker:      stepCfg.CircuitBreaker,
+		}, e.req, nil, &e.exec)
+		e.results <- dagResult{index: i, err: err}
+	}()
+}
+
+// done handles the result of a step that ran.
+func (e *dagExecution[T]) done(ctx context.Context, r dagResult) {
+	e.running--
+	if r.err != nil {
+		e.errs.add(r.index, r.err)
+	}
+	e.finish(ctx, r)
+}
+
+// finish releases the dependents of a finished step, starting the ones having all their dependencies succeeded, and
+// skipping the ones having a failed dependency, or one blocked by a failed dependency, with ErrDependencyFailed.
+func (e *dagExecution[T]) finish(ctx context.Context, r dagResult) {
+	for _, j := range e.dag.dependents[r.index] {
+		e.pending[j]--
+		e.blocked[j] = e.blocked[j] || r.err != nil || r.blocked
+		if e.pending[j] > 0 {
+			continue
+		}
+		if !e.blocked[j] {
+			e.start(ctx, j)
+
+			continue
+		}
+		stepName := e.dag.stepsConfig[j].Step.Name()
+		e.dag.runner.log.printStep(ctx, LevelError, concatStr("skipping step: ", stepName, ", a dependency did not succeed"), stepName, 0)
+		e.errs.add(j, fmt.Errorf("skipping step: %s: %w", stepName, ErrDependencyFailed))
+		e.finish(ctx, dagResult{index: j, blocked: true})
+	}
+}
@@ -0,0 +1,212 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewDAGBehaviourOnInvalidDependencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []DAGStepConfig[any]
+	}{
+		{
+			name: "a workflow with duplicate step names should return an error",
+			input: []DAGStepConfig[any]{
+				{Step: newStepSuccessful("a")},
+				{Step: newStepSuccessful("a")},
+			},
+		},
+		{
+			name: "a workflow with a step depending on an unknown step should return an error",
+			input: []DAGStepConfig[any]{
+				{Step: newStepSuccessful("a")},
+				{Step: newStepSuccessful("b"), DependsOn: []string{"x"}},
+			},
+		},
+		{
+			name: "a workflow with a dependency cycle should return an error",
+			input: []DAGStepConfig[any]{
+				{Step: newStepSuccessful("a")},
+				{Step: newStepSuccessful("b"), DependsOn: []string{"a", "d"}},
+				{Step: newStepSuccessful("c"), DependsOn: []string{"b"}},
+				{Step: newStepSuccessful("d"), DependsOn: []string{"c"}},
+			},
+		},
+		{
+			name: "a workflow with a step depending on itself should return an error",
+			input: []DAGStepConfig[any]{
+				{Step: newStepSuccessful("a"), DependsOn: []string{"a"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDAG("some-workflow", tt.input)
+			if err == nil || d != nil {
+				t.Errorf("The constructor output is not as expected: \n expected error, \n actual = %#v, %#v", d, err)
+			}
+		})
+	}
+}
+
+func TestDAGExecuteBehaviourOnDependencies(t *testing.T) {
+	var mu sync.Mutex
+	var executed []string
+	// b and c wait for each other, so the test fails by timeout if they don't run in parallel.
+	bStarted, cStarted := make(chan struct{}), make(chan struct{})
+	newStep := func(name string, started, waitFor chan struct{}) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			if started != nil {
+				close(started)
+				select {
+				case <-waitFor:
+				case <-time.After(time.Second):
+					return errors.New("the independent steps did not run in parallel")
+				}
+			}
+			mu.Lock()
+			executed = append(executed, name)
+			mu.Unlock()
+
+			return nil
+		}}
+	}
+	input := []DAGStepConfig[any]{
+		{Step: newStep("d", nil, nil), DependsOn: []string{"b", "c"}},
+		{Step: newStep("b", bStarted, cStarted), DependsOn: []string{"a"}},
+		{Step: newStep("c", cStarted, bStarted), DependsOn: []string{"a"}},
+		{Step: newStep("a", nil, nil)},
+	}
+
+	d, err := NewDAG("some-workflow", input)
+	if err != nil {
+		t.Fatalf("The constructor returned an unexpected error: %v", err)
+	}
+	if err = d.Execute(context.TODO(), nil); err != nil {
+		t.Fatalf("The workflow returned an unexpected error: %v", err)
+	}
+
+	if len(executed) != 4 || executed[0] != "a" || executed[3] != "d" {
+		t.Errorf("The steps execution order is not as expected: \n actual = %#v", executed)
+	}
+}
+
+func TestDAGExecuteBehaviourOnStepFailure(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var mu sync.Mutex
+	var executed []string
+	newStep := func(name string, err error) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			mu.Lock()
+			executed = append(executed, name)
+			mu.Unlock()
+
+			return err
+		}}
+	}
+	input := []DAGStepConfig[any]{
+		{Step: newStep("a", nil)},
+		{Step: newStep("b", anyErr), DependsOn: []string{"a"}},
+		{Step: newStep("c", nil), DependsOn: []string{"a"}},
+		{Step: newStep("d", nil), DependsOn: []string{"b", "c"}},
+		{Step: newStep("e", nil), DependsOn: []string{"d"}},
+		{Step: newStep("f", nil), DependsOn: []string{"c"}},
+	}
+
+	d, err := NewDAG("some-workflow", input)
+	if err != nil {
+		t.Fatalf("The constructor returned an unexpected error: %v", err)
+	}
+	err = d.Execute(context.TODO(), nil)
+
+	if !errors.Is(err, anyErr) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+	if !errors.Is(err, ErrDependencyFailed) {
+		t.Errorf("The workflow error does not report the skipped steps: \n actual = %#v", err)
+	}
+	sort.Strings(executed)
+	expectedOutput := []string{"a", "b", "c", "f"}
+	if !reflect.DeepEqual(executed, expectedOutput) {
+		t.Errorf("The executed steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, executed)
+	}
+}
+
+func TestDAGExecuteBehaviourOnDoneContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	step2 := &dagStepMock{name: "step 2", fn: func(ctx context.Context) error { return nil }}
+	step3 := &dagStepMock{name: "step 3", fn: func(ctx context.Context) error { return nil }}
+	input := []DAGStepConfig[any]{
+		{Step: &dagStepMock{name: "step 1", fn: func(ctx context.Context) error { cancel(); return nil }}},
+		{Step: step2, DependsOn: []string{"step 1"}},
+		{Step: step3, DependsOn: []string{"step 2"}},
+	}
+
+	d, err := NewDAG("some-workflow", input)
+	if err != nil {
+		t.Fatalf("The constructor returned an unexpected error: %v", err)
+	}
+	err = d.Execute(ctx, nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", context.Canceled, err)
+	}
+	// no dependency failed, so the steps not started are reported only by the ctx error.
+	if errors.Is(err, ErrDependencyFailed) {
+		t.Errorf("The workflow error reports a dependency failure for a done ctx: \n actual = %#v", err)
+	}
+	if step2.invocationCount != 0 || step3.invocationCount != 0 {
+		t.Errorf("The steps after the ctx was done were executed: \n invocations = %#v, %#v", step2.invocationCount, step3.invocationCount)
+	}
+}
+
+func TestDAGExecuteErrorsOrder(t *testing.T) {
+	anyErr := errors.New("any-err")
+	// the step 1 fails only once the step 2 failed, so the steps finish in the reverse declaration order.
+	step2Done := make(chan struct{})
+	input := []DAGStepConfig[any]{
+		{Step: &dagStepMock{name: "step 1", fn: func(ctx context.Context) error {
+			<-step2Done
+
+			return anyErr
+		}}},
+		{Step: &dagStepMock{name: "step 2", fn: func(ctx context.Context) error {
+			defer close(step2Done)
+
+			return anyErr
+		}}},
+	}
+	d, _ := NewDAG("some-workflow", input)
+
+	err := d.Execute(context.TODO(), nil)
+
+	expectedOutput := []string{"step 1", "step 2"}
+	if actualOutput := FailedSteps(err); !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The failed steps order is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+// MOCKS/STUBS
+
+// dagStepMock is a SequentialStep running the provided function.
+type dagStepMock struct {
+	name            string
+	fn              func(ctx context.Context) error
+	invocationCount int
+}
+
+func (s *dagStepMock) Name() string {
+	return s.name
+}
+
+func (s *dagStepMock) Execute(ctx context.Context, _ any) error {
+	s.invocationCount++
+
+	return s.fn(ctx)
+}
@@ -0,0 +1,54 @@
+package workflow
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// WithMinRemainingTime stops the workflow before a step, with context.DeadlineExceeded, if the time left until the
+// deadline of the ctx is less than d, as the step can't plausibly finish, instead of starting it and letting it exceed
+// the deadline. It has no effect for a ctx without a deadline. The remaining time is measured by the Clock, and it's
+// logged at the Debug level before every step, even without this option.
+func WithMinRemainingTime(d time.Duration) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.minRemainingTime = d
+	}
+}
+
+// WithPipeMinRemainingTime is the Pipe version of WithMinRemainingTime.
+func WithPipeMinRemainingTime(d time.Duration) PipeOption {
+	return func(o *pipeOptions) {
+		o.minRemainingTime = d
+	}
+}
+
+// checkRemainingTime logs the time left until the deadline of the ctx, if any, before running the step, and returns
+// context.DeadlineExceeded if it's less than the minRemaining.
+func checkRemainingTime(ctx context.Context, log logger, clock Clock, stepName string, minRemaining time.Duration) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	remaining := deadline.Sub(now(clock))
+	log.printStep(
+		ctx,
+		LevelDebug,
+		concatStr("step: ", stepName, " starts with: ", strconv.FormatInt(remaining.Milliseconds(), 10), "ms left until the deadline"),
+		stepName,
+		0,
+	)
+	if minRemaining > 0 && remaining < minRemaining {
+		log.printStep(
+			ctx,
+			LevelError,
+			concatStr("step: ", stepName, " is not started, the time left until the deadline is less than: ", strconv.FormatInt(minRemaining.Milliseconds(), 10), "ms"),
+			stepName,
+			0,
+		)
+
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
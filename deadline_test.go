@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/silviutanasa/workflow/workflowtest"
+)
+
+func TestSequentialExecuteBehaviourOnMinRemainingTime(t *testing.T) {
+	start := time.Now()
+	clock := workflowtest.NewFakeClock(start)
+	// the ctx deadline is far in the future for the system time, so only the fake clock brings it close.
+	ctx, cancel := context.WithDeadline(context.Background(), start.Add(time.Hour))
+	defer cancel()
+	step1 := &dagStepMock{name: "step 1", fn: func(ctx context.Context) error {
+		clock.Advance(50 * time.Minute)
+
+		return nil
+	}}
+	step2 := &dagStepMock{name: "step 2", fn: func(ctx context.Context) error { return nil }}
+	input := []SequentialStepConfig[any]{
+		{Step: step1},
+		{Step: step2},
+	}
+	log := &leveledLoggerMock{}
+
+	err := NewSequential(
+		"some-workflow",
+		input,
+		WithClock(clock),
+		WithLogger(log),
+		WithMinRemainingTime(30*time.Minute),
+	).Execute(ctx, nil)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", context.DeadlineExceeded, err)
+	}
+	if step1.invocationCount != 1 || step2.invocationCount != 0 {
+		t.Errorf("The steps invocation counts are not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", 1, 0, step1.invocationCount, step2.invocationCount)
+	}
+	for _, expectedOutput := range []string{
+		"step: step 1 starts with: 3600000ms left until the deadline",
+		"step: step 2 starts with: 600000ms left until the deadline",
+	} {
+		if !contains(log.debug, expectedOutput) {
+			t.Errorf("The remaining time is not logged: \n expected = %#v, \n actual = %#v", expectedOutput, log.debug)
+		}
+	}
+}
+
+func TestSequentialExecuteBehaviourOnNearExpiredContext(t *testing.T) {
+	tests := []struct {
+		name                    string
+		input                   time.Duration // the minimum remaining time
+		expectedErr             error
+		expectedInvocationCount int
+	}{
+		{
+			name:                    "a step should run without a minimum remaining time",
+			input:                   0,
+			expectedInvocationCount: 1,
+		},
+		{
+			name:                    "a step should run if the remaining time is greater than the minimum",
+			input:                   time.Millisecond,
+			expectedInvocationCount: 1,
+		},
+		{
+			name:                    "a step should not run if the remaining time is less than the minimum",
+			input:                   time.Minute,
+			expectedErr:             context.DeadlineExceeded,
+			expectedInvocationCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			step := newStepSuccessful("step 1")
+
+			err := NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: step}}, WithMinRemainingTime(tt.input)).
+				Execute(ctx, nil)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedErr, err)
+			}
+			if step.invocationCount != tt.expectedInvocationCount {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedInvocationCount, step.invocationCount)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnMinRemainingTime(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+	}
+
+	out, err := NewPipe("some-workflow", input, WithPipeMinRemainingTime(time.Minute)).Execute(ctx, "x")
+
+	// the value the step would have received is returned.
+	if !errors.Is(err, context.DeadlineExceeded) || out != "x" {
+		t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x", context.DeadlineExceeded, out, err)
+	}
+}
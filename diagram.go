@@ -0,0 +1,189 @@
+package workflow
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// diagrammer is implemented by the workflows, so that a workflow used as a step of another workflow is rendered as a
+// subgraph of its diagram.
+type diagrammer interface {
+	writeDiagram(b *bytes.Buffer, id string, depth int)
+}
+
+// Diagram returns a mermaid flowchart of the workflow steps, in the execution order.
+// The steps configured to be retried, and the ones not stopping the workflow on error, are marked as such, and the
+// workflows used as steps are rendered as subgraphs. A nil step is rendered as a node named after its problem. The
+// steps of a dynamic workflow are built at execution time, so they are rendered as a single node.
+func (s *Sequential[T]) Diagram() string {
+	return diagram(s)
+}
+
+// Diagram returns a mermaid flowchart of the workflow steps, in the execution order.
+// The steps configured to be retried, and the ones not stopping the workflow on error, are marked as such, and the
+// workflows used as steps are rendered as subgraphs. A nil step is rendered as a node named after its problem.
+func (p *Pipe[T]) Diagram() string {
+	return diagram(p)
+}
+
+// Diagram returns a mermaid flowchart of the workflow steps, which run at the same time, so no edge links them.
+// The steps configured to be retried are marked as such, and the workflows used as steps are rendered as subgraphs. A
+// nil step is rendered as a node named after its problem.
+func (p *Parallel[T]) Diagram() string {
+	return diagram(p)
+}
+
+// Diagram returns a mermaid flowchart of the workflow steps, every step being linked to the steps depending on it.
+// The steps configured to be retried are marked as such, and the workflows used as steps are rendered as subgraphs.
+func (d *DAG[T]) Diagram() string {
+	return diagram(d)
+}
+
+// diagram renders the workflow d as a mermaid flowchart.
+func diagram(d diagrammer) string {
+	var b bytes.Buffer
+	b.Grow(256)
+	b.WriteString("flowchart TD\n")
+	d.writeDiagram(&b, "s", 1)
+
+	return b.String()
+}
+
+func (s *Sequential[T]) writeDiagram(b *bytes.Buffer, id string, depth int) {
+	if s.stepsProvider != nil {
+		writeNode(b, id+"0", "dynamic steps", depth)
+
+		return
+	}
+	var prevID string
+	for i, stepCfg := range s.stepsConfig {
+		stepID := id + strconv.Itoa(i)
+		retryable := stepCfg.RetryConfigProvider != nil && stepCfg.retryable()
+		node := diagramNode{id: stepID, name: stepCfg.describedName(i), marks: stepMarks(retryable, stepCfg.ContinueWorkflowOnError)}
+		writeStep(b, stepCfg.Step, node, depth)
+		writeEdge(b, prevID, stepID, depth)
+		prevID = stepID
+	}
+}
+
+func (p *Pipe[T]) writeDiagram(b *bytes.Buffer, id string, depth int) {
+	var prevID string
+	for i, stepCfg := range p.stepsConfig {
+		stepID := id + strconv.Itoa(i)
+		retryable := stepCfg.RetryConfigProvider != nil && stepCfg.retryable()
+		node := diagramNode{id: stepID, name: stepCfg.describedName(i), marks: stepMarks(retryable, stepCfg.ContinueOnError)}
+		writeStep(b, stepCfg.Step, node, depth)
+		writeEdge(b, prevID, stepID, depth)
+		prevID = stepID
+	}
+}
+
+func (p *Parallel[T]) writeDiagram(b *bytes.Buffer, id string, depth int) {
+	for i, stepCfg := range p.runner.stepsConfig {
+		retryable := stepCfg.RetryConfigProvider != nil && stepCfg.retryable()
+		node := diagramNode{id: id + strconv.Itoa(i), name: stepCfg.describedName(i), marks: stepMarks(retryable, false)}
+		writeStep(b, stepCfg.Step, node, depth)
+	}
+}
+
+func (d *DAG[T]) writeDiagram(b *bytes.Buffer, id string, depth int) {
+	for i, stepCfg := range d.stepsConfig {
+		retryable := stepCfg.RetryConfigProvider != nil && isRetryable(stepCfg.Step)
+		node := diagramNode{id: id + strconv.Itoa(i), name: stepCfg.Step.Name(), marks: stepMarks(retryable, false)}
+		writeStep(b, stepCfg.Step, node, depth)
+	}
+	// the edges are rendered once all the steps are, as a step may depend on the steps configured after it.
+	for i, dependents := range d.dependents {
+		for _, j := range dependents {
+			writeEdge(b, id+strconv.Itoa(i), id+strconv.Itoa(j), depth)
+		}
+	}
+}
+
+// diagramNode describes the rendering of a step.
+type diagramNode struct {
+	id    string
+	name  string
+	marks string // rendered under the name
+}
+
+// writeStep renders the step as the node. A step which is a workflow is rendered as a subgraph, holding its own steps.
+func writeStep(b *bytes.Buffer, step any, node diagramNode, depth int) {
+	d, ok := step.(diagrammer)
+	if !ok {
+		indent(b, depth)
+		b.WriteString(node.id)
+		b.WriteString("[\"")
+		writeLabel(b, node.name, node.marks)
+		b.WriteString("\"]\n")
+
+		return
+	}
+	indent(b, depth)
+	b.WriteString("subgraph ")
+	b.WriteString(node.id)
+	b.WriteString(" [\"")
+	writeLabel(b, node.name, node.marks)
+	b.WriteString("\"]\n")
+	d.writeDiagram(b, node.id+"_", depth+1)
+	indent(b, depth)
+	b.WriteString("end\n")
+}
+
+// writeEdge renders the edge between the consecutive steps, if the step identified by stepID is not the first one.
+func writeEdge(b *bytes.Buffer, prevID, stepID string, depth int) {
+	if prevID == "" {
+		return
+	}
+	indent(b, depth)
+	b.WriteString(prevID)
+	b.WriteString(" --> ")
+	b.WriteString(stepID)
+	b.WriteString("\n")
+}
+
+// writeNode renders a standalone node.
+func writeNode(b *bytes.Buffer, id, label string, depth int) {
+	indent(b, depth)
+	b.WriteString(id)
+	b.WriteString("[\"")
+	writeLabel(b, label, "")
+	b.WriteString("\"]\n")
+}
+
+// stepMarks returns the marks rendered under the step name.
+func stepMarks(retryable, continueOnError bool) string {
+	switch {
+	case retryable && continueOnError:
+		return "<br/><i>retryable</i><br/><i>continues on error</i>"
+	case retryable:
+		return "<br/><i>retryable</i>"
+	case continueOnError:
+		return "<br/><i>continues on error</i>"
+	}
+
+	return ""
+}
+
+// writeLabel renders the name, with its quotes escaped, followed by the marks.
+func writeLabel(b *bytes.Buffer, name, marks string) {
+	rest := name
+	for {
+		i := strings.IndexByte(rest, '"')
+		if i < 0 {
+			break
+		}
+		b.WriteString(rest[:i])
+		b.WriteString("#quot;")
+		rest = rest[i+1:]
+	}
+	b.WriteString(rest)
+	b.WriteString(marks)
+}
+
+func indent(b *bytes.Buffer, depth int) {
+	for i := 0; i < depth; i++ {
+		b.WriteString("    ")
+	}
+}
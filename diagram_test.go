@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSequentialDiagram(t *testing.T) {
+	anyErr := errors.New("any-err")
+	nested := NewSequential("payment", []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("authorize")},
+		{Step: newStepSuccessful("capture")},
+	})
+	c := NewSequential("checkout", []SequentialStepConfig[any]{
+		{Step: newStepSuccessful(`validate "order"`)},
+		{Step: newStepFailedRetryable("reserve stock", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+		{Step: nested},
+		{Step: newStepSuccessful("notify"), ContinueWorkflowOnError: true},
+	})
+
+	actualOutput := c.Diagram()
+
+	expectedOutput := `flowchart TD
+    s0["validate #quot;order#quot;"]
+    s1["reserve stock<br/><i>retryable</i>"]
+    s0 --> s1
+    subgraph s2 ["payment"]
+        s2_0["authorize"]
+        s2_1["capture"]
+        s2_0 --> s2_1
+    end
+    s1 --> s2
+    s3["notify<br/><i>continues on error</i>"]
+    s2 --> s3
+`
+	if actualOutput != expectedOutput {
+		t.Errorf("The diagram is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestPipeDiagram(t *testing.T) {
+	anyErr := errors.New("any-err")
+	c := NewPipe("some-workflow", []PipeStepConfig[any]{
+		{Step: newPipeStepFailedRetryable[any]("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, ContinueOnError: true},
+		{Step: NewPipe("nested", []PipeStepConfig[any]{{Step: newPipeStepFailedRetryable[any]("step 2", anyErr)}})},
+	})
+
+	actualOutput := c.Diagram()
+
+	expectedOutput := `flowchart TD
+    s0["step 1<br/><i>retryable</i><br/><i>continues on error</i>"]
+    subgraph s1 ["nested"]
+        s1_0["step 2"]
+    end
+    s0 --> s1
+`
+	if actualOutput != expectedOutput {
+		t.Errorf("The diagram is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestDynamicSequentialDiagram(t *testing.T) {
+	c := NewDynamicSequential("some-workflow", func(ctx context.Context, req any) []SequentialStepConfig[any] { return nil })
+
+	actualOutput := c.Diagram()
+
+	expectedOutput := "flowchart TD\n    s0[\"dynamic steps\"]\n"
+	if actualOutput != expectedOutput {
+		t.Errorf("The diagram is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestSequentialDiagramBehaviourOnNilStep(t *testing.T) {
+	c := NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}, {Step: nil}})
+
+	actualOutput := c.Diagram()
+
+	expectedOutput := `flowchart TD
+    s0["step 1"]
+    s1["the step at index: 1 is nil"]
+    s0 --> s1
+`
+	if actualOutput != expectedOutput {
+		t.Errorf("The diagram is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestPipeDiagramBehaviourOnNilStep(t *testing.T) {
+	c := NewPipe("some-workflow", []PipeStepConfig[any]{{Step: nil}})
+
+	actualOutput := c.Diagram()
+
+	expectedOutput := `flowchart TD
+    s0["the step at index: 0 is nil"]
+`
+	if actualOutput != expectedOutput {
+		t.Errorf("The diagram is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestParallelDiagram(t *testing.T) {
+	nested := NewParallel("nested", []ParallelStepConfig[any]{{Step: newStepSuccessful("step 3")}})
+	c := NewSequential("some-workflow", []SequentialStepConfig[any]{
+		{Step: NewParallel("parallel", []ParallelStepConfig[any]{
+			{Step: newStepSuccessful("step 1"), RetryConfigProvider: defaultRetryConfigProviderTest},
+			{Step: newStepSuccessful("step 2")},
+			{Step: nested},
+		})},
+	})
+
+	actualOutput := c.Diagram()
+
+	expectedOutput := `flowchart TD
+    subgraph s0 ["parallel"]
+        s0_0["step 1<br/><i>retryable</i>"]
+        s0_1["step 2"]
+        subgraph s0_2 ["nested"]
+            s0_2_0["step 3"]
+        end
+    end
+`
+	if actualOutput != expectedOutput {
+		t.Errorf("The diagram is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestDAGDiagram(t *testing.T) {
+	d, err := NewDAG("some-workflow", []DAGStepConfig[any]{
+		{Step: newStepSuccessful("c"), DependsOn: []string{"a", "b"}},
+		{Step: newStepSuccessful("a"), RetryConfigProvider: defaultRetryConfigProviderTest},
+		{Step: newStepSuccessful("b"), DependsOn: []string{"a"}},
+	})
+	if err != nil {
+		t.Fatalf("The workflow returned an unexpected error: %v", err)
+	}
+
+	actualOutput := d.Diagram()
+
+	// the edges link every step to its dependents, whatever their configuration order.
+	expectedOutput := `flowchart TD
+    s0["c"]
+    s1["a<br/><i>retryable</i>"]
+    s2["b"]
+    s1 --> s0
+    s1 --> s2
+    s2 --> s0
+`
+	if actualOutput != expectedOutput {
+		t.Errorf("The diagram is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
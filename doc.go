@@ -29,7 +29,8 @@ For granular control over the workflow behaviour, use ContinueWorkflowOnError an
 
 	sc := []SequentialStepConfig[any]{
 		{
-			// step1 must implement the RetryDecider interface, and return true for CanRetry() in order to be retryable
+			// step1 must implement the RetryDecider interface, and return true for CanRetry() in order to be retryable, or
+			// the ErrorRetryDecider interface, and return true for RetryableError(err), for a decision based on the error
 			SequentialStep: step1,
 			ContinueWorkflowOnError: true,
 			// this has effect only if step1 is retryable
@@ -49,7 +50,43 @@ For logging, just provide a logger that implements the workflow.Logger interface
 	// in real life usage, this must be a concrete type implementing the workflow.Logger interface.
 	var myLogger workflow.Logger
 	...
-	wf := workflow.NewSequential("example", sc, myLogger)
+	wf := workflow.NewSequential("example", sc, workflow.WithLogger(myLogger))
+	...
+
+The retry attempts and waits are logged at the Debug level, and the failures that will be retried at the Warn level.
+If the logger doesn't implement the workflow.DebugLogger and workflow.WarnLogger interfaces, these messages are sent to
+Info and Error.
+
+If the logger also implements the workflow.StructuredLogger interface, the workflow name, the step name and the attempt
+are attached to the log messages as fields. For log/slog users, the workflow.SlogLogger adapter is available:
+
+	wf := workflow.NewSequential("example", sc, workflow.WithLogger(workflow.NewSlogLogger(slog.Default())))
+	...
+
+For observing every step execution attempt(e.g. metrics, tracing), register hooks:
+
+	wf := workflow.NewSequential(
+		"example",
+		sc,
+		workflow.WithBeforeStep(func(ctx context.Context, stepName string) {...}),
+		workflow.WithAfterStep(func(ctx context.Context, stepName string, err error, attempt int) {...}),
+	)
+	...
+
+For steps sharing an accumulated state, use a pointer for the request type, and let the steps mutate it:
+
+	type State struct {
+		UserID string
+		Orders []Order
+	}
+	// every step receives the same *State, and sees the changes of the previous steps.
+	sc := []SequentialStepConfig[*State]{
+		{Step: loadOrders},
+		{Step: computeTotals},
+	}
+	// a new state for every execution, as the steps run one at a time, but the executions may run concurrently.
+	state := &State{UserID: "42"}
+	err := workflow.NewSequential("example", sc).Execute(context.Background(), state)
 	...
 
 2. Pipe workflow:
@@ -73,7 +110,38 @@ For logging, just provide a logger that implements the workflow.Logger interface
 	// in real life usage, this must be a concrete type implementing the workflow.Logger interface.
 	var myLogger workflow.Logger
 	...
-	wf := workflow.NewPipe("example", sc, myLogger)
+	wf := workflow.NewPipe("example", sc, workflow.WithPipeLogger(myLogger))
+	...
+
+3. DAG workflow:
+The steps run once all the steps they depend on succeeded, and the independent steps run in parallel:
+
+	// in real life usage, these must be concrete types implementing the workflow.SequentialStep interface.
+	var stepA, stepB, stepC, stepD SequentialStep
+	...
+	// stepB and stepC run in parallel, after stepA, and stepD runs after both of them.
+	sc := []DAGStepConfig[any]{
+		{Step: stepA},
+		{Step: stepB, DependsOn: []string{stepA.Name()}},
+		{Step: stepC, DependsOn: []string{stepA.Name()}},
+		{Step: stepD, DependsOn: []string{stepB.Name(), stepC.Name()}},
+	}
+	// the constructor returns an error for unknown dependencies and dependency cycles.
+	wf, err := workflow.NewDAG("example", sc)
+	...
+	err = wf.Execute(context.Background(), &req)
+	...
+
+4. Parallel workflow:
+All the steps run at the same time, and every step error is collected, unless WithFailFast is used:
+
+	sc := []ParallelStepConfig[any]{
+		{Step: step1},
+		{Step: step2},
+	}
+	// the first failure cancels the ctx of the running steps, whose errors are held by a workflow.AbortedError.
+	wf := workflow.NewParallel("example", sc, workflow.WithFailFast())
+	err := wf.Execute(context.Background(), &req)
 	...
 */
 package workflow
@@ -3,7 +3,11 @@ package workflow_test
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/silviutanasa/workflow"
 )
@@ -47,6 +51,265 @@ func ExamplePipe_Execute() {
 
 }
 
+// counter is satisfied by prometheus.Counter.
+type counter interface {
+	Inc()
+}
+
+// observer is satisfied by prometheus.Observer, e.g. a prometheus.Histogram.
+type observer interface {
+	Observe(float64)
+}
+
+// promMetrics adapts Prometheus collectors to the workflow.Metrics interface.
+// In real life usage, the collectors are vectors labeled by the step name, e.g. stepsTotal.WithLabelValues(stepName).Inc().
+type promMetrics struct {
+	stepsTotal    counter
+	failuresTotal counter
+	retriesTotal  counter
+	duration      observer
+}
+
+func (m *promMetrics) StepStarted(_ context.Context, _ string) {
+	m.stepsTotal.Inc()
+}
+
+func (m *promMetrics) StepRetried(_ context.Context, _ string, _ uint) {
+	m.retriesTotal.Inc()
+}
+
+func (m *promMetrics) StepFinished(_ context.Context, _ string, status workflow.StepStatus, duration time.Duration) {
+	if status == workflow.StepStatusFailed {
+		m.failuresTotal.Inc()
+	}
+	m.duration.Observe(duration.Seconds())
+}
+
+type countMetric int
+
+func (c *countMetric) Inc() {
+	*c++
+}
+
+func (c *countMetric) Observe(_ float64) {
+	*c++
+}
+
+func ExampleWithMetrics() {
+	// in real life usage, these are prometheus.Counter and prometheus.Histogram collectors.
+	var steps, failures, retries, durations countMetric
+	m := promMetrics{stepsTotal: &steps, failuresTotal: &failures, retriesTotal: &retries, duration: &durations}
+
+	stepsCfg := []workflow.SequentialStepConfig[any]{
+		{Step: &sequentialStepAbstract{name: "extract-data"}},
+		{Step: &sequentialStepAbstract{name: "load-data"}},
+	}
+	wf := workflow.NewSequential("ETL", stepsCfg, workflow.WithMetrics(&m))
+	wf.Execute(context.TODO(), nil)
+	fmt.Printf("\nsteps: %d, failures: %d, retries: %d, observed durations: %d", steps, failures, retries, durations)
+	// Output:
+	//running: extract-data
+	//running: load-data
+	//steps: 2, failures: 0, retries: 0, observed durations: 2
+}
+
+func ExampleThen() {
+	// every step transforms the value to the type expected by the next one: string -> int -> bool.
+	wf := workflow.Then[string, int, bool](
+		workflow.NewChain[string, int]("is-even", &parseInt{name: "parse-int"}),
+		&isEven{name: "is-even"},
+	)
+	even, _ := wf.Execute(context.TODO(), "42")
+	fmt.Print(even)
+	// Output:
+	//true
+}
+
+func ExampleFanOut() {
+	// the stock of every item of the order is reserved in parallel, at most 2 items at the same time, before the payment.
+	var reserved reservations
+	stepsCfg := []workflow.SequentialStepConfig[*order]{
+		{Step: workflow.NewFanOut[*order, string](
+			"reserve-stock",
+			func(o *order) []string { return o.items },
+			&reserveItem{name: "reserve-item", reserved: &reserved},
+			2,
+		)},
+		{Step: &applyDiscount{name: "apply-discount", percent: 10}},
+	}
+
+	wf := workflow.NewSequential("checkout", stepsCfg)
+	wf.Execute(context.TODO(), &order{items: []string{"pen", "book", "bag"}, total: 20})
+	// the items are reserved in any order.
+	sort.Strings(reserved.items)
+	fmt.Print(reserved.items)
+	// Output:
+	//[bag book pen]
+}
+
+func ExampleAsSequentialStep() {
+	// the Pipe cleans up the note of the order, and its output replaces the note shared by the checkout steps.
+	cleanNote := workflow.NewPipe("clean-note", []workflow.PipeStepConfig[string]{
+		{Step: &trimSpaces{name: "trim-spaces"}},
+		{Step: &removeCommas{name: "remove-commas"}},
+	})
+	stepsCfg := []workflow.SequentialStepConfig[*order]{
+		{Step: &addItem{name: "add-book", item: "book", price: 12}},
+		{Step: workflow.AsSequentialStep[*order, string](
+			cleanNote,
+			func(o *order) string { return o.note },
+			func(o *order, note string) { o.note = note },
+		)},
+	}
+
+	wf := workflow.NewSequential("checkout", stepsCfg)
+	o := &order{note: "  leave it at the door, please  "}
+	wf.Execute(context.TODO(), o)
+	fmt.Printf("%v: %q", o.items, o.note)
+	// Output:
+	//[book]: "leave it at the door please"
+}
+
+func ExampleStepGroup() {
+	// the extraction steps share the retry configuration, and the notification steps don't stop the workflow on error.
+	extract := workflow.StepGroup[any]{
+		Steps: []workflow.SequentialStep[any]{
+			&sequentialStepAbstract{name: "extract-users"},
+			&sequentialStepAbstract{name: "extract-orders"},
+		},
+		Config: workflow.SequentialStepConfig[any]{
+			RetryConfigProvider: func() (uint, time.Duration) { return 3, time.Second },
+			Backoff:             workflow.ExponentialBackoff{Base: time.Second, Max: time.Minute},
+		},
+	}
+	notify := workflow.StepGroup[any]{
+		Steps: []workflow.SequentialStep[any]{
+			&sequentialStepAbstract{name: "notify-by-email"},
+			&sequentialStepAbstract{name: "notify-by-sms"},
+		},
+		Config: workflow.SequentialStepConfig[any]{ContinueWorkflowOnError: true},
+	}
+
+	stepsCfg := extract.Configs()
+	stepsCfg = append(stepsCfg, workflow.SequentialStepConfig[any]{Step: &sequentialStepAbstract{name: "load-data"}})
+	stepsCfg = append(stepsCfg, notify.Configs()...)
+	wf := workflow.NewSequential("ETL", stepsCfg, nil)
+	wf.Execute(context.TODO(), nil)
+	// Output:
+	//running: extract-users
+	//running: extract-orders
+	//running: load-data
+	//running: notify-by-email
+	//running: notify-by-sms
+}
+
+func ExampleSequential_sharedState() {
+	// the steps share the order, through a pointer, and every step sees the changes of the previous ones.
+	stepsCfg := []workflow.SequentialStepConfig[*order]{
+		{Step: &addItem{name: "add-book", item: "book", price: 12}},
+		{Step: &addItem{name: "add-pen", item: "pen", price: 3}},
+		{Step: &applyDiscount{name: "apply-discount", percent: 10}},
+	}
+
+	wf := workflow.NewSequential("checkout", stepsCfg)
+	// a new state for every execution.
+	o := &order{}
+	wf.Execute(context.TODO(), o)
+	fmt.Printf("%v: %.2f", o.items, o.total)
+	// Output:
+	//[book pen]: 13.50
+}
+
+func ExampleBuilder() {
+	// the same as the NewSequential example, the modifiers configuring the last added step.
+	wf, err := workflow.NewBuilder[any]("ETL").
+		AddStep(&sequentialStepAbstract{name: "extract-data"}).WithTimeout(time.Minute).
+		AddStep(&sequentialStepAbstract{name: "load-data"}).
+		AddStep(&sequentialStepAbstract{name: "notify"}).ContinueOnError().
+		Build()
+	if err != nil {
+		fmt.Print(err)
+
+		return
+	}
+	wf.Execute(context.TODO(), nil)
+	// Output:
+	//running: extract-data
+	//running: load-data
+	//running: notify
+}
+
+func ExamplePipeBuilder() {
+	wf, _ := workflow.NewPipeBuilder[string]("ETL").
+		AddStep(&trimSpaces{name: "trim-spaces"}).
+		AddStep(&removeCommas{name: "remove-commas"}).
+		AddStep(&transformToUppercase{name: "transform-to-upper"}).
+		Build()
+	output, _ := wf.Execute(context.TODO(), "  a, b  ")
+	fmt.Print(output)
+	// Output:
+	//A B
+}
+
+// order is the state accumulated by the checkout steps.
+type order struct {
+	items []string
+	total float64
+	note  string
+}
+
+type addItem struct {
+	name  string
+	item  string
+	price float64
+}
+
+func (s *addItem) Name() string {
+	return s.name
+}
+
+func (s *addItem) Execute(_ context.Context, o *order) error {
+	o.items = append(o.items, s.item)
+	o.total += s.price
+	return nil
+}
+
+// reservations collects the items reserved concurrently by the reserveItem step.
+type reservations struct {
+	mu    sync.Mutex
+	items []string
+}
+
+type reserveItem struct {
+	name     string
+	reserved *reservations
+}
+
+func (s *reserveItem) Name() string {
+	return s.name
+}
+
+func (s *reserveItem) Execute(_ context.Context, item string) error {
+	s.reserved.mu.Lock()
+	defer s.reserved.mu.Unlock()
+	s.reserved.items = append(s.reserved.items, item)
+	return nil
+}
+
+type applyDiscount struct {
+	name    string
+	percent float64
+}
+
+func (s *applyDiscount) Name() string {
+	return s.name
+}
+
+func (s *applyDiscount) Execute(_ context.Context, o *order) error {
+	o.total -= o.total * s.percent / 100
+	return nil
+}
+
 type sequentialStepAbstract struct {
 	name string
 }
@@ -107,3 +370,27 @@ func (s *removeDots) Name() string {
 func (s *removeDots) Execute(ctx context.Context, req string) (string, error) {
 	return strings.ReplaceAll(req, ".", ""), nil
 }
+
+type parseInt struct {
+	name string
+}
+
+func (s *parseInt) Name() string {
+	return s.name
+}
+
+func (s *parseInt) Execute(ctx context.Context, req string) (int, error) {
+	return strconv.Atoi(req)
+}
+
+type isEven struct {
+	name string
+}
+
+func (s *isEven) Name() string {
+	return s.name
+}
+
+func (s *isEven) Execute(ctx context.Context, req int) (bool, error) {
+	return req%2 == 0, nil
+}
@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// executionID provides the ID of every execution of a workflow, see WithExecutionID.
+type executionID struct {
+	id string
+	fn func(ctx context.Context) string // provides the ID of every execution, taking precedence over the id, if not nil
+}
+
+// WithExecutionID sets the ID identifying the executions of the workflow in the logs, e.g. to tell apart the replays
+// of an event sharing its correlation ID, which identifies the stored results, see WithCorrelationID. It's attached to
+// every log line, as a field for a StructuredLogger, and appended to the message otherwise, and it's carried by the
+// ctx passed to the steps, the hooks and the Metrics, read by ExecutionIDFromContext. The nested workflows without an ID of their
+// own log the ID of their parent. See WithExecutionIDFunc for an ID unique to every execution of a reused workflow.
+func WithExecutionID(id string) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.execID.id = id
+	}
+}
+
+// WithExecutionIDFunc sets the function providing the ID of every execution, called once at its start, as described by
+// WithExecutionID, e.g. NewExecutionID, or a function reading the ID of the request from the ctx. It takes precedence
+// over WithExecutionID, and an empty ID means no ID for the execution.
+func WithExecutionIDFunc(fn func(ctx context.Context) string) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.execID.fn = fn
+	}
+}
+
+// WithPipeExecutionID is the Pipe version of WithExecutionID.
+func WithPipeExecutionID(id string) PipeOption {
+	return func(o *pipeOptions) {
+		o.execID.id = id
+	}
+}
+
+// WithPipeExecutionIDFunc is the Pipe version of WithExecutionIDFunc.
+func WithPipeExecutionIDFunc(fn func(ctx context.Context) string) PipeOption {
+	return func(o *pipeOptions) {
+		o.execID.fn = fn
+	}
+}
+
+// ExecutionIDFromContext returns the ID of the execution the ctx was passed to, and reports whether it is set, which
+// requires WithExecutionID or WithExecutionIDFunc, or their Pipe versions.
+func ExecutionIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(executionIDKey).(string)
+
+	return id, ok
+}
+
+// NewExecutionID returns a random UUID, version 4. It takes an unused ctx, so it can be passed to WithExecutionIDFunc.
+func NewExecutionID(_ context.Context) string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
+
+// context returns the ctx carrying the ID of the execution, or the ctx itself if there is none.
+func (e executionID) context(ctx context.Context) context.Context {
+	id := e.id
+	if e.fn != nil {
+		id = e.fn(ctx)
+	}
+	if id == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, executionIDKey, id)
+}
+
+// withExecutionID returns the fields of a structured log line, along with the ID of the execution carried by the ctx,
+// if any.
+func withExecutionID(ctx context.Context, fields ...Field) []Field {
+	if id, ok := ExecutionIDFromContext(ctx); ok {
+		return append(fields, Field{Key: "execution", Value: id})
+	}
+
+	return fields
+}
+
+// writeExecutionID appends the ID of the execution carried by the ctx, if any, to the message of an unstructured log
+// line, e.g. "✓ executing step: X in 42ms, execution: Y".
+func writeExecutionID(ctx context.Context, b *bytes.Buffer) {
+	if id, ok := ExecutionIDFromContext(ctx); ok {
+		b.WriteString(", execution: ")
+		b.WriteString(id)
+	}
+}
@@ -0,0 +1,89 @@
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnExecutionID(t *testing.T) {
+	anyErr := errors.New("any-err")
+	log := &loggerMock{}
+	var hookID string
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepFailedNonRetryable("step 2", anyErr)},
+	}
+
+	NewSequential(
+		"some-workflow",
+		input,
+		WithLogger(log),
+		WithExecutionID("exec-1"),
+		WithCorrelationID("id-1"),
+		WithOnComplete(func(ctx context.Context, err error) { hookID, _ = ExecutionIDFromContext(ctx) }),
+	).Execute(context.TODO(), nil)
+
+	msgs := append(log.info, log.error...)
+	if len(msgs) == 0 {
+		t.Fatalf("The workflow logged no message")
+	}
+	for _, msg := range msgs {
+		if !strings.HasSuffix(msg, ", execution: exec-1") {
+			t.Errorf("The log message doesn't hold the execution ID: \n actual = %#v", msg)
+		}
+	}
+	if hookID != "exec-1" {
+		t.Errorf("The hook execution ID is not as expected: \n expected = %#v, \n actual = %#v", "exec-1", hookID)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnExecutionIDFunc(t *testing.T) {
+	var ids []string
+	step := stepFuncMock(func(ctx context.Context, request any) error {
+		id, _ := ExecutionIDFromContext(ctx)
+		ids = append(ids, id)
+
+		return nil
+	})
+	wf := NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: step}}, WithExecutionIDFunc(NewExecutionID))
+
+	wf.Execute(context.TODO(), nil)
+	wf.Execute(context.TODO(), nil)
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if len(ids) != 2 || !uuid.MatchString(ids[0]) || !uuid.MatchString(ids[1]) || ids[0] == ids[1] {
+		t.Errorf("The execution IDs are not as expected: \n actual = %#v", ids)
+	}
+}
+
+func TestPipeExecuteBehaviourOnExecutionID(t *testing.T) {
+	var buf bytes.Buffer
+	input := []PipeStepConfig[string]{{Step: newPipeStepAppend("step 1", "-a")}}
+
+	NewPipe("some-workflow", input, WithPipeLogger(NewJSONLogger(&buf)), WithPipeExecutionID("exec-1")).
+		Execute(context.TODO(), "x")
+
+	scanner := bufio.NewScanner(&buf)
+	var count int
+	for scanner.Scan() {
+		var r struct {
+			Execution string `json:"execution"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("The log line is not valid JSON: %v, \n line = %s", err, scanner.Text())
+		}
+		if r.Execution != "exec-1" {
+			t.Errorf("The log line execution ID is not as expected: \n expected = %#v, \n actual = %#v", "exec-1", scanner.Text())
+		}
+		count++
+	}
+	if count == 0 {
+		t.Errorf("The workflow logged no line")
+	}
+}
@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// FanOut is a SequentialStep running its sub-step for every element of the req, concurrently, as a single step, e.g.
+// to process the items carried by the req in parallel, while the workflow keeps its sequential semantics: the step
+// following the FanOut starts once the sub-step is done for all the elements.
+// The FanOut fails if the sub-step fails for any element, with the errors of all the failing elements joined, in the
+// elements order. Once the ctx is done, the sub-step is not started for the remaining elements, and the ctx error is
+// returned along with the errors of the started ones.
+// The FanOut doesn't implement the RetryDecider interface, so it's not retried, as retrying it would run the sub-step
+// again for the succeeded elements. A sub-step needing retries can be a Sequential, wrapping it.
+// The FanOut holds no execution state, so it can be executed concurrently.
+type FanOut[T, E any] struct {
+	name        string
+	elements    func(req T) []E
+	step        SequentialStep[E]
+	concurrency uint
+}
+
+// NewFanOut is the FanOut constructor. The elements extracts the elements of the req the step runs for, and the
+// concurrency bounds the elements processed at the same time, all of them if 0.
+func NewFanOut[T, E any](name string, elements func(req T) []E, step SequentialStep[E], concurrency uint) *FanOut[T, E] {
+	return &FanOut[T, E]{name: name, elements: elements, step: step, concurrency: concurrency}
+}
+
+// Name returns the name of the step.
+func (f *FanOut[T, E]) Name() string {
+	return f.name
+}
+
+// Execute runs the sub-step for every element of the req, as described by FanOut.
+func (f *FanOut[T, E]) Execute(ctx context.Context, req T) error {
+	elements := f.elements(req)
+	concurrency := int(f.concurrency)
+	if concurrency == 0 || concurrency > len(elements) {
+		concurrency = len(elements)
+	}
+
+	// the last slot holds the ctx error, if the ctx is done before all the elements are started.
+	errs := make([]error, len(elements)+1)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, element := range elements {
+		sem <- struct{}{}
+		// the ctx may be done while waiting for a free slot.
+		if err := ctx.Err(); err != nil {
+			errs[len(elements)] = err
+
+			break
+		}
+		wg.Add(1)
+		go func(i int, element E) {
+			defer wg.Done()
+			errs[i] = f.step.Execute(ctx, element)
+			<-sem
+		}(i, element)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,166 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFanOutExecute(t *testing.T) {
+	errOdd := errors.New("odd element")
+	tests := []struct {
+		name           string
+		input          []int
+		concurrency    uint
+		expectedOutput []error
+	}{
+		{
+			name:        "no element should succeed",
+			input:       nil,
+			concurrency: 2,
+		},
+		{
+			name:        "all the elements succeeding should succeed",
+			input:       []int{2, 4, 6, 8},
+			concurrency: 2,
+		},
+		{
+			name:           "the failing elements should fail the step, with all their errors",
+			input:          []int{1, 2, 3, 4},
+			concurrency:    2,
+			expectedOutput: []error{errOdd},
+		},
+		{
+			name:           "a zero concurrency should run all the elements",
+			input:          []int{1, 2, 3, 4},
+			concurrency:    0,
+			expectedOutput: []error{errOdd},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var actualElements []int
+			var running, maxRunning int32
+			step := &fanOutStepMock{name: "sub-step", fn: func(ctx context.Context, element int) error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for m := atomic.LoadInt32(&maxRunning); n > m && !atomic.CompareAndSwapInt32(&maxRunning, m, n); {
+					m = atomic.LoadInt32(&maxRunning)
+				}
+				mu.Lock()
+				actualElements = append(actualElements, element)
+				mu.Unlock()
+				if element%2 != 0 {
+					return errOdd
+				}
+
+				return nil
+			}}
+			fanOut := NewFanOut[[]int, int]("fan-out", func(req []int) []int { return req }, step, tt.concurrency)
+
+			err := fanOut.Execute(context.TODO(), tt.input)
+
+			for _, expectedErr := range tt.expectedOutput {
+				if !errors.Is(err, expectedErr) {
+					t.Errorf("The step error is not as expected: \n expected = %#v, \n actual = %#v", expectedErr, err)
+				}
+			}
+			if tt.expectedOutput == nil && err != nil {
+				t.Errorf("The step returned an unexpected error: %v", err)
+			}
+			if len(actualElements) != len(tt.input) {
+				t.Errorf("The processed elements are not as expected: \n expected = %#v, \n actual = %#v", tt.input, actualElements)
+			}
+			if tt.concurrency > 0 && maxRunning > int32(tt.concurrency) {
+				t.Errorf("The concurrency bound is not honored: \n expected = %#v, \n actual = %#v", tt.concurrency, maxRunning)
+			}
+		})
+	}
+}
+
+func TestFanOutExecuteBehaviourOnErrorsOrder(t *testing.T) {
+	step := &fanOutStepMock{name: "sub-step", fn: func(ctx context.Context, element int) error {
+		return StepError{StepName: "sub-step", Attempts: uint(element), Err: errors.New("any-err")}
+	}}
+	fanOut := NewFanOut[[]int, int]("fan-out", func(req []int) []int { return req }, step, 3)
+
+	err := fanOut.Execute(context.TODO(), []int{1, 2, 3})
+
+	var actualOutput []uint
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		actualOutput = append(actualOutput, err.(StepError).Attempts)
+	}
+	expectedOutput := []uint{1, 2, 3}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The errors order is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestFanOutExecuteBehaviourOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var invocationCount int32
+	step := &fanOutStepMock{name: "sub-step", fn: func(ctx context.Context, element int) error {
+		atomic.AddInt32(&invocationCount, 1)
+		cancel()
+
+		return nil
+	}}
+	fanOut := NewFanOut[[]int, int]("fan-out", func(req []int) []int { return req }, step, 1)
+
+	err := fanOut.Execute(ctx, []int{1, 2, 3})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("The step error is not as expected: \n expected = %#v, \n actual = %#v", context.Canceled, err)
+	}
+	// the first element cancels the ctx, so the next ones are not started.
+	if invocationCount != 1 {
+		t.Errorf("The sub-step invocation count is not as expected: \n expected = %#v, \n actual = %#v", 1, invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnFanOut(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var sum int64
+	step := &fanOutStepMock{name: "add", fn: func(ctx context.Context, element int) error {
+		if element < 0 {
+			return anyErr
+		}
+		atomic.AddInt64(&sum, int64(element))
+
+		return nil
+	}}
+	input := []SequentialStepConfig[[]int]{
+		{Step: NewFanOut[[]int, int]("fan-out", func(req []int) []int { return req }, step, 2)},
+	}
+
+	err := NewSequential("some-workflow", input, nil).Execute(context.TODO(), []int{1, 2, -3, 4})
+
+	var stepErr StepError
+	if !errors.As(err, &stepErr) || stepErr.StepName != "fan-out" || !errors.Is(err, anyErr) {
+		t.Errorf("The workflow error is not as expected: \n actual = %#v", err)
+	}
+	// the elements following the failing one are still processed.
+	if sum != 7 {
+		t.Errorf("The elements are not all processed: \n expected = %#v, \n actual = %#v", 7, sum)
+	}
+}
+
+// MOCKS/STUBS
+
+// fanOutStepMock is a SequentialStep running its fn for every element.
+type fanOutStepMock struct {
+	name string
+	fn   func(ctx context.Context, element int) error
+}
+
+func (s *fanOutStepMock) Name() string {
+	return s.name
+}
+
+func (s *fanOutStepMock) Execute(ctx context.Context, element int) error {
+	return s.fn(ctx, element)
+}
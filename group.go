@@ -0,0 +1,25 @@
+package workflow
+
+// StepGroup applies a shared configuration to consecutive steps, e.g. to make them all continue the workflow on error,
+// with the same retry configuration, instead of repeating the configuration for every step.
+type StepGroup[T any] struct {
+	Steps []SequentialStep[T]
+	// Config is the configuration shared by the Steps. Its Step, IdempotencyKey and NameOverride are ignored, as they
+	// identify a single step.
+	Config SequentialStepConfig[T]
+}
+
+// Configs expands the group into the configurations of its steps, following the Steps order, which compose with the
+// individually configured steps, e.g. by using append.
+func (g StepGroup[T]) Configs() []SequentialStepConfig[T] {
+	stepsCfg := make([]SequentialStepConfig[T], len(g.Steps))
+	for i, step := range g.Steps {
+		stepCfg := g.Config
+		stepCfg.Step = step
+		stepCfg.IdempotencyKey = ""
+		stepCfg.NameOverride = ""
+		stepsCfg[i] = stepCfg
+	}
+
+	return stepsCfg
+}
@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStepGroupConfigs(t *testing.T) {
+	step1, step2 := newStepSuccessful("step 1"), newStepSuccessful("step 2")
+	shouldRun := func(ctx context.Context, req any) bool { return true }
+	g := StepGroup[any]{
+		Steps: []SequentialStep[any]{step1, step2},
+		Config: SequentialStepConfig[any]{
+			Step:                    newStepSuccessful("ignored"),
+			ContinueWorkflowOnError: true,
+			RetryConfigProvider:     defaultRetryConfigProviderTest,
+			Backoff:                 ConstantBackoff(time.Second),
+			RetryBudget:             time.Minute,
+			ShouldRun:               shouldRun,
+			IdempotencyKey:          "ignored",
+			NameOverride:            "ignored",
+		},
+	}
+
+	actualOutput := g.Configs()
+
+	if len(actualOutput) != 2 {
+		t.Fatalf("The number of configs is not as expected: \n expected = %#v, \n actual = %#v", 2, len(actualOutput))
+	}
+	for i, step := range []*stepMock{step1, step2} {
+		stepCfg := actualOutput[i]
+		if stepCfg.Step != step {
+			t.Errorf("The step of the config %d is not as expected: \n expected = %#v, \n actual = %#v", i, step, stepCfg.Step)
+		}
+		if !stepCfg.ContinueWorkflowOnError || stepCfg.RetryConfigProvider == nil || stepCfg.ShouldRun == nil ||
+			stepCfg.Backoff != ConstantBackoff(time.Second) || stepCfg.RetryBudget != time.Minute {
+			t.Errorf("The config %d doesn't hold the shared configuration: \n actual = %#v", i, stepCfg)
+		}
+		if stepCfg.IdempotencyKey != "" || stepCfg.NameOverride != "" {
+			t.Errorf("The config %d holds the step identifiers of the shared configuration: \n actual = %#v", i, stepCfg)
+		}
+	}
+}
+
+func TestStepGroupComposesWithSteps(t *testing.T) {
+	anyErr := errors.New("any-err")
+	optional := StepGroup[any]{
+		Steps: []SequentialStep[any]{
+			newStepFailedNonRetryable("optional 1", anyErr),
+			newStepFailedNonRetryable("optional 2", anyErr),
+		},
+		Config: SequentialStepConfig[any]{ContinueWorkflowOnError: true},
+	}
+	last := newStepSuccessful("last")
+	stepsCfg := append(optional.Configs(), SequentialStepConfig[any]{Step: last})
+
+	err := NewSequential("some-workflow", stepsCfg).Execute(context.TODO(), nil)
+
+	expectedOutput := []string{"optional 1", "optional 2"}
+	if actualOutput := FailedSteps(err); len(actualOutput) != 2 || actualOutput[0] != expectedOutput[0] || actualOutput[1] != expectedOutput[1] {
+		t.Errorf("The failed steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	if last.invocationCount != 1 {
+		t.Errorf("The step following the group did not run: \n invocation count = %#v", last.invocationCount)
+	}
+}
@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONLogger is a StructuredLogger writing every message as a JSON object, on its own line, e.g.
+// {"time":"2024-01-02T15:04:05.000000006Z","level":"info","msg":"[START] executing workflow: ETL","workflow":"ETL"}.
+// It provides machine readable logs without a third party logger. It is safe for concurrent use.
+type JSONLogger struct {
+	mu sync.Mutex // serializes the writes, so the lines don't interleave
+	w  io.Writer
+}
+
+// NewJSONLogger is the JSONLogger constructor, writing the log lines to w.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{w: w}
+}
+
+// Debug is the Debug level log.
+func (l *JSONLogger) Debug(msg string) {
+	l.Log(context.Background(), LevelDebug, msg)
+}
+
+// Info is the Info level log.
+func (l *JSONLogger) Info(msg string) {
+	l.Log(context.Background(), LevelInfo, msg)
+}
+
+// Warn is the Warn level log.
+func (l *JSONLogger) Warn(msg string) {
+	l.Log(context.Background(), LevelWarn, msg)
+}
+
+// Error is the Error level log.
+func (l *JSONLogger) Error(msg string) {
+	l.Log(context.Background(), LevelError, msg)
+}
+
+// Log implements the StructuredLogger interface, by writing the fields as members of the JSON object, after the time,
+// the level and the msg. A field value which can't be encoded as JSON is written as its default string format.
+func (l *JSONLogger) Log(_ context.Context, level Level, msg string, fields ...Field) {
+	var b bytes.Buffer
+	b.WriteString(`{"time":`)
+	writeJSONValue(&b, time.Now().UTC().Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeJSONValue(&b, levelName(level))
+	b.WriteString(`,"msg":`)
+	writeJSONValue(&b, msg)
+	for _, f := range fields {
+		b.WriteString(",")
+		writeJSONValue(&b, f.Key)
+		b.WriteString(":")
+		writeJSONValue(&b, f.Value)
+	}
+	b.WriteString("}\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// a Logger has no way to report its own errors.
+	_, _ = l.w.Write(b.Bytes())
+}
+
+// writeJSONValue writes the v encoded as JSON, or its default string format, encoded as JSON, if it can't be encoded.
+func writeJSONValue(b *bytes.Buffer, v any) {
+	enc, err := json.Marshal(v)
+	if err != nil {
+		enc, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.WriteString(string(enc))
+}
+
+// levelName returns the name of the level, as written by the JSONLogger.
+func levelName(level Level) string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+
+	return "info"
+}
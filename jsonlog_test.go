@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJSONLoggerWritesJSONLines(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var buf bytes.Buffer
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step \"1\"")},
+		{Step: newStepFailedNonRetryable("step 2", anyErr)},
+	}
+
+	NewSequential("some-workflow", input, WithLogger(NewJSONLogger(&buf))).Execute(context.TODO(), nil)
+
+	type record struct {
+		Time     string `json:"time"`
+		Level    string `json:"level"`
+		Msg      string `json:"msg"`
+		Workflow string `json:"workflow"`
+		Step     string `json:"step"`
+	}
+	var records []record
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("The log line is not valid JSON: %v, \n line = %s", err, scanner.Text())
+		}
+		if _, err := time.Parse(time.RFC3339Nano, r.Time); err != nil {
+			t.Errorf("The log line time is not as expected: %v", err)
+		}
+		records = append(records, r)
+	}
+
+	expectedOutput := []record{
+		{Level: "info", Msg: "[START] executing workflow: some-workflow", Workflow: "some-workflow"},
+		{Level: "info", Msg: succeed + " executing step: step \"1\"", Workflow: "some-workflow", Step: "step \"1\""},
+		{Level: "error", Msg: failed + " executing step: step 2, err: any-err", Workflow: "some-workflow", Step: "step 2"},
+		{Level: "info", Msg: "[DONE] executing workflow: some-workflow", Workflow: "some-workflow"},
+	}
+	if len(records) != len(expectedOutput) {
+		t.Fatalf("The number of log lines is not as expected: \n expected = %#v, \n actual = %#v", len(expectedOutput), len(records))
+	}
+	for i, r := range records {
+		r.Time = ""
+		if r != expectedOutput[i] {
+			t.Errorf("The log line %d is not as expected: \n expected = %#v, \n actual = %#v", i, expectedOutput[i], r)
+		}
+	}
+}
+
+func TestJSONLoggerConcurrently(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewJSONLogger(&buf)
+
+	const writers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Info("some message")
+		}()
+	}
+	wg.Wait()
+
+	var lines int
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		lines++
+		if !json.Valid(scanner.Bytes()) {
+			t.Errorf("A concurrently written log line is not valid JSON: \n line = %s", scanner.Text())
+		}
+	}
+	if lines != writers {
+		t.Errorf("The number of log lines is not as expected: \n expected = %#v, \n actual = %#v", writers, lines)
+	}
+}
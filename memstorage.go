@@ -0,0 +1,173 @@
+package workflow
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStorage is a ResultStorage keeping the steps execution results in memory, e.g. for the local development and
+// the tests. The results are lost when the process stops, and are not shared across the instances of a service, which
+// the redisstore and mongostore modules are for. It is safe for concurrent use.
+type MemoryStorage struct {
+	mu      sync.Mutex
+	records map[string]*memoryRecords // the results of every correlation ID
+	order   *list.List                // the correlation IDs, from the oldest to the newest, for the eviction
+	maxSize int
+	// normalize builds the key of a step result from the step name, which is the step name itself if nil
+	normalize func(stepName string) string
+}
+
+// memoryRecords holds the results of a correlation ID.
+type memoryRecords struct {
+	results map[string]StepResult
+	values  map[string][]byte
+	elem    *list.Element // the position of the correlation ID in the eviction order
+}
+
+// MemoryStorageOption configures a MemoryStorage.
+type MemoryStorageOption func(*MemoryStorage)
+
+// WithMemoryStorageMaxSize bounds the number of the correlation IDs whose results are stored: once the bound is
+// reached, saving the results of a new correlation ID evicts all the results of the oldest one. A zero n means no
+// bound, which is the default, the results being removed only when the workflows succeed.
+func WithMemoryStorageMaxSize(n int) MemoryStorageOption {
+	return func(s *MemoryStorage) {
+		s.maxSize = n
+	}
+}
+
+// WithMemoryStorageKeyNormalizer sets the function building the key of a step result from the step name, e.g. to
+// mimic a Storage normalizing the keys, like lower casing them. By default, the step names are used as they are.
+// The step names normalized to the same key share their result, see the Storage documentation.
+func WithMemoryStorageKeyNormalizer(fn func(stepName string) string) MemoryStorageOption {
+	return func(s *MemoryStorage) {
+		s.normalize = fn
+	}
+}
+
+// NewMemoryStorage is the MemoryStorage constructor.
+func NewMemoryStorage(opts ...MemoryStorageOption) *MemoryStorage {
+	s := MemoryStorage{
+		records: make(map[string]*memoryRecords),
+		order:   list.New(),
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return &s
+}
+
+// Save implements the Storage interface.
+func (s *MemoryStorage) Save(_ context.Context, stepName, correlationID string, status StepStatus, output *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if output != nil {
+		out := *output
+		output = &out
+	}
+	s.recordsOf(correlationID).results[s.key(stepName)] = StepResult{Status: status, Output: output, SavedAt: time.Now()}
+
+	return nil
+}
+
+// Get implements the Storage interface.
+func (s *MemoryStorage) Get(ctx context.Context, stepName, correlationID string) (StepStatus, error) {
+	res, err := s.GetResult(ctx, stepName, correlationID)
+
+	return res.Status, err
+}
+
+// GetResult implements the ResultStorage interface.
+func (s *MemoryStorage) GetResult(_ context.Context, stepName, correlationID string) (StepResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[correlationID]
+	if !ok {
+		return StepResult{}, nil
+	}
+	res := r.results[s.key(stepName)]
+	if res.Output != nil {
+		out := *res.Output
+		res.Output = &out
+	}
+
+	return res, nil
+}
+
+// Clear implements the Storage interface. It returns a nil error even if there is no stored record.
+func (s *MemoryStorage) Clear(_ context.Context, correlationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.records[correlationID]; ok {
+		s.order.Remove(r.elem)
+		delete(s.records, correlationID)
+	}
+
+	return nil
+}
+
+// SaveValue implements the Storage interface.
+func (s *MemoryStorage) SaveValue(_ context.Context, stepName, correlationID string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordsOf(correlationID).values[s.key(stepName)] = append([]byte(nil), value...)
+
+	return nil
+}
+
+// GetValue implements the Storage interface. It returns a nil value if there is no stored value.
+func (s *MemoryStorage) GetValue(_ context.Context, stepName, correlationID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[correlationID]
+	if !ok {
+		return nil, nil
+	}
+	value, ok := r.values[s.key(stepName)]
+	if !ok {
+		return nil, nil
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+// Len returns the number of the correlation IDs whose results are stored.
+func (s *MemoryStorage) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.records)
+}
+
+// recordsOf returns the results of the correlation ID, creating them if missing, and evicting the oldest correlation ID
+// if the max size is reached. It must be called with the mutex held.
+func (s *MemoryStorage) recordsOf(correlationID string) *memoryRecords {
+	if r, ok := s.records[correlationID]; ok {
+		return r
+	}
+	if s.maxSize > 0 && len(s.records) >= s.maxSize {
+		oldest := s.order.Front()
+		s.order.Remove(oldest)
+		delete(s.records, oldest.Value.(string))
+	}
+	r := memoryRecords{
+		results: make(map[string]StepResult),
+		values:  make(map[string][]byte),
+		elem:    s.order.PushBack(correlationID),
+	}
+	s.records[correlationID] = &r
+
+	return &r
+}
+
+// key returns the key of the step result.
+func (s *MemoryStorage) key(stepName string) string {
+	if s.normalize != nil {
+		return s.normalize(stepName)
+	}
+
+	return stepName
+}
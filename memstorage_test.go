@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMemoryStorageBehaviourOnMissingRecords(t *testing.T) {
+	s := NewMemoryStorage()
+	s.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+
+	tests := []struct {
+		name          string
+		stepName      string
+		correlationID string
+	}{
+		{name: "a missing correlation ID should return no record", stepName: "step 1", correlationID: "id-2"},
+		{name: "a missing step should return no record", stepName: "step 2", correlationID: "id-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := s.Get(context.TODO(), tt.stepName, tt.correlationID)
+			if status != "" || err != nil {
+				t.Errorf("The status is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", StepStatus(""), nil, status, err)
+			}
+			res, err := s.GetResult(context.TODO(), tt.stepName, tt.correlationID)
+			if !reflect.DeepEqual(res, StepResult{}) || err != nil {
+				t.Errorf("The result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", StepResult{}, nil, res, err)
+			}
+			value, err := s.GetValue(context.TODO(), tt.stepName, tt.correlationID)
+			if value != nil || err != nil {
+				t.Errorf("The value is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", nil, nil, value, err)
+			}
+		})
+	}
+}
+
+func TestMemoryStorageBehaviourOnSaveAndClear(t *testing.T) {
+	s := NewMemoryStorage()
+	output := "any-err"
+	s.Save(context.TODO(), "step 1", "id-1", StepStatusFailed, &output)
+	s.SaveValue(context.TODO(), "step 1", "id-1", []byte("value"))
+	s.Save(context.TODO(), "step 1", "id-2", StepStatusSuccess, nil)
+	// the stored output is a copy, so it's not affected by the caller.
+	output = "changed"
+
+	res, _ := s.GetResult(context.TODO(), "step 1", "id-1")
+	if res.Status != StepStatusFailed || res.Output == nil || *res.Output != "any-err" || res.SavedAt.IsZero() {
+		t.Errorf("The stored result is not as expected: \n actual = %#v", res)
+	}
+	if value, _ := s.GetValue(context.TODO(), "step 1", "id-1"); string(value) != "value" {
+		t.Errorf("The stored value is not as expected: \n expected = %#v, \n actual = %#v", "value", string(value))
+	}
+
+	s.Clear(context.TODO(), "id-1")
+
+	if status, _ := s.Get(context.TODO(), "step 1", "id-1"); status != "" {
+		t.Errorf("The cleared status is not as expected: \n expected = %#v, \n actual = %#v", StepStatus(""), status)
+	}
+	if value, _ := s.GetValue(context.TODO(), "step 1", "id-1"); value != nil {
+		t.Errorf("The cleared value is not as expected: \n expected = %#v, \n actual = %#v", nil, value)
+	}
+	// the results of the other correlation IDs are kept.
+	if status, _ := s.Get(context.TODO(), "step 1", "id-2"); status != StepStatusSuccess {
+		t.Errorf("The status is not as expected: \n expected = %#v, \n actual = %#v", StepStatusSuccess, status)
+	}
+}
+
+func TestMemoryStorageBehaviourOnRepeatedClear(t *testing.T) {
+	s := NewMemoryStorage()
+	s.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+
+	if err := s.Clear(context.TODO(), "id-1"); err != nil {
+		t.Errorf("The first clear error is not as expected: \n expected = %#v, \n actual = %#v", nil, err)
+	}
+	// the records are already removed, e.g. by a concurrent execution.
+	if err := s.Clear(context.TODO(), "id-1"); err != nil {
+		t.Errorf("The second clear error is not as expected: \n expected = %#v, \n actual = %#v", nil, err)
+	}
+	if err := s.Clear(context.TODO(), "id-2"); err != nil {
+		t.Errorf("The clear error of a missing correlation ID is not as expected: \n expected = %#v, \n actual = %#v", nil, err)
+	}
+}
+
+func TestMemoryStorageBehaviourOnMaxSize(t *testing.T) {
+	s := NewMemoryStorage(WithMemoryStorageMaxSize(2))
+	s.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+	s.Save(context.TODO(), "step 1", "id-2", StepStatusSuccess, nil)
+	// saving the results of a known correlation ID doesn't evict any.
+	s.SaveValue(context.TODO(), "step 1", "id-1", []byte("value"))
+	s.Save(context.TODO(), "step 1", "id-3", StepStatusSuccess, nil)
+
+	var actualOutput []string
+	for _, id := range []string{"id-1", "id-2", "id-3"} {
+		if status, _ := s.Get(context.TODO(), "step 1", id); status != "" {
+			actualOutput = append(actualOutput, id)
+		}
+	}
+
+	expectedOutput := []string{"id-2", "id-3"}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) || s.Len() != 2 {
+		t.Errorf("The stored correlation IDs are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestMemoryStorageBehaviourOnKeyNormalizer(t *testing.T) {
+	s := NewMemoryStorage(WithMemoryStorageKeyNormalizer(strings.ToLower))
+	s.Save(context.TODO(), "Send Email", "id-1", StepStatusSuccess, nil)
+
+	if status, _ := s.Get(context.TODO(), "send email", "id-1"); status != StepStatusSuccess {
+		t.Errorf("The status of the normalized key is not as expected: \n expected = %#v, \n actual = %#v", StepStatusSuccess, status)
+	}
+	if status, _ := NewMemoryStorage().Get(context.TODO(), "send email", "id-1"); status != "" {
+		t.Errorf("The status of a distinct step name is not as expected: \n expected = %#v, \n actual = %#v", StepStatus(""), status)
+	}
+}
+
+func TestMemoryStorageConcurrently(t *testing.T) {
+	s := NewMemoryStorage(WithMemoryStorageMaxSize(10))
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			s.Save(context.TODO(), "step 1", id, StepStatusSuccess, nil)
+			s.SaveValue(context.TODO(), "step 1", id, []byte(id))
+			s.GetValue(context.TODO(), "step 1", id)
+			s.Clear(context.TODO(), id)
+		}("id-" + strconv.Itoa(i))
+	}
+	wg.Wait()
+
+	if s.Len() != 0 {
+		t.Errorf("The number of the stored correlation IDs is not as expected: \n expected = %#v, \n actual = %#v", 0, s.Len())
+	}
+}
+
+func TestSequentialExecuteBehaviourOnReplayWithMemoryStorage(t *testing.T) {
+	anyErr := errors.New("any-err")
+	s := NewMemoryStorage()
+	step1 := newStepSuccessful("step 1")
+	step2 := newStepFailedNonRetryable("step 2", anyErr)
+	wf := NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: step1}, {Step: step2}}, WithStorage(s), WithCorrelationID("id-1"))
+
+	wf.Execute(context.TODO(), nil)
+	step2.execute = nil
+	err := wf.Execute(context.TODO(), nil)
+
+	if err != nil || step1.invocationCount != 1 || step2.invocationCount != 2 {
+		t.Errorf("The replay is not as expected: \n actual = %#v, %#v, %#v", err, step1.invocationCount, step2.invocationCount)
+	}
+	if s.Len() != 0 {
+		t.Errorf("The results of the succeeded workflow were not cleared: \n actual = %#v", s.Len())
+	}
+}
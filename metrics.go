@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics records the steps executions, e.g. as Prometheus counters and histograms.
+// Every method receives the ctx of the execution, which carries its ID, read by ExecutionIDFromContext, if set by
+// WithExecutionID or WithExecutionIDFunc, or their Pipe versions, so the recordings of a run can be correlated, e.g. as
+// exemplars.
+type Metrics interface {
+	// StepStarted is called once per step, before its first attempt.
+	StepStarted(ctx context.Context, stepName string)
+	// StepRetried is called before every retry attempt of the step, the attempt starting from 1, once the retry budgets
+	// allow it, so a refused retry is not recorded.
+	StepRetried(ctx context.Context, stepName string, attempt uint)
+	// StepFinished is called once per step, after its last attempt, with the final status and the total duration,
+	// retries included.
+	StepFinished(ctx context.Context, stepName string, status StepStatus, duration time.Duration)
+}
+
+// WithMetrics sets the Metrics recording the steps executions of the Sequential workflow.
+// If not provided, or if nil is provided, nothing is recorded.
+func WithMetrics(m Metrics) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.metrics.m = m
+	}
+}
+
+// WithPipeMetrics sets the Metrics recording the steps executions of the Pipe workflow.
+// If not provided, or if nil is provided, nothing is recorded.
+func WithPipeMetrics(m Metrics) PipeOption {
+	return func(o *pipeOptions) {
+		o.metrics.m = m
+	}
+}
+
+// stepMetrics wraps the Metrics provided by the user, and is a no op if no Metrics is provided.
+// It also spares the time measurement, when there is nothing to record.
+type stepMetrics struct {
+	m Metrics
+}
+
+// started records the step start, and returns the start time.
+func (sm stepMetrics) started(ctx context.Context, stepName string) time.Time {
+	if sm.m == nil {
+		return time.Time{}
+	}
+	sm.m.StepStarted(ctx, stepName)
+
+	return time.Now()
+}
+
+// retried records the step retry attempt.
+func (sm stepMetrics) retried(ctx context.Context, stepName string, attempt uint) {
+	if sm.m != nil {
+		sm.m.StepRetried(ctx, stepName, attempt)
+	}
+}
+
+// finished records the step outcome, the step being started at start.
+func (sm stepMetrics) finished(ctx context.Context, stepName string, start time.Time, err error) {
+	if sm.m == nil {
+		return
+	}
+	status := StepStatusSuccess
+	if err != nil {
+		status = StepStatusFailed
+	}
+	sm.m.StepFinished(ctx, stepName, status, time.Since(start))
+}
@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestExecuteBehaviourOnMetrics(t *testing.T) {
+	anyErr := errors.New("any-err")
+	expectedOutput := []string{
+		"started: step 1",
+		"finished: step 1 SUCCESS",
+		"started: step 2",
+		"retried: step 2 1",
+		"retried: step 2 2",
+		"finished: step 2 FAILED",
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		m := &metricsMock{}
+		input := []SequentialStepConfig[any]{
+			{Step: newStepSuccessful("step 1")},
+			{Step: newStepFailedRetryable("step 2", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+		}
+		NewSequential("some-workflow", input, WithMetrics(m)).Execute(context.TODO(), nil)
+
+		if !reflect.DeepEqual(m.records, expectedOutput) {
+			t.Errorf("The recorded metrics are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, m.records)
+		}
+	})
+	t.Run("pipe", func(t *testing.T) {
+		m := &metricsMock{}
+		input := []PipeStepConfig[any]{
+			{Step: newPipeStepSuccessful[any]("step 1")},
+			{Step: newPipeStepFailedRetryable[any]("step 2", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+		}
+		NewPipe("some-workflow", input, WithPipeMetrics(m)).Execute(context.TODO(), nil)
+
+		if !reflect.DeepEqual(m.records, expectedOutput) {
+			t.Errorf("The recorded metrics are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, m.records)
+		}
+	})
+}
+
+func TestExecuteBehaviourOnMetricsOfRefusedRetry(t *testing.T) {
+	anyErr := errors.New("any-err")
+	retryConfig := func() (uint, time.Duration) { return 3, time.Second }
+	// the retry budget refuses the retries, so no retry is recorded.
+	expectedOutput := []string{"started: step 1", "finished: step 1 FAILED"}
+
+	t.Run("sequential", func(t *testing.T) {
+		m := &metricsMock{}
+		input := []SequentialStepConfig[any]{
+			{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: retryConfig, RetryBudget: time.Millisecond},
+		}
+		NewSequential("some-workflow", input, WithMetrics(m)).Execute(context.TODO(), nil)
+
+		if !reflect.DeepEqual(m.records, expectedOutput) {
+			t.Errorf("The recorded metrics are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, m.records)
+		}
+	})
+	t.Run("sequential global retry budget", func(t *testing.T) {
+		m := &metricsMock{}
+		input := []SequentialStepConfig[any]{
+			{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: func() (uint, time.Duration) { return 3, 0 }},
+		}
+		NewSequential("some-workflow", input, WithMetrics(m), WithGlobalRetryBudget(1)).Execute(context.TODO(), nil)
+
+		expectedOutput := []string{"started: step 1", "retried: step 1 1", "finished: step 1 FAILED"}
+		if !reflect.DeepEqual(m.records, expectedOutput) {
+			t.Errorf("The recorded metrics are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, m.records)
+		}
+	})
+	t.Run("pipe", func(t *testing.T) {
+		m := &metricsMock{}
+		input := []PipeStepConfig[any]{
+			{Step: newPipeStepFailedRetryable[any]("step 1", anyErr), RetryConfigProvider: retryConfig, RetryBudget: time.Millisecond},
+		}
+		NewPipe("some-workflow", input, WithPipeMetrics(m)).Execute(context.TODO(), nil)
+
+		if !reflect.DeepEqual(m.records, expectedOutput) {
+			t.Errorf("The recorded metrics are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, m.records)
+		}
+	})
+}
+
+func TestExecuteBehaviourOnMetricsExecutionID(t *testing.T) {
+	anyErr := errors.New("any-err")
+
+	t.Run("sequential", func(t *testing.T) {
+		m := &metricsMock{}
+		input := []SequentialStepConfig[any]{
+			{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: func() (uint, time.Duration) { return 1, 0 }},
+		}
+		NewSequential("some-workflow", input, WithMetrics(m), WithExecutionID("exec-1")).Execute(context.TODO(), nil)
+
+		// the start, the retry and the finish of the step.
+		expectedOutput := []string{"exec-1", "exec-1", "exec-1"}
+		if !reflect.DeepEqual(m.executionIDs, expectedOutput) {
+			t.Errorf("The execution IDs passed to the metrics are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, m.executionIDs)
+		}
+	})
+	t.Run("pipe", func(t *testing.T) {
+		m := &metricsMock{}
+		input := []PipeStepConfig[any]{
+			{Step: newPipeStepSuccessful[any]("step 1")},
+			{Step: newPipeStepSuccessful[any]("step 2")},
+		}
+		NewPipe("some-workflow", input, WithPipeMetrics(m), WithPipeExecutionID("exec-1")).Execute(context.TODO(), nil)
+
+		// the start and the finish of both steps.
+		expectedOutput := []string{"exec-1", "exec-1", "exec-1", "exec-1"}
+		if !reflect.DeepEqual(m.executionIDs, expectedOutput) {
+			t.Errorf("The execution IDs passed to the metrics are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, m.executionIDs)
+		}
+	})
+}
+
+// MOCKS/STUBS
+type metricsMock struct {
+	records      []string
+	executionIDs []string // the IDs of the executions recorded, read from their ctx
+}
+
+func (m *metricsMock) StepStarted(ctx context.Context, stepName string) {
+	m.records = append(m.records, "started: "+stepName)
+	m.recordExecutionID(ctx)
+}
+
+func (m *metricsMock) StepRetried(ctx context.Context, stepName string, attempt uint) {
+	m.records = append(m.records, "retried: "+stepName+" "+strconv.FormatUint(uint64(attempt), 10))
+	m.recordExecutionID(ctx)
+}
+
+func (m *metricsMock) StepFinished(ctx context.Context, stepName string, status StepStatus, _ time.Duration) {
+	m.records = append(m.records, "finished: "+stepName+" "+string(status))
+	m.recordExecutionID(ctx)
+}
+
+func (m *metricsMock) recordExecutionID(ctx context.Context) {
+	if id, ok := ExecutionIDFromContext(ctx); ok {
+		m.executionIDs = append(m.executionIDs, id)
+	}
+}
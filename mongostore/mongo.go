@@ -0,0 +1,124 @@
+// Package mongostore provides a workflow.Storage backed by MongoDB, keeping the workflows replay state along with the
+// rest of the operational state, and so in the same backups.
+// It lives in its own module, so the workflow package doesn't depend on the MongoDB driver.
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/silviutanasa/workflow"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// record is the document holding the execution result of a step, for a correlation ID.
+type record struct {
+	CorrelationID string              `bson:"correlation_id"`
+	StepName      string              `bson:"step_name"`
+	Status        workflow.StepStatus `bson:"status"`
+	Output        *string             `bson:"output"`
+	SavedAt       time.Time           `bson:"saved_at"`
+	Value         []byte              `bson:"value,omitempty"`
+}
+
+// Storage is a workflow.ResultStorage keeping the steps execution results in a MongoDB collection, one document per
+// correlation ID and step name.
+type Storage struct {
+	coll *mongo.Collection
+}
+
+// New is the Storage constructor.
+// The collection should be indexed by EnsureIndexes, before the Storage is used.
+func New(coll *mongo.Collection) *Storage {
+	return &Storage{coll: coll}
+}
+
+// EnsureIndexes creates, if missing, the unique compound index on the correlation ID and the step name, used by every
+// Storage operation.
+func (s *Storage) EnsureIndexes(ctx context.Context) error {
+	_, err := s.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "correlation_id", Value: 1}, {Key: "step_name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
+
+// Save implements the workflow.Storage interface.
+func (s *Storage) Save(ctx context.Context, stepName, correlationID string, status workflow.StepStatus, output *string) error {
+	return s.upsert(ctx, stepName, correlationID, bson.D{
+		{Key: "status", Value: status},
+		{Key: "output", Value: output},
+		{Key: "saved_at", Value: time.Now()},
+	})
+}
+
+// Get implements the workflow.Storage interface.
+// It returns an empty workflow.StepStatus and a nil error, if there is no stored record.
+func (s *Storage) Get(ctx context.Context, stepName, correlationID string) (workflow.StepStatus, error) {
+	res, err := s.GetResult(ctx, stepName, correlationID)
+
+	return res.Status, err
+}
+
+// GetResult implements the workflow.ResultStorage interface.
+// It returns a workflow.StepResult with an empty workflow.StepStatus and a nil error, if there is no stored record.
+func (s *Storage) GetResult(ctx context.Context, stepName, correlationID string) (workflow.StepResult, error) {
+	r, err := s.find(ctx, stepName, correlationID)
+	if err != nil {
+		return workflow.StepResult{}, err
+	}
+
+	return workflow.StepResult{Status: r.Status, Output: r.Output, SavedAt: r.SavedAt}, nil
+}
+
+// Clear implements the workflow.Storage interface. Deleting no document is not an error, so it's idempotent.
+func (s *Storage) Clear(ctx context.Context, correlationID string) error {
+	_, err := s.coll.DeleteMany(ctx, bson.D{{Key: "correlation_id", Value: correlationID}})
+
+	return err
+}
+
+// SaveValue implements the workflow.Storage interface.
+func (s *Storage) SaveValue(ctx context.Context, stepName, correlationID string, value []byte) error {
+	return s.upsert(ctx, stepName, correlationID, bson.D{{Key: "value", Value: value}})
+}
+
+// GetValue implements the workflow.Storage interface.
+// It returns a nil value and a nil error, if there is no stored value.
+func (s *Storage) GetValue(ctx context.Context, stepName, correlationID string) ([]byte, error) {
+	r, err := s.find(ctx, stepName, correlationID)
+
+	return r.Value, err
+}
+
+// upsert sets the fields of the document of the step, for the correlation ID, creating it if missing.
+func (s *Storage) upsert(ctx context.Context, stepName, correlationID string, fields bson.D) error {
+	_, err := s.coll.UpdateOne(
+		ctx,
+		filter(stepName, correlationID),
+		bson.D{{Key: "$set", Value: fields}},
+		options.UpdateOne().SetUpsert(true),
+	)
+
+	return err
+}
+
+// find returns the document of the step, for the correlation ID, or an empty record if there is none.
+func (s *Storage) find(ctx context.Context, stepName, correlationID string) (record, error) {
+	var r record
+	err := s.coll.FindOne(ctx, filter(stepName, correlationID)).Decode(&r)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return record{}, nil
+	}
+
+	return r, err
+}
+
+// filter selects the document of the step, for the correlation ID.
+func filter(stepName, correlationID string) bson.D {
+	return bson.D{{Key: "correlation_id", Value: correlationID}, {Key: "step_name", Value: stepName}}
+}
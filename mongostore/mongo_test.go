@@ -0,0 +1,128 @@
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/silviutanasa/workflow"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// The tests run against the MongoDB found at the MONGODB_URI, e.g. a test container started by:
+//
+//	docker run --rm -d -p 27017:27017 mongo
+//	MONGODB_URI=mongodb://localhost:27017 go test ./...
+//
+// They are skipped if the MONGODB_URI is not set.
+
+func TestStorage(t *testing.T) {
+	ctx := context.TODO()
+	s := newTestStorage(t)
+	failure := "any-err"
+
+	if status, err := s.Get(ctx, "step 1", "id-1"); status != "" || err != nil {
+		t.Errorf("The missing record is not reported as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "", nil, status, err)
+	}
+	if err := s.Save(ctx, "step 1", "id-1", workflow.StepStatusFailed, &failure); err != nil {
+		t.Fatalf("The save returned an unexpected error: %v", err)
+	}
+	res, err := s.GetResult(ctx, "step 1", "id-1")
+	if err != nil || res.Status != workflow.StepStatusFailed || res.Output == nil || *res.Output != failure || res.SavedAt.IsZero() {
+		t.Errorf("The stored result is not as expected: \n actual = %#v, %#v", res, err)
+	}
+	// a success overwrites the failure, and drops its output.
+	if err = s.Save(ctx, "step 1", "id-1", workflow.StepStatusSuccess, nil); err != nil {
+		t.Fatalf("The save returned an unexpected error: %v", err)
+	}
+	if res, _ = s.GetResult(ctx, "step 1", "id-1"); res.Status != workflow.StepStatusSuccess || res.Output != nil {
+		t.Errorf("The stored result is not as expected: \n actual = %#v", res)
+	}
+	if err = s.SaveValue(ctx, "step 1", "id-1", []byte(`"x"`)); err != nil {
+		t.Fatalf("The value save returned an unexpected error: %v", err)
+	}
+	if value, _ := s.GetValue(ctx, "step 1", "id-1"); string(value) != `"x"` {
+		t.Errorf("The stored value is not as expected: \n expected = %#v, \n actual = %#v", `"x"`, string(value))
+	}
+	if err = s.Save(ctx, "step 1", "id-2", workflow.StepStatusSuccess, nil); err != nil {
+		t.Fatalf("The save returned an unexpected error: %v", err)
+	}
+
+	if err = s.Clear(ctx, "id-1"); err != nil {
+		t.Fatalf("The clear returned an unexpected error: %v", err)
+	}
+	if status, _ := s.Get(ctx, "step 1", "id-1"); status != "" {
+		t.Errorf("The record was not removed by the clear: \n actual = %#v", status)
+	}
+	if status, _ := s.Get(ctx, "step 1", "id-2"); status != workflow.StepStatusSuccess {
+		t.Errorf("The record of another correlation ID was removed by the clear: \n actual = %#v", status)
+	}
+	if err = s.Clear(ctx, "id-1"); err != nil {
+		t.Errorf("The clear of removed records returned an unexpected error: %v", err)
+	}
+}
+
+func TestStorageWithSequential(t *testing.T) {
+	anyErr := errors.New("any-err")
+	s := newTestStorage(t)
+	var step2Err = anyErr
+	var step1Count int
+	input := []workflow.SequentialStepConfig[any]{
+		{Step: stepFunc{name: "step 1", fn: func() error { step1Count++; return nil }}},
+		{Step: stepFunc{name: "step 2", fn: func() error { return step2Err }}},
+	}
+	wf := workflow.NewSequential("some-workflow", input, workflow.WithStorage(s), workflow.WithCorrelationID("id-1"))
+
+	if err := wf.Execute(context.TODO(), nil); !errors.Is(err, anyErr) {
+		t.Fatalf("The first execution error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+	step2Err = nil
+	if err := wf.Execute(context.TODO(), nil); err != nil {
+		t.Fatalf("The replay returned an unexpected error: %v", err)
+	}
+
+	if step1Count != 1 {
+		t.Errorf("The succeeded step was not skipped on replay: \n expected invocations = %#v, \n actual = %#v", 1, step1Count)
+	}
+}
+
+// newTestStorage returns a Storage using a new collection, dropped at the end of the test.
+func newTestStorage(t *testing.T) *Storage {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI is not set")
+	}
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("The MongoDB connection failed: %v", err)
+	}
+	coll := client.Database("workflow_test").Collection("results_" + strconv.FormatInt(time.Now().UnixNano(), 10))
+	t.Cleanup(func() {
+		coll.Drop(context.TODO())
+		client.Disconnect(context.TODO())
+	})
+	s := New(coll)
+	if err = s.EnsureIndexes(context.TODO()); err != nil {
+		t.Fatalf("The indexes creation failed: %v", err)
+	}
+
+	return s
+}
+
+// MOCKS/STUBS
+type stepFunc struct {
+	name string
+	fn   func() error
+}
+
+func (s stepFunc) Name() string {
+	return s.name
+}
+
+func (s stepFunc) Execute(_ context.Context, _ any) error {
+	return s.fn()
+}
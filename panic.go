@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error a recovered step panic is converted to, see WithPanicRecovery.
+type PanicError struct {
+	Value any    // the value passed to panic
+	Stack []byte // the stack trace of the panicking goroutine
+}
+
+// Error implements the error interface.
+func (e PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value passed to panic, if it's an error, so it can be checked using errors.Is or errors.As.
+func (e PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+// isPanic reports whether the err is a recovered panic.
+func isPanic(err error) bool {
+	_, ok := err.(PanicError)
+
+	return ok
+}
+
+// WithPanicRecovery makes the workflow recover the steps panics, and convert them to a PanicError, returned as the step
+// error, so a panicking step fails like any other failing step, and honors its SequentialStepConfig.ContinueWorkflowOnError.
+// A recovered panic is not retried, as it signals a bug rather than a transient failure.
+// If not provided, the panics are not recovered.
+func WithPanicRecovery() SequentialOption {
+	return func(o *sequentialOptions) {
+		o.recoverPanics = true
+	}
+}
+
+// WithPanicHandler registers a handler called with the value of every recovered step panic, before it's converted to a
+// PanicError, e.g. to report the panic to a crash reporter. It enables the panic recovery, as WithPanicRecovery does.
+func WithPanicHandler(fn func(ctx context.Context, stepName string, recovered any)) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.recoverPanics = true
+		o.onPanic = fn
+	}
+}
+
+// recovered handles the r panic of the attempt of the step, and returns it as a PanicError.
+func (s *Sequential[T]) recovered(ctx context.Context, stepCfg SequentialStepConfig[T], attempt uint, r any) error {
+	stepName := stepCfg.name()
+	err := PanicError{Value: r, Stack: debug.Stack()}
+	s.log.override(stepCfg.Logger).printStep(ctx, LevelError, concatStr("recovered panic of step: ", stepName, ", ", err.Error()), stepName, attempt)
+	if s.onPanic != nil {
+		s.onPanic(ctx, stepName, r)
+	}
+
+	return err
+}
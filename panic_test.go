@@ -0,0 +1,89 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnPanic(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name           string
+		input          any // the value passed to panic
+		expectedOutput string
+	}{
+		{
+			name:           "a panic with a value should be returned as a PanicError",
+			input:          "boom",
+			expectedOutput: "panic: boom",
+		},
+		{
+			name:           "a panic with an error should be returned as a PanicError wrapping the error",
+			input:          anyErr,
+			expectedOutput: "panic: any-err",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := []SequentialStepConfig[any]{
+				{Step: stepFuncMock(func(ctx context.Context, request any) error { panic(tt.input) })},
+			}
+
+			err := NewSequential("some-workflow", input, WithPanicRecovery()).Execute(context.TODO(), nil)
+
+			var panicErr PanicError
+			if !errors.As(err, &panicErr) || panicErr.Value != tt.input || len(panicErr.Stack) == 0 {
+				t.Fatalf("The workflow error doesn't hold the recovered panic: \n expected = %#v, \n actual = %#v", tt.input, err)
+			}
+			if panicErr.Error() != tt.expectedOutput {
+				t.Errorf("The panic error message is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, panicErr.Error())
+			}
+			if inputErr, ok := tt.input.(error); ok && !errors.Is(err, inputErr) {
+				t.Errorf("The workflow error doesn't wrap the panic error: \n expected = %#v, \n actual = %#v", inputErr, err)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnPanicHandler(t *testing.T) {
+	var stepNames []string
+	var recovered []any
+	handler := func(ctx context.Context, stepName string, r any) {
+		stepNames = append(stepNames, stepName)
+		recovered = append(recovered, r)
+	}
+	// the panicking step is retryable, but a recovered panic is not retried.
+	panicking := retryableStepFuncMock(func(ctx context.Context, request any) error { panic("boom") })
+	next := newStepSuccessful("step 2")
+	input := []SequentialStepConfig[any]{
+		{Step: panicking, ContinueWorkflowOnError: true, RetryConfigProvider: defaultRetryConfigProviderTest},
+		{Step: next},
+	}
+
+	err := NewSequential("some-workflow", input, WithPanicHandler(handler)).Execute(context.TODO(), nil)
+
+	if len(recovered) != 1 || recovered[0] != "boom" || stepNames[0] != "retryable-step-func" {
+		t.Errorf("The panic handler calls are not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "retryable-step-func", "boom", stepNames, recovered)
+	}
+	var stepErr StepError
+	if !errors.As(err, &stepErr) || stepErr.Attempts != 1 {
+		t.Errorf("The panicking step error is not as expected: \n expected attempts = %#v, \n actual = %#v", 1, err)
+	}
+	if next.invocationCount != 1 {
+		t.Errorf("The workflow did not continue after the panic: \n invocation count = %#v", next.invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnPanicWithoutRecovery(t *testing.T) {
+	input := []SequentialStepConfig[any]{
+		{Step: stepFuncMock(func(ctx context.Context, request any) error { panic("boom") })},
+	}
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("The panic was not propagated: \n expected = %#v, \n actual = %#v", "boom", r)
+		}
+	}()
+
+	NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+}
@@ -0,0 +1,271 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ParallelStepConfig provides configuration for a step of a Parallel workflow.
+// The fields have the same meaning as the SequentialStepConfig ones.
+type ParallelStepConfig[T any] struct {
+	Step SequentialStep[T]
+	// define this only if the Step implements RetryDecider or ErrorRetryDecider, or if RetryIf is set, otherwise it has no
+	// effect and no sense!
+	RetryConfigProvider func() (maxAttempts uint, attemptDelay time.Duration) // provides the retry configuration
+	RetryIf             func(ctx context.Context, err error, attempt uint) bool
+	Backoff             BackoffStrategy
+	RetryDelay          func(attempt uint, lastErr error) time.Duration
+	RetryBudget         time.Duration
+	Timeout             time.Duration
+	NameOverride        string
+	CircuitBreaker      *CircuitBreaker
+	// Priority orders the start of the steps, when WithMaxConcurrency limits the number of the steps running at the same
+	// time: the steps with a higher Priority start first, and the steps with the same Priority start in the config order.
+	Priority int
+}
+
+// sequential returns the SequentialStepConfig running the step, so the Parallel steps are run by a Sequential runner.
+func (c ParallelStepConfig[T]) sequential() SequentialStepConfig[T] {
+	return SequentialStepConfig[T]{
+		Step:                c.Step,
+		RetryConfigProvider: c.RetryConfigProvider,
+		RetryIf:             c.RetryIf,
+		Backoff:             c.Backoff,
+		RetryDelay:          c.RetryDelay,
+		RetryBudget:         c.RetryBudget,
+		Timeout:             c.Timeout,
+		NameOverride:        c.NameOverride,
+		CircuitBreaker:      c.CircuitBreaker,
+	}
+}
+
+// AbortedError holds the errors returned by the steps aborted by the fail fast cancellation of a Parallel workflow, see
+// WithFailFast, so they can be told apart from the errors of the steps which failed on their own, by using errors.As
+// against the error returned by the workflow.
+type AbortedError struct {
+	Errs []error
+}
+
+// Error implements the error interface.
+func (e *AbortedError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return "aborted: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the aborted steps, so they can be checked using errors.Is or errors.As.
+func (e *AbortedError) Unwrap() []error {
+	return e.Errs
+}
+
+// WithFailFast makes a Parallel workflow cancel the ctx passed to its running steps as soon as a step fails, instead of
+// waiting for all of them to complete. The steps stop only if they honor their ctx. It has no effect on the other
+// workflows.
+func WithFailFast() SequentialOption {
+	return func(o *sequentialOptions) {
+		o.failFast = true
+	}
+}
+
+// WithMaxConcurrency bounds the number of the steps of a Parallel workflow running at the same time, the waiting steps
+// starting, by their ParallelStepConfig.Priority, as the running ones complete. A zero n means no limit, which is the
+// default. It has no effect on the other workflows.
+func WithMaxConcurrency(n uint) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.maxConcurrency = n
+	}
+}
+
+// Parallel is a workflow that runs all its steps at the same time, passing the same req to every step.
+// The req is shared by the steps, so the steps mutating it must synchronize the access to it, unless every step gets
+// its own copy, see SetRequestCloner.
+type Parallel[T any] struct {
+	name string
+	// runner executes the steps, so the Parallel steps are retried, stored, measured and logged as the Sequential ones.
+	runner       *Sequential[T]
+	order        []int         // the indexes of the steps, in their start order
+	cloneRequest func(req T) T // gives every step its own copy of the req, if not nil
+}
+
+// the workflow implements the SequentialStep interface, so it can be a step of another workflow.
+var _ SequentialStep[any] = (*Parallel[any])(nil)
+
+// NewParallel is the workflow constructor.
+// The options are the Sequential ones, WithCompensation excepted, which has no effect. The hooks registered by
+// WithBeforeStep and WithAfterStep, and the Logger, may be called concurrently.
+// It panics if the clone provided by WithRequestCloner is not a func(T) T.
+func NewParallel[T any](name string, stepsCfg []ParallelStepConfig[T], opts ...SequentialOption) *Parallel[T] {
+	seqStepsCfg := make([]SequentialStepConfig[T], 0, len(stepsCfg))
+	order := make([]int, 0, len(stepsCfg))
+	for i, stepCfg := range stepsCfg {
+		seqStepsCfg = append(seqStepsCfg, stepCfg.sequential())
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return stepsCfg[order[i]].Priority > stepsCfg[order[j]].Priority
+	})
+
+	return &Parallel[T]{name: name, runner: NewSequential(name, seqStepsCfg, opts...), order: order}
+}
+
+// SetRequestCloner makes the workflow pass to every step its own copy of the req, returned by the clone, so the steps
+// mutating the req don't race. The clone is called before every step start, from the goroutine executing the workflow,
+// and the req itself is passed to no step. A nil clone makes the steps share the req, which is the default, the caller
+// being responsible for the steps mutating it synchronizing the access to it.
+// It must be called before the executions, as it's not safe to call concurrently with Execute.
+func (p *Parallel[T]) SetRequestCloner(clone func(req T) T) {
+	p.cloneRequest = clone
+}
+
+// Name returns the name of the workflow.
+func (p *Parallel[T]) Name() string {
+	return p.name
+}
+
+// Execute runs all the ParallelStepConfig.Step at the same time, passing them the ctx and the req, and waits for all of
+// them to complete. If WithMaxConcurrency limits the number of the running steps, the steps start by their
+// ParallelStepConfig.Priority, as the running ones complete.
+// The errors returned by the failing steps are wrapped in a single error, so any error from any failing
+// ParallelStepConfig.Step can be checked using errors.Is or errors.As against the returned error. Every step error is
+// wrapped in a StepError, identifying the failing step. The steps errors are joined in the steps declaration order,
+// whatever the order the steps finished in, so the failures summaries are stable.
+// By default, a failing step doesn't stop the other steps, so every step error is collected. With WithFailFast, the first
+// failure cancels the ctx passed to the running steps, and the waiting steps are not started, the errors of the steps
+// aborted by the cancellation being collected separately, in an AbortedError.
+// The ctx is checked before starting every step, and a done ctx stops the workflow, the ctx error being returned along
+// with the steps errors, once the running steps are done.
+// A nil ParallelStepConfig.Step fails, with an error identifying its index.
+// If a storage and a correlation ID are configured, the steps that already succeeded for the correlation ID are skipped,
+// and the stored results are cleared once the workflow succeeds.
+// The Cacheable steps which already succeeded in the execution, for their cache key, are skipped.
+func (p *Parallel[T]) Execute(ctx context.Context, req T) error {
+	ctx = p.runner.execID.context(ctx)
+
+	return p.runner.end.end(ctx, wrapWorkflowErr(p.runner.wrapErrs, p.name, p.execute(ctx, req)))
+}
+
+// execute runs the workflow, as described by Execute, without wrapping the returned error.
+func (p *Parallel[T]) execute(ctx context.Context, req T) error {
+	r := p.runner
+	r.log.print(ctx, LevelInfo, concatStr("[START] executing workflow: ", p.name))
+	defer r.log.printDone(ctx)
+
+	stepsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	e := parallelExecution[T]{
+		parallel: p,
+		req:      req,
+		stepsCtx: stepsCtx,
+		cancel:   cancel,
+		results:  make(chan indexedErr, len(r.stepsConfig)),
+		exec:     execution{retries: r.newGlobalRetries(), cache: new(succeededSteps)},
+	}
+	for _, i := range p.order {
+		if r.maxConcurrency > 0 && e.running == int(r.maxConcurrency) {
+			e.done(ctx, <-e.results)
+		}
+		e.start(ctx, i)
+	}
+	for e.running > 0 {
+		e.done(ctx, <-e.results)
+	}
+
+	if err := e.err(ctx); err != nil {
+		return err
+	}
+
+	return r.store.handleErr(ctx, r.log, r.store.clearDB(ctx))
+}
+
+// parallelExecution holds the state of a single Parallel execution.
+// It is accessed only by the goroutine running Parallel.Execute, the steps goroutines sending their results on the
+// results channel.
+type parallelExecution[T any] struct {
+	parallel  *Parallel[T]
+	req       T
+	stepsCtx  context.Context // the ctx passed to the steps, cancelled by the fail fast
+	cancel    context.CancelFunc
+	results   chan indexedErr
+	exec      execution // the state shared by the steps
+	running   int
+	cancelled bool // the running steps were cancelled by a failing step
+	stopped   bool // a step was not started because the ctx is done
+	errs      orderedErrs
+	aborted   orderedErrs
+}
+
+// start runs the step in a new goroutine, unless the ctx is done, or the steps were cancelled by a failing step, in
+// which case the step is aborted.
+func (e *parallelExecution[T]) start(ctx context.Context, i int) {
+	r := e.parallel.runner
+	stepCfg := r.stepsConfig[i]
+	if ctx.Err() != nil {
+		if !e.stopped {
+			r.log.print(ctx, LevelError, concatStr(failed, " stopping workflow: ", e.parallel.name, ", err: ", ctx.Err().Error()))
+		}
+		e.stopped = true
+
+		return
+	}
+	if stepCfg.Step == nil {
+		// the nil step fails at once, like a failing step, so the fail fast applies to it.
+		e.running++
+		e.done(ctx, indexedErr{index: i, err: nilStepErr(i)})
+
+		return
+	}
+	if e.cancelled {
+		e.aborted.add(i, StepError{StepName: stepCfg.name(), Err: e.stepsCtx.Err()})
+
+		return
+	}
+	e.running++
+	req := e.req
+	if e.parallel.cloneRequest != nil {
+		req = e.parallel.cloneRequest(req)
+	}
+	go func() {
+		_, err := r.processStep(e.stepsCtx, stepCfg, req, nil, &e.exec)
+		e.results <- indexedErr{index: i, err: err}
+	}()
+}
+
+// done handles the result of a step that ran. If the fail fast is enabled, the first failure cancels the running steps.
+func (e *parallelExecution[T]) done(ctx context.Context, r indexedErr) {
+	e.running--
+	switch {
+	case r.err == nil:
+	case e.cancelled && ctx.Err() == nil && errors.Is(r.err, context.Canceled):
+		e.aborted.add(r.index, r.err)
+	default:
+		e.errs.add(r.index, r.err)
+		if e.parallel.runner.failFast && !e.cancelled {
+			e.parallel.runner.log.print(ctx, LevelError, concatStr(failed, " cancelling the running steps of workflow: ", e.parallel.name))
+			e.cancelled = true
+			e.cancel()
+		}
+	}
+}
+
+// err returns the errors of the execution, in the steps declaration order, wrapped in a single error, or nil if all the
+// steps succeeded.
+func (e *parallelExecution[T]) err(ctx context.Context) error {
+	errs := e.errs.errs()
+	if e.stopped {
+		errs = append(errs, ctx.Err())
+	}
+	if len(e.aborted) > 0 {
+		errs = append(errs, &AbortedError{Errs: e.aborted.errs()})
+	}
+	if len(errs) > 0 {
+		return joinErrs(errs)
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallelExecuteBehaviourOnStepFailure(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var mu sync.Mutex
+	var executed []string
+	newStep := func(name string, err error) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			mu.Lock()
+			executed = append(executed, name)
+			mu.Unlock()
+
+			return err
+		}}
+	}
+	input := []ParallelStepConfig[any]{
+		{Step: newStep("a", nil)},
+		{Step: newStep("b", anyErr)},
+		{Step: newStep("c", anyErr)},
+		{Step: newStep("d", nil)},
+	}
+
+	err := NewParallel("some-workflow", input).Execute(context.TODO(), nil)
+
+	// by default, all the steps run to completion, and every error is collected.
+	failedSteps := FailedSteps(err)
+	sort.Strings(failedSteps)
+	if !reflect.DeepEqual(failedSteps, []string{"b", "c"}) {
+		t.Errorf("The failed steps are not as expected: \n expected = %#v, \n actual = %#v", []string{"b", "c"}, failedSteps)
+	}
+	sort.Strings(executed)
+	expectedOutput := []string{"a", "b", "c", "d"}
+	if !reflect.DeepEqual(executed, expectedOutput) {
+		t.Errorf("The executed steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, executed)
+	}
+}
+
+func TestParallelExecuteBehaviourOnFailFast(t *testing.T) {
+	anyErr := errors.New("any-err")
+	// the blocking steps wait for the fail fast cancellation, so the test fails by timeout if they are not cancelled.
+	blocking := func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return errors.New("the running step was not cancelled")
+		}
+	}
+	input := []ParallelStepConfig[any]{
+		{Step: &dagStepMock{name: "a", fn: blocking}},
+		{Step: &dagStepMock{name: "b", fn: func(ctx context.Context) error { return anyErr }}},
+		{Step: &dagStepMock{name: "c", fn: blocking}},
+	}
+
+	err := NewParallel("some-workflow", input, WithFailFast()).Execute(context.TODO(), nil)
+
+	if !reflect.DeepEqual(FailedSteps(err), []string{"b"}) {
+		t.Errorf("The failed steps are not as expected: \n expected = %#v, \n actual = %#v", []string{"b"}, FailedSteps(err))
+	}
+	var abortedErr *AbortedError
+	if !errors.As(err, &abortedErr) {
+		t.Fatalf("The workflow error does not hold the aborted steps: \n actual = %#v", err)
+	}
+	abortedSteps := FailedSteps(errors.Join(abortedErr.Errs...))
+	sort.Strings(abortedSteps)
+	if !reflect.DeepEqual(abortedSteps, []string{"a", "c"}) {
+		t.Errorf("The aborted steps are not as expected: \n expected = %#v, \n actual = %#v", []string{"a", "c"}, abortedSteps)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("The aborted steps errors are not as expected: \n expected = %#v, \n actual = %#v", context.Canceled, err)
+	}
+}
+
+func TestParallelExecuteConcurrently(t *testing.T) {
+	// run with -race: the steps share the workflow state, e.g. the logger, the hooks and the cache.
+	const steps = 20
+	var mu sync.Mutex
+	var invocationCount int
+	input := make([]ParallelStepConfig[any], 0, steps)
+	for i := 0; i < steps; i++ {
+		var err error
+		if i%2 == 0 {
+			err = errors.New("any-err")
+		}
+		input = append(input, ParallelStepConfig[any]{
+			Step: &dagStepMock{name: "step", fn: func(ctx context.Context) error {
+				mu.Lock()
+				invocationCount++
+				mu.Unlock()
+
+				return err
+			}},
+			NameOverride: "step " + string(rune('a'+i)),
+		})
+	}
+	log := &syncLoggerMock{}
+	wf := NewParallel(
+		"some-workflow",
+		input,
+		WithLogger(log),
+		WithFailFast(),
+		WithAfterStep(func(ctx context.Context, stepName string, err error, attempt int) {}),
+	)
+
+	err := wf.Execute(context.TODO(), nil)
+
+	if err == nil {
+		t.Errorf("The workflow error is not as expected: \n expected an error, \n actual = %#v", err)
+	}
+	if invocationCount != steps {
+		t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", steps, invocationCount)
+	}
+}
+
+func TestParallelExecuteBehaviourOnPriority(t *testing.T) {
+	var executed []string
+	newStep := func(name string) *dagStepMock {
+		// a single step runs at a time, so the steps don't need to synchronize.
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			executed = append(executed, name)
+
+			return nil
+		}}
+	}
+	input := []ParallelStepConfig[any]{
+		{Step: newStep("background 1")},
+		{Step: newStep("urgent 1"), Priority: 10},
+		{Step: newStep("normal"), Priority: 5},
+		{Step: newStep("background 2")},
+		{Step: newStep("urgent 2"), Priority: 10},
+	}
+
+	err := NewParallel("some-workflow", input, WithMaxConcurrency(1)).Execute(context.TODO(), nil)
+
+	if err != nil {
+		t.Fatalf("The workflow returned an unexpected error: %v", err)
+	}
+	expectedOutput := []string{"urgent 1", "urgent 2", "normal", "background 1", "background 2"}
+	if !reflect.DeepEqual(executed, expectedOutput) {
+		t.Errorf("The steps dispatch order is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, executed)
+	}
+}
+
+func TestParallelExecuteBehaviourOnMaxConcurrencyAndFailFast(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step2 := &dagStepMock{name: "step 2", fn: func(ctx context.Context) error { return nil }}
+	input := []ParallelStepConfig[any]{
+		{Step: &dagStepMock{name: "step 1", fn: func(ctx context.Context) error { return anyErr }}},
+		{Step: step2},
+	}
+
+	err := NewParallel("some-workflow", input, WithMaxConcurrency(1), WithFailFast()).Execute(context.TODO(), nil)
+
+	// the waiting steps are not started once a step failed.
+	if step2.invocationCount != 0 {
+		t.Errorf("The waiting step was started after the failure: \n invocations = %#v", step2.invocationCount)
+	}
+	var abortedErr *AbortedError
+	if !errors.As(err, &abortedErr) || !reflect.DeepEqual(FailedSteps(errors.Join(abortedErr.Errs...)), []string{"step 2"}) {
+		t.Errorf("The workflow error does not hold the aborted steps: \n actual = %#v", err)
+	}
+}
+
+func TestParallelExecuteBehaviourOnDoneContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	step := &dagStepMock{name: "step 1", fn: func(ctx context.Context) error { return nil }}
+
+	err := NewParallel("some-workflow", []ParallelStepConfig[any]{{Step: step}}).Execute(ctx, nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", context.Canceled, err)
+	}
+	if step.invocationCount != 0 {
+		t.Errorf("The step was executed with a done ctx: \n invocations = %#v", step.invocationCount)
+	}
+}
+
+func TestParallelExecuteErrorsOrder(t *testing.T) {
+	anyErr := errors.New("any-err")
+	// every step fails only once the following one failed, so the steps finish in the reverse declaration order.
+	done := []chan struct{}{make(chan struct{}), make(chan struct{}), make(chan struct{})}
+	newStep := func(name string, i int) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			defer close(done[i])
+			if i+1 < len(done) {
+				<-done[i+1]
+			}
+
+			return anyErr
+		}}
+	}
+	input := []ParallelStepConfig[any]{
+		{Step: newStep("step 1", 0)},
+		{Step: newStep("step 2", 1)},
+		{Step: newStep("step 3", 2)},
+	}
+
+	err := NewParallel("some-workflow", input).Execute(context.TODO(), nil)
+
+	expectedOutput := []string{"step 1", "step 2", "step 3"}
+	if actualOutput := FailedSteps(err); !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The failed steps order is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestParallelExecuteBehaviourOnRequestCloner(t *testing.T) {
+	var mu sync.Mutex
+	var clones int
+	cloner := func(req *visitsReq) *visitsReq {
+		mu.Lock()
+		clones++
+		mu.Unlock()
+		visits := make(map[string]int, len(req.visits))
+		for k, v := range req.visits {
+			visits[k] = v
+		}
+
+		return &visitsReq{visits: visits}
+	}
+	var input []ParallelStepConfig[*visitsReq]
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		input = append(input, ParallelStepConfig[*visitsReq]{Step: &visitStep{name: name}})
+	}
+	req := &visitsReq{visits: map[string]int{"start": 1}}
+
+	// the steps mutate the req concurrently, which is a data race, reported by the race detector, without the cloner.
+	p := NewParallel("some-workflow", input)
+	p.SetRequestCloner(cloner)
+	err := p.Execute(context.TODO(), req)
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	if clones != len(input) {
+		t.Errorf("The number of clones is not as expected: \n expected = %#v, \n actual = %#v", len(input), clones)
+	}
+	expectedOutput := map[string]int{"start": 1}
+	if !reflect.DeepEqual(req.visits, expectedOutput) {
+		t.Errorf("The shared req was mutated by the steps: \n expected = %#v, \n actual = %#v", expectedOutput, req.visits)
+	}
+}
+
+// MOCKS/STUBS
+
+// visitsReq is a req recording the steps which visited it.
+type visitsReq struct {
+	visits map[string]int
+}
+
+// visitStep is a step mutating its req, by recording its visit.
+type visitStep struct {
+	name string
+}
+
+func (s *visitStep) Name() string {
+	return s.name
+}
+
+func (s *visitStep) Execute(_ context.Context, req *visitsReq) error {
+	req.visits[s.name]++
+	if req.visits[s.name] != 1 || req.visits["start"] != 1 {
+		return errors.New("the req was visited by another step")
+	}
+
+	return nil
+}
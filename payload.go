@@ -0,0 +1,31 @@
+package workflow
+
+import "context"
+
+// WithPayloadLogger logs, at the Debug level, the req passed to every executed step, once, before its first attempt, as
+// formatted by the format, e.g. to debug a workflow. The req is generic, and may be huge or sensitive, so the format
+// decides how much of it is logged, e.g. by truncating or redacting it. The req is the one passed to the step, so the
+// one returned by its SequentialStepConfig.RequestFunc, if set. The skipped steps are not logged.
+// A nil format logs no payload, which is the default.
+func WithPayloadLogger(format func(stepName string, payload any) string) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.log.formatPayload = format
+	}
+}
+
+// WithPipePayloadLogger is the Pipe version of WithPayloadLogger, which also logs the output of every succeeded step.
+func WithPipePayloadLogger(format func(stepName string, payload any) string) PipeOption {
+	return func(o *pipeOptions) {
+		o.log.formatPayload = format
+	}
+}
+
+// printPayload logs the payload passed to, or returned by, the step named stepName, as described by the label, if the
+// payload logging is enabled. The payload is converted to an interface only if it's logged, so a disabled payload
+// logging costs no allocation.
+func printPayload[T any](ctx context.Context, l logger, stepName, label string, payload T) {
+	if l.formatPayload == nil {
+		return
+	}
+	l.printStep(ctx, LevelDebug, concatStr("step: ", stepName, ", ", label, ": ", l.formatPayload(stepName, payload)), stepName, 0)
+}
@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnPayloadLogger(t *testing.T) {
+	type payload struct {
+		stepName string
+		payload  any
+	}
+	var actualOutput []payload
+	format := func(stepName string, p any) string {
+		actualOutput = append(actualOutput, payload{stepName: stepName, payload: p})
+
+		return fmt.Sprint(p)[:3]
+	}
+	log := &leveledLoggerMock{}
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepSuccessful("step 2"), RequestFunc: func(ctx context.Context, req any) any { return "step 2 request" }},
+		{Step: newStepSuccessful("step 3"), ShouldRun: func(ctx context.Context, req any) bool { return false }},
+	}
+
+	err := NewSequential("some-workflow", input, WithLogger(log), WithPayloadLogger(format)).Execute(context.TODO(), "some request")
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	// the skipped step is not logged, and the RequestFunc output is the payload of its step.
+	expectedOutput := []payload{{stepName: "step 1", payload: "some request"}, {stepName: "step 2", payload: "step 2 request"}}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The formatted payloads are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	if !contains(log.debug, "step: step 1, input: som") || !contains(log.debug, "step: step 2, input: ste") {
+		t.Errorf("The formatted payloads are not logged: \n actual = %#v", log.debug)
+	}
+}
+
+func TestPipeExecuteBehaviourOnPayloadLogger(t *testing.T) {
+	var actualOutput []string
+	format := func(stepName string, p any) string {
+		s := stepName + "=" + p.(string)
+		actualOutput = append(actualOutput, s)
+
+		return s
+	}
+	log := &leveledLoggerMock{}
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: newPipeStepFailedNonRetryable[string]("step 2", errors.New("any-err"))},
+	}
+
+	NewPipe("some-workflow", input, WithPipeLogger(log), WithPipePayloadLogger(format)).Execute(context.TODO(), "x")
+
+	// the failed step has no output to log.
+	expectedOutput := []string{"step 1=x", "step 1=x-a", "step 2=x-a"}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The formatted payloads are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	if !contains(log.debug, "step: step 1, input: step 1=x") || !contains(log.debug, "step: step 1, output: step 1=x-a") {
+		t.Errorf("The formatted payloads are not logged: \n actual = %#v", log.debug)
+	}
+}
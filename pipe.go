@@ -2,10 +2,19 @@ package workflow
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"time"
 )
 
+// ErrStopPipe is returned by a PipeStep, along with its output, to complete the Pipe early: the following steps don't
+// run, and the workflow returns the step output with a nil error, as if the step was the last one, e.g. for a validation
+// step finding that no further processing is needed. The step is considered succeeded, so it's neither retried nor
+// reported as failed, and its output is stored as for any succeeded step. It can be wrapped, as it's checked using
+// errors.Is. A Pipe nested in another workflow stops only itself.
+var ErrStopPipe = errors.New("stop pipe")
+
 // PipeStep describes a step of execution.
 type PipeStep[T any] interface {
 	// Name provides the identity of the step.
@@ -18,30 +27,339 @@ type PipeStep[T any] interface {
 // PipeStepConfig provides configuration for a PipeStep of execution.
 type PipeStepConfig[T any] struct {
 	Step PipeStep[T]
-	// define this only if the Step implements RetryDecider, otherwise it has no effect and no sense!
+	// define this only if the Step implements RetryDecider or ErrorRetryDecider, or if RetryIf is set, otherwise it has no
+	// effect and no sense!
 	RetryConfigProvider func() (maxAttempts uint, attemptDelay time.Duration) // provides the retry configuration
+	// RetryIf decides if the Step runs again, after the attempt numbered attempt(0 for the first execution) failed with
+	// the err, instead of the RetryDecider or ErrorRetryDecider implemented by the Step, so any Step can be retried,
+	// without changing its type. It is called after every failed attempt, the last one included, so a failure it refuses
+	// to retry is stored as permanently failed, but the Step runs again only while the attempts provided by the
+	// RetryConfigProvider are not spent, so a nil RetryConfigProvider means no retry, even if RetryIf is set. A recovered
+	// panic is never retried.
+	RetryIf func(ctx context.Context, err error, attempt uint) bool
+	// Backoff computes the delay before every retry attempt, and takes precedence over the attemptDelay provided by the
+	// RetryConfigProvider. The maximum number of attempts is still provided by the RetryConfigProvider.
+	Backoff BackoffStrategy
+	// RetryDelay computes the delay before every retry attempt from the error of the previous attempt, same as for the
+	// SequentialStepConfig.
+	RetryDelay func(attempt uint, lastErr error) time.Duration
+	// RetryBudget bounds the total time spent on retrying the Step, measured from the first attempt. A retry whose delay
+	// would exceed the RetryBudget is not attempted, even if the maximum number of attempts is not reached.
+	// A zero RetryBudget means no time limit.
+	RetryBudget time.Duration
+	// Timeout bounds every execution attempt of the Step, its Validate included, by the deadline of the ctx passed to
+	// it. A zero Timeout means no timeout.
+	Timeout time.Duration
+	// ShouldRun decides, at execution time, if the Step runs for the req. A nil ShouldRun means the Step always runs.
+	// A Step not running passes the req through, unchanged, to the next step.
+	ShouldRun func(ctx context.Context, req T) bool
+	// Validate checks the req before every execution of the Step, which is not executed if the req is rejected. The
+	// validation error is the error of the attempt, so it's retried, or not, and stops the workflow, or not, as a Step
+	// error. A nil Validate means no validation.
+	Validate func(ctx context.Context, req T) error
+	// Enabled decides, at execution time, if the Step is enabled, whatever the req, same as for the SequentialStepConfig.
+	// A disabled Step passes the req through, unchanged, to the next step.
+	Enabled func(ctx context.Context) bool
+	// IdempotencyKey identifies the Step execution result in the storage, instead of the Step name, if not empty.
+	// It allows the steps sharing a name to be replayed correctly, see the Storage documentation.
+	IdempotencyKey string
+	// Aliases holds the previous storage keys of the Step, checked after its current key, same as for the
+	// SequentialStepConfig. The stored output of the Step is read under the key its succeeded result is found under.
+	Aliases []string
+	// ContinueOnError decides if the workflow goes on when the Step fails, in which case the Step passes the req through,
+	// unchanged, to the next step, as if it didn't run.
+	ContinueOnError bool
+	// NameOverride replaces the Step name, if not empty, in the logs, the storage keys, the errors and the metrics, e.g.
+	// to tell apart the uses of a workflow nested in several parent workflows.
+	NameOverride string
+	// CircuitBreaker stops executing the Step, same as for the SequentialStepConfig.
+	CircuitBreaker *CircuitBreaker
+	// Logger replaces the Logger of the workflow for the log lines of the Step, same as for the SequentialStepConfig.
+	Logger Logger
+}
+
+// name returns the name identifying the Step in the workflow.
+func (c PipeStepConfig[T]) name() string {
+	if c.NameOverride != "" {
+		return c.NameOverride
+	}
+
+	return c.Step.Name()
+}
+
+// retryable reports whether the Step can be retried, by implementing the RetryDecider or the ErrorRetryDecider
+// interface, or by having a RetryIf.
+func (c PipeStepConfig[T]) retryable() bool {
+	return c.RetryIf != nil || isRetryable(c.Step)
+}
+
+// canRetry reports whether the Step can run again, after the attempt failed with the err, as decided by the RetryIf,
+// if set, otherwise by the Step.
+func (c PipeStepConfig[T]) canRetry(ctx context.Context, err error, attempt uint) bool {
+	if c.RetryIf != nil && !isPanic(err) {
+		return c.RetryIf(ctx, err, attempt)
+	}
+
+	return canRetry(c.Step, err)
+}
+
+// execute validates the req, if the Validate is set, and executes the Step for the valid req, within the Timeout, if
+// greater than 0.
+func (c PipeStepConfig[T]) execute(ctx context.Context, req T) (T, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	if c.Validate != nil {
+		if err := c.Validate(ctx, req); err != nil {
+			var out T
+
+			return out, err
+		}
+	}
+
+	return c.Step.Execute(ctx, req)
+}
+
+// storageKey returns the key identifying the Step execution result in the storage.
+func (c PipeStepConfig[T]) storageKey() string {
+	if c.IdempotencyKey != "" {
+		return c.IdempotencyKey
+	}
+
+	return c.name()
+}
+
+// PipeOption configures a Pipe workflow.
+type PipeOption func(*pipeOptions)
+
+// pipeOptions holds the optional configuration of a Pipe workflow.
+type pipeOptions struct {
+	log     logger // the internal logger is a no op if no Logger is provided
+	store   stepsStore
+	codec   Codec // serializes the steps output values for the storage, JSONCodec if not provided
+	metrics stepMetrics
+	clock   Clock // the system time is used if nil
+	// stepFilter runs only the steps it returns true for, if not nil
+	stepFilter func(stepName string) bool
+	end        endHooks
+	execID     executionID
+	onRetry    func(ctx context.Context, stepName string, attempt uint, err error, nextDelay time.Duration)
+	// stepNames passes the workflow and the step names to the steps, in their ctx
+	stepNames bool
+	// enrichContext decorates the ctx passed to every step, if not nil
+	enrichContext func(ctx context.Context, stepName string) context.Context
+	wrapErrs      bool // wraps the returned errors in a WorkflowError
+	// returnLastGood returns the input of the failing step stopping the workflow, instead of its output
+	returnLastGood bool
+	// minRemainingTime stops the workflow before a step with less time left until the ctx deadline, if greater than 0
+	minRemainingTime time.Duration
+	// retryBackoffCap bounds the delay before every retry attempt, if greater than 0
+	retryBackoffCap time.Duration
+	maxSteps        int             // fails the executions with more steps, if greater than 0
+	limiter         Limiter         // throttles the steps execution attempts, if not nil
+	stop            <-chan struct{} // stops the workflow before the next step, once closed
+	// maxConsecutiveFailures stops retrying a step failing that many times with the same error, if greater than 0
+	maxConsecutiveFailures uint
+	progress               chan<- StepEvent // receives the event of every executed step
+	// strictValidation makes the constructor panic if the configuration is not valid
+	strictValidation bool
+}
+
+// WithPipeLogger sets the logger used by the workflow.
+// If not provided, or if nil is provided, the logging is disabled.
+func WithPipeLogger(log Logger) PipeOption {
+	return func(o *pipeOptions) {
+		o.log.log = log
+	}
+}
+
+// WithPipeStorage sets the storage used to persist the steps execution results and output values, for the correlation ID
+// set by WithPipeCorrelationID.
+// When the workflow is executed again with the same correlation ID, the steps that already succeeded are skipped, and their
+// stored output value is handed to the next step. The output values are serialized by the Codec set by WithPipeCodec.
+// The stored results are cleared once the workflow succeeds.
+func WithPipeStorage(storage Storage) PipeOption {
+	return func(o *pipeOptions) {
+		o.store.storage = storage
+	}
+}
+
+// WithPipeCodec sets the Codec serializing the steps output values for the storage set by WithPipeStorage.
+// If not provided, or if nil is provided, the JSONCodec is used, so T must support the JSON encoding for the replay to work.
+func WithPipeCodec(codec Codec) PipeOption {
+	return func(o *pipeOptions) {
+		o.codec = codec
+	}
+}
+
+// WithPipeCorrelationID sets the ID under which the steps execution results are persisted in the storage set by WithPipeStorage.
+func WithPipeCorrelationID(id string) PipeOption {
+	return func(o *pipeOptions) {
+		o.store.correlationID = id
+	}
+}
+
+// WithPipeStorageTTL sets the duration after which the steps execution results stored in the storage set by WithPipeStorage
+// expire, and are treated as absent. It works only with a ResultStorage, which records the time the results were saved at.
+// A zero TTL means the stored results never expire.
+// The results expire one by one, as described by WithStorageTTL, and a step run again because its result expired
+// receives the output of the previous step, either stored or recomputed.
+func WithPipeStorageTTL(ttl time.Duration) PipeOption {
+	return func(o *pipeOptions) {
+		o.store.ttl = ttl
+	}
+}
+
+// WithPipeStorageErrorPolicy sets how the errors of the storage set by WithPipeStorage are handled.
+// If not provided, the StoragePolicyFail is used.
+func WithPipeStorageErrorPolicy(policy StorageErrorPolicy) PipeOption {
+	return func(o *pipeOptions) {
+		o.store.errorPolicy = policy
+	}
+}
+
+// WithPipeErrorWrapping wraps the error returned by the workflow in a WorkflowError, same as WithErrorWrapping.
+func WithPipeErrorWrapping() PipeOption {
+	return func(o *pipeOptions) {
+		o.wrapErrs = true
+	}
+}
+
+// WithPipeReturnLastGood makes the workflow stopped by a failing step return the last good value, i.e. the input of the
+// failing step, which is the output of the last succeeded step, or the req if the first step fails, instead of the output
+// of the failing step, which is often the zero value. It allows the caller to proceed with a partial transformation.
+func WithPipeReturnLastGood() PipeOption {
+	return func(o *pipeOptions) {
+		o.returnLastGood = true
+	}
+}
+
+// WithPipeStepKeyFunc sets the function mapping the step names to the keys of their results and output values in the
+// storage set by WithPipeStorage, same as WithStepKeyFunc.
+func WithPipeStepKeyFunc(fn func(name string) string) PipeOption {
+	return func(o *pipeOptions) {
+		o.store.keyFunc = fn
+	}
+}
+
+// WithPipeStorageOutputTransformer sets the function building the output stored for a failed step, same as
+// WithStorageOutputTransformer. The output values of the succeeded steps are not affected, as they are the steps data.
+func WithPipeStorageOutputTransformer(fn func(stepName string, err error) *string) PipeOption {
+	return func(o *pipeOptions) {
+		o.store.outputTransformer = fn
+	}
+}
+
+// WithPipeCorrelationIDFromContext sets the function reading, from the execution ctx, the ID under which the steps execution
+// results are persisted in the storage set by WithPipeStorage, allowing a workflow to be reused across requests.
+// The ID read from the ctx takes precedence over the one set by WithPipeCorrelationID, which is used only if the read ID is empty.
+func WithPipeCorrelationIDFromContext(fn func(ctx context.Context) string) PipeOption {
+	return func(o *pipeOptions) {
+		o.store.correlationIDFromCtx = fn
+	}
+}
+
+// WithPipeClock sets the Clock measuring the retries delays and budgets, and the steps durations logged, e.g. to test
+// them without waiting.
+// If not provided, or if nil is provided, the system time is used.
+func WithPipeClock(clock Clock) PipeOption {
+	return func(o *pipeOptions) {
+		o.clock = clock
+	}
+}
+
+// WithPipeOnComplete registers a hook called once per execution, when the workflow ends, with the final error returned
+// by Execute, nil on success, same as WithOnComplete.
+func WithPipeOnComplete(fn func(ctx context.Context, err error)) PipeOption {
+	return func(o *pipeOptions) {
+		o.end.onComplete = fn
+	}
+}
+
+// WithPipeOnError registers a hook called once per execution, when the workflow ends with an error, same as WithOnError.
+func WithPipeOnError(fn func(ctx context.Context, err error)) PipeOption {
+	return func(o *pipeOptions) {
+		o.end.onError = fn
+	}
+}
+
+// WithPipeOnRetry registers a hook called right before waiting for every retry attempt of a step, same as WithOnRetry.
+func WithPipeOnRetry(fn func(ctx context.Context, stepName string, attempt uint, err error, nextDelay time.Duration)) PipeOption {
+	return func(o *pipeOptions) {
+		o.onRetry = fn
+	}
+}
+
+// WithPipeStepFilter runs only the steps for which the filter returns true, given their name, same as WithStepFilter.
+// The filtered out steps pass their input through unchanged, as for a PipeStepConfig.ShouldRun returning false.
+func WithPipeStepFilter(filter func(stepName string) bool) PipeOption {
+	return func(o *pipeOptions) {
+		o.stepFilter = filter
+	}
 }
 
 // Pipe is a workflow that runs its steps in a predefined sequence(the order of the []PipeStepConfig).
+// The Pipe holds no execution state, so Execute can be called concurrently, with distinct requests, as long as the
+// steps, the Logger, the Storage and the Metrics are safe for concurrent use, which is the user responsibility.
 type Pipe[T any] struct {
 	name        string
 	stepsConfig []PipeStepConfig[T] // the workflow runs the steps following the slice order
-	log         Logger              // the internal logger is a no op if nil is provided
+	pipeOptions
 }
 
-// NewPipe is the workflow constructor.
-func NewPipe[T any](name string, stepsCfg []PipeStepConfig[T], log Logger) *Pipe[T] {
-	if log == nil {
-		log = noOpLogger{}
-	}
+// the workflow implements the PipeStep interface, so it can be a step of another workflow.
+var _ PipeStep[any] = (*Pipe[any])(nil)
 
-	s := Pipe[T]{
+// NewPipe is the workflow constructor.
+// The nil options are ignored, so NewPipe(name, stepsCfg, nil) builds a workflow with the default configuration.
+// If WithPipeStrictValidation is provided, it panics with the Validate error, for a configuration which is not valid.
+//
+// Migration note: the logger used to be the third positional argument of NewPipe. The callers passing nil still compile
+// and behave the same, while the callers passing a Logger must migrate to NewPipe(name, stepsCfg, WithPipeLogger(log)).
+func NewPipe[T any](name string, stepsCfg []PipeStepConfig[T], opts ...PipeOption) *Pipe[T] {
+	p := Pipe[T]{
 		name:        name,
 		stepsConfig: stepsCfg,
-		log:         log,
+		pipeOptions: newPipeOptions(name, stepsCfg, opts),
+	}
+
+	return &p
+}
+
+// newPipeOptions applies the opts over the default configuration, for the workflow named name.
+// The options are applied on a lazily allocated value, so that the workflow built without options produces no allocations.
+// It panics if the strict validation is enabled and the stepsCfg are not valid. The validation is done here, rather than
+// in the constructor, to keep the constructor inlined, and so the workflow off the heap.
+func newPipeOptions[T any](name string, stepsCfg []PipeStepConfig[T], opts []PipeOption) pipeOptions {
+	var o *pipeOptions
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if o == nil {
+			o = new(pipeOptions)
+		}
+		opt(o)
+	}
+	if o == nil {
+		return pipeOptions{log: logger{log: noOpLogger{}, workflowName: name}, codec: JSONCodec{}}
+	}
+	if o.log.log == nil {
+		o.log.log = noOpLogger{}
+	}
+	if o.codec == nil {
+		o.codec = JSONCodec{}
+	}
+	o.log.workflowName = name
+	if o.strictValidation {
+		if err := validatePipeSteps(name, stepsCfg); err != nil {
+			panic(err)
+		}
 	}
 
-	return &s
+	return *o
 }
 
 // Name returns the name of the workflow.
@@ -49,66 +367,380 @@ func (p *Pipe[T]) Name() string {
 	return p.name
 }
 
+// SetCorrelationID replaces the ID under which the steps execution results are persisted in the storage set by
+// WithPipeStorage, as WithPipeCorrelationID does at construction time.
+// It allows a single workflow to be reused across requests, instead of building a new one for every request, when the
+// steps hold no per request state. It must be called between the executions, as it's not safe to call concurrently with
+// Execute: the workflows executed concurrently must read their correlation ID from the ctx, see
+// WithPipeCorrelationIDFromContext.
+func (p *Pipe[T]) SetCorrelationID(id string) {
+	p.store.correlationID = id
+}
+
 // Execute loops through all the steps from the s.stepsConfig collection, passes the ctx and the req to the first PipeStepConfig.Step,
 // and the following steps receive as request, the output from the previous step - pipe like behaviour.
-// The workflow stops at the first failing step and returns the error produced by the step.
+// The workflow stops at the first failing step and returns the error produced by the step, wrapped in a StepError, along
+// with the output of the failing step, or its input if WithPipeReturnLastGood is provided, unless
+// the step is configured with PipeStepConfig.ContinueOnError, in which case the following step receives the input of the
+// failing step. The errors of all the failing steps are then wrapped in a single error, returned along with the output
+// of the last step.
+// A step returning ErrStopPipe completes the workflow early: the following steps don't run, and its output is returned,
+// along with the errors of the previous failing steps, if any.
+// If a storage and a correlation ID are configured, the steps that already succeeded for the correlation ID are skipped,
+// and the following step receives the stored output of the skipped step. The stored results are cleared once the workflow succeeds.
+// The steps whose PipeStepConfig.ShouldRun returns false, or filtered out by the WithPipeStepFilter, are skipped, and the
+// value is passed through unchanged.
+// The Cacheable steps which already succeeded in the execution, for their cache key, are skipped, and the next step
+// receives their cached output.
+// The ctx is checked before every step, and a done ctx stops the workflow, the ctx error being returned along with the
+// steps errors, and the value the next step would have received. The time left until the ctx deadline, if any, is logged
+// before every step, and the workflow stops with context.DeadlineExceeded before a step if it's less than the minimum
+// set by WithPipeMinRemainingTime.
+// The workflow configured with WithPipeMaxSteps fails with ErrTooManySteps, before running any step, if it has more
+// steps than the max, the req being returned.
+// A nil PipeStepConfig.Step stops the workflow with an error identifying its index, see Validate to report it at
+// construction time.
 func (p *Pipe[T]) Execute(ctx context.Context, req T) (T, error) {
-	p.log.Info(concatStr("[START] executing workflow: ", p.name))
-	defer func() { p.log.Info(concatStr("[DONE] executing workflow: ", p.name)) }()
+	ctx = p.execID.context(ctx)
+	out, err := p.execute(ctx, req, nil)
+
+	return out, p.end.end(ctx, wrapWorkflowErr(p.wrapErrs, p.name, err))
+}
 
+// execute runs the workflow, as described by Execute, and records the steps outputs in the trace, if it's not nil.
+func (p *Pipe[T]) execute(ctx context.Context, req T, trace *pipeTrace[T]) (T, error) {
+	p.log.print(ctx, LevelInfo, concatStr("[START] executing workflow: ", p.name))
+	defer p.log.printDone(ctx)
+
+	if err := maxStepsErr(p.name, len(p.stepsConfig), p.maxSteps); err != nil {
+		p.log.print(ctx, LevelError, concatStr(failed, " stopping workflow: ", p.name, ", err: ", err.Error()))
+
+		return req, err
+	}
 	var out T
+	var errs []error
 	var err error
+	var cache pipeResults[T]
 	for i, stepConfig := range p.stepsConfig {
-		out, err = p.executeStep(ctx, stepConfig, req)
-		if i > 0 {
-			req = out
+		// a done ctx, or one without enough time left, stops the workflow before running the next step.
+		if err = p.checkStep(ctx, i, stepConfig); err != nil {
+			return req, joinErrs(append(errs, err))
+		}
+		if !p.shouldRun(ctx, stepConfig, req) {
+			trace.add(PipeStepOutput[T]{StepName: stepConfig.name(), Value: req, Skipped: true})
+			out = req
+
+			continue
+		}
+		var skipped bool
+		out, skipped, err = p.processStep(ctx, stepConfig, req, &cache)
+		trace.add(PipeStepOutput[T]{StepName: stepConfig.name(), Value: out, Err: stepResultErr(err), Skipped: skipped})
+		if err == ErrStopPipe {
+			p.log.print(ctx, LevelInfo, concatStr("stopping workflow: ", p.name, ", completed early by step: ", stepConfig.name()))
+
+			break
 		}
 		if err != nil {
-			return out, err
+			if !stepConfig.ContinueOnError {
+				out = p.failedOutput(req, out)
+				// prevents the errors collection allocation, if there are no errors from the previous steps.
+				if errs == nil {
+					return out, err
+				}
+
+				return out, joinErrs(append(errs, err))
+			}
+			errs = appendErr(errs, err, len(p.stepsConfig))
+			p.log.print(
+				ctx,
+				LevelInfo,
+				concatStr(
+					"the step name: ",
+					stepConfig.name(),
+					", is configured not to stop the workflow on error, so the following steps(if any) receive its input",
+				),
+			)
+			out = req
+
+			continue
 		}
+		req = out
+	}
+	if len(errs) > 0 {
+		return out, joinErrs(errs)
 	}
 
-	return out, nil
+	return out, p.store.handleErr(ctx, p.log, p.store.clearDB(ctx))
 }
 
-// executeStep processes a single PipeStep by passing it the ctx and the req.
-// It retries the PipeStep if it implements the RetryDecider interface, and uses the max attempts and the attempt delay provided
-// by the PipeStepConfig.RetryConfigProvider() if it's not nil. If the PipeStepConfig.RetryConfigProvider() is nil, there is no retry.
-func (p *Pipe[T]) executeStep(ctx context.Context, stepCfg PipeStepConfig[T], req T) (T, error) {
+// checkStep returns the error stopping the workflow before the step found at the index i: the error of a nil step, the
+// ctx error, ErrStopped if the stop signal is closed, or context.DeadlineExceeded if the time left until the ctx deadline
+// is less than the minimum set by WithPipeMinRemainingTime.
+func (p *Pipe[T]) checkStep(ctx context.Context, i int, stepCfg PipeStepConfig[T]) error {
+	if stepCfg.Step == nil {
+		err := nilStepErr(i)
+		p.log.print(ctx, LevelError, concatStr(failed, " stopping workflow: ", p.name, ", err: ", err.Error()))
+
+		return err
+	}
+	err := ctx.Err()
+	if err == nil {
+		err = stopped(p.stop)
+	}
+	if err == nil {
+		err = checkRemainingTime(ctx, p.log, p.clock, stepCfg.name(), p.minRemainingTime)
+	}
+	if err != nil {
+		p.log.print(ctx, LevelError, concatStr(failed, " stopping workflow: ", p.name, ", err: ", err.Error()))
+	}
+
+	return err
+}
+
+// failedOutput returns the output of the workflow stopped by a failing step: the out returned by the step, or its req
+// if WithPipeReturnLastGood is provided.
+func (p *Pipe[T]) failedOutput(req, out T) T {
+	if p.returnLastGood {
+		return req
+	}
+
+	return out
+}
+
+// stepResultErr returns the error of the step result, which is nil for a step stopping the workflow by ErrStopPipe.
+func stepResultErr(err error) error {
+	if err == ErrStopPipe {
+		return nil
+	}
+
+	return err
+}
+
+// shouldRun reports whether the step runs for the req, according to the step filter, and to its PipeStepConfig.Enabled
+// and ShouldRun.
+func (p *Pipe[T]) shouldRun(ctx context.Context, stepCfg PipeStepConfig[T], req T) bool {
+	stepName := stepCfg.name()
+	log := p.log.override(stepCfg.Logger)
+	if p.stepFilter != nil && !p.stepFilter(stepName) {
+		log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", filtered out"), stepName, 0)
+
+		return false
+	}
+	if stepCfg.Enabled != nil && !stepCfg.Enabled(ctx) {
+		log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", disabled"), stepName, 0)
+
+		return false
+	}
+	if stepCfg.ShouldRun == nil || stepCfg.ShouldRun(ctx, req) {
+		return true
+	}
+	log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", not required to run"), stepName, 0)
+
+	return false
+}
+
+// processStep skips the step if it already succeeded for the correlation ID, or in the execution for its cache key, by
+// returning its stored or cached output, otherwise it executes it. It reports whether the step was skipped.
+// The ErrStopPipe returned by the step is returned as it is.
+func (p *Pipe[T]) processStep(ctx context.Context, stepCfg PipeStepConfig[T], req T, cache *pipeResults[T]) (T, bool, error) {
+	stepName := stepCfg.name()
+	log := p.log.override(stepCfg.Logger)
+	out, skip, err := p.skipStep(ctx, stepCfg)
+	if err = p.store.handleErr(ctx, log, err); err != nil {
+		return out, false, err
+	}
+	if skip {
+		log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", already succeeded"), stepName, 0)
+
+		return out, true, nil
+	}
+
+	key, cacheable := stepCacheKey(stepCfg.Step, req)
+	if cached, ok := (*cache)[key]; cacheable && ok {
+		log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", already succeeded for the cache key: ", key), stepName, 0)
+
+		return cached, true, nil
+	}
+
+	printPayload(ctx, log, stepName, "input", req)
+	metricsStart := p.metrics.started(ctx, stepName)
+	out, attempts, err := p.executeStep(ctx, stepCfg, req)
+	p.metrics.finished(ctx, stepName, metricsStart, stepResultErr(err))
+	if stepResultErr(err) == nil {
+		printPayload(ctx, log, stepName, "output", out)
+	}
+	sendProgress(ctx, p.progress, stepName, attempts, stepResultErr(err))
+	if cacheable && err == nil {
+		cache.put(key, out)
+	}
+
+	return out, false, err
+}
+
+// skipStep reports whether the step execution already succeeded for the correlation ID, under its storage key or one of
+// its aliases, and returns its stored output.
+func (p *Pipe[T]) skipStep(ctx context.Context, stepCfg PipeStepConfig[T]) (T, bool, error) {
+	var out T
+	key, skip, failure, err := p.store.skipStepOrAlias(ctx, stepCfg.storageKey(), stepCfg.Aliases)
+	if err != nil {
+		return out, false, err
+	}
+	if !skip {
+		p.log.override(stepCfg.Logger).printReplay(ctx, stepCfg.name(), failure)
+
+		return out, false, nil
+	}
+	id, _ := p.store.id(ctx)
+	value, err := p.store.storage.GetValue(ctx, p.store.key(key), id)
+	if err != nil {
+		return out, false, fmt.Errorf("error getting step output value: %w", err)
+	}
+	out, err = decodeValue[T](p.codec, value)
+	if err != nil {
+		return out, false, fmt.Errorf("error decoding step output value: %w", err)
+	}
+
+	return out, true, nil
+}
+
+// decodeValue decodes the stored output value of a step.
+// It is kept apart from the skipStep, so that the decoded value is moved to the heap only when there is a value to decode.
+func decodeValue[T any](codec Codec, value []byte) (T, error) {
 	var out T
-	step := stepCfg.Step
-	stepName := step.Name()
+	err := codec.Unmarshal(value, &out)
 
-	var maxAttempts uint
-	var attemptDelay time.Duration
-	if stepCfg.RetryConfigProvider != nil {
-		maxAttempts, attemptDelay = stepCfg.RetryConfigProvider()
+	return out, err
+}
+
+// storeStepResult saves the result of the step execution, and on success its output value, for the correlation ID.
+// The output value is saved before the status, so that a step stored as succeeded always has an output value.
+// A failed step is stored as permanently failed if the permanent is true.
+func (p *Pipe[T]) storeStepResult(ctx context.Context, stepCfg PipeStepConfig[T], out T, stepErr error, permanent bool) error {
+	key := stepCfg.storageKey()
+	id, ok := p.store.id(ctx)
+	if !ok {
+		return nil
+	}
+	if stepErr == nil {
+		value, err := p.codec.Marshal(out)
+		if err != nil {
+			return fmt.Errorf("error encoding step output value: %w", err)
+		}
+		if err = p.store.storage.SaveValue(ctx, p.store.key(key), id, value); err != nil {
+			return fmt.Errorf("error storing step output value: %w", err)
+		}
 	}
 
+	return p.store.storeStepResult(ctx, stepCfg.name(), key, stepErr, permanent)
+}
+
+// executeStep processes a single PipeStep by passing it the ctx and the req, once validated by the
+// PipeStepConfig.Validate, if set.
+// It retries the PipeStep if it implements the RetryDecider interface, with CanRetry() returning true, or the ErrorRetryDecider
+// interface, with RetryableError(err) returning true for the error of the last attempt, and uses the max attempts, capped to MaxRetryAttempts, and the attempt delay provided
+// by the PipeStepConfig.RetryConfigProvider() if it's not nil. If the PipeStepConfig.RetryConfigProvider() is nil, there is no retry.
+// The PipeStepConfig.RetryIf, if set, decides the retry instead of the PipeStep.
+// If the PipeStepConfig.Backoff is not nil, it provides the delay before every retry attempt, instead of the attempt delay.
+// If the PipeStepConfig.RetryBudget is greater than 0, the retries stop once their delay would exceed the budget.
+// The waiting before a retry attempt stops as soon as the ctx is done, and the ctx error is returned along with the step error.
+// The ctx passed to the PipeStep carries the workflow and the step names, if enabled by WithPipeStepNamesInContext,
+// and is decorated by the enricher set by WithPipeContextEnricher, if any.
+// The result of the last attempt is persisted in the storage, if configured.
+// The error is wrapped in a StepError, holding the step name and the number of attempts which ran, also returned.
+// A step returning ErrStopPipe succeeds, and the unwrapped ErrStopPipe is returned, to stop the workflow.
+func (p *Pipe[T]) executeStep(ctx context.Context, stepCfg PipeStepConfig[T], req T) (T, uint, error) {
+	var out T
+	stepName := stepCfg.name()
+	log := p.log.override(stepCfg.Logger)
+	stepCtx := enrichContext(withStepNames(ctx, p.stepNames, p.name, stepName), p.enrichContext, stepName)
+
+	maxAttempts, attemptDelay := retryConfig(stepCfg.RetryConfigProvider)
+
 	var attempt uint
+	var attempts uint // the attempts which ran, as the last loop iteration may stop before running the step
 	var err error
+	var stop bool       // the step returned ErrStopPipe
+	var start time.Time // the start of the first attempt, measured only for a retry budget
+	if stepCfg.RetryBudget > 0 {
+		start = now(p.clock)
+	}
+	var permanent bool // the last attempt failed with an error the step can't be retried for
+	streak := failureStreak{max: p.maxConsecutiveFailures}
 	for attempt = 0; attempt <= maxAttempts; attempt++ {
 		// if the attempt is greater than 0, then it's a retry
 		if attempt > 0 {
-			p.log.Info(
-				concatStr("step: ", stepName, " is configured to retry", ", retry attempt count: ", strconv.Itoa(int(attempt))),
+			log.printStep(
+				ctx,
+				LevelDebug,
+				concatStr("step: ", stepName, " is configured to retry", ", retry attempt count: ", strconv.FormatUint(uint64(attempt), 10)),
+				stepName,
+				attempt,
 			)
 			// allow some waiting time before trying again
-			p.log.Info(concatStr("waiting for: ", strconv.FormatInt(attemptDelay.Milliseconds(), 10), "ms before retry attempt"))
-			time.Sleep(attemptDelay)
+			delay := stepCfg.retryDelay(attempt, err, attemptDelay, p.retryBackoffCap)
+			if stepCfg.RetryBudget > 0 && now(p.clock).Sub(start)+delay > stepCfg.RetryBudget {
+				log.printStep(ctx, LevelError, concatStr("step: ", stepName, " exhausted its retry budget"), stepName, attempt)
+
+				break
+			}
+			// the retry is measured only once the budget doesn't refuse it.
+			p.metrics.retried(ctx, stepName, attempt)
+			log.printStep(
+				ctx,
+				LevelDebug,
+				concatStr("waiting for: ", strconv.FormatInt(delay.Milliseconds(), 10), "ms before retry attempt"),
+				stepName,
+				attempt,
+			)
+			if p.onRetry != nil {
+				p.onRetry(ctx, stepName, attempt, err, delay)
+			}
+			if sleepErr := sleep(ctx, p.clock, delay); sleepErr != nil {
+				return out, attempts, StepError{StepName: stepName, Attempts: attempts, Err: errors.Join(err, sleepErr)}
+			}
+		}
+		if waitErr := waitLimiter(ctx, log, p.limiter, stepName, attempt); waitErr != nil {
+			return out, attempts, StepError{StepName: stepName, Attempts: attempts, Err: errors.Join(err, waitErr)}
+		}
+		trial, openErr := checkCircuit(ctx, log, stepCfg.CircuitBreaker, stepName, attempt)
+		if openErr != nil {
+			return out, attempts, StepError{StepName: stepName, Attempts: attempts, Err: errors.Join(err, openErr)}
 		}
-		out, err = step.Execute(ctx, req)
+		attempts++
+		attemptStart := log.startTimer(p.clock)
+		out, err = stepCfg.execute(stepCtx, req)
+		if errors.Is(err, ErrStopPipe) {
+			stop, err = true, nil
+		}
+		stepCfg.CircuitBreaker.record(trial, err)
+		res := attemptResult{stepName: stepName, attempt: attempt, elapsed: log.elapsed(p.clock, attemptStart), err: err}
 		if err == nil {
-			p.log.Info(concatStr(succeed, " executing step: ", stepName))
+			log.printAttempt(ctx, LevelInfo, res)
 
 			break
 		}
-		p.log.Error(concatStr(failed, " executing step: ", stepName, ", err: ", err.Error()))
-		// only the ones implementing the RetryDecider, with CanRetry() returning true, can run more than once
-		if stepR, ok := step.(RetryDecider); !ok || (ok && !stepR.CanRetry()) {
+		// the failure is permanent if the step can't be retried for the err, whether or not it ran out of attempts.
+		permanent = !stepCfg.canRetry(ctx, err, attempt)
+		if attempt == maxAttempts || permanent {
+			log.printAttempt(ctx, LevelError, res)
+
 			break
 		}
+		if streak.stop(ctx, log, res) {
+			break
+		}
+		// the failure is recoverable, as the step is retried.
+		log.printAttempt(ctx, LevelWarn, res)
+	}
+	if dbErr := p.store.handleErr(ctx, log, p.storeStepResult(ctx, stepCfg, out, err, permanent)); dbErr != nil {
+		err = errors.Join(err, dbErr)
+	}
+	if err != nil {
+		return out, attempts, StepError{StepName: stepName, Attempts: attempts, Err: err}
+	}
+	if stop {
+		return out, attempts, ErrStopPipe
 	}
 
-	return out, err
+	return out, attempts, nil
 }
@@ -3,7 +3,16 @@ package workflow
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/silviutanasa/workflow/workflowtest"
 )
 
 func TestPipeExecuteBehaviourOnPreservingErrorsType(t *testing.T) {
@@ -75,6 +84,106 @@ func TestPipeExecuteBehaviourOnReturningErrors(t *testing.T) {
 	}
 }
 
+func TestPipeExecuteBehaviourOnNestedWorkflow(t *testing.T) {
+	nested := NewPipe("nested", []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("nested step 1", "-b")},
+		{Step: newPipeStepAppend("nested step 2", "-c")},
+	})
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: nested},
+		{Step: newPipeStepAppend("step 3", "-d")},
+	}
+
+	// the nested workflow receives the output of the previous step, and its output is passed to the next one.
+	actualOutput, err := NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	if actualOutput != "x-a-b-c-d" {
+		t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", "x-a-b-c-d", actualOutput)
+	}
+}
+
+func TestPipeExecuteBehaviourOnChainingSteps(t *testing.T) {
+	var actualOutput []string
+	newStep := func(name string) *pipeStepFunc[string] {
+		return newPipeStepFunc(name, func(req string) (string, error) {
+			actualOutput = append(actualOutput, req)
+
+			return req + "-" + name, nil
+		})
+	}
+	input := []PipeStepConfig[string]{{Step: newStep("a")}, {Step: newStep("b")}, {Step: newStep("c")}}
+
+	out, err := NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+
+	// every step, the second one included, receives the output of the previous step.
+	expectedOutput := []string{"x", "x-a", "x-a-b"}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps inputs are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	if err != nil || out != "x-a-b-c" {
+		t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v, \n err = %v", "x-a-b-c", out, err)
+	}
+}
+
+func TestPipeExecuteBehaviourOnStopPipe(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name           string
+		input          error // the error returned by the stopping step
+		expectedOutput string
+	}{
+		{
+			name:           "a step returning ErrStopPipe should complete the workflow with its output",
+			input:          ErrStopPipe,
+			expectedOutput: "x-a-b",
+		},
+		{
+			name:           "a step returning a wrapped ErrStopPipe should complete the workflow with its output",
+			input:          fmt.Errorf("no further processing needed: %w", ErrStopPipe),
+			expectedOutput: "x-a-b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopping := newPipeStepFunc("step 2", func(req string) (string, error) { return req + "-b", tt.input })
+			last := newPipeStepAppend("step 3", "-c")
+			m := &metricsMock{}
+			input := []PipeStepConfig[string]{
+				{Step: newPipeStepAppend("step 1", "-a")},
+				{Step: stopping, RetryConfigProvider: defaultRetryConfigProviderTest, RetryIf: func(context.Context, error, uint) bool { return true }},
+				{Step: last},
+			}
+
+			actualOutput, err := NewPipe("some-workflow", input, WithPipeMetrics(m)).Execute(context.TODO(), "x")
+
+			if err != nil {
+				t.Errorf("The workflow returned an unexpected error: %v", err)
+			}
+			if actualOutput != tt.expectedOutput {
+				t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, actualOutput)
+			}
+			// the stopping step succeeded, so it's not retried, nor measured as failed.
+			if stopping.invocationCount != 1 || !contains(m.records, "finished: step 2 "+string(StepStatusSuccess)) {
+				t.Errorf("The stopping step is not as expected: \n invocations = %#v, \n metrics = %#v", stopping.invocationCount, m.records)
+			}
+		})
+	}
+
+	// the errors of the previous failing steps are still returned.
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepFailedNonRetryable[string]("step 1", anyErr), ContinueOnError: true},
+		{Step: newPipeStepFunc("step 2", func(req string) (string, error) { return req + "-b", ErrStopPipe })},
+	}
+	actualOutput, err := NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+	if !errors.Is(err, anyErr) || errors.Is(err, ErrStopPipe) || actualOutput != "x-b" {
+		t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x-b", anyErr, actualOutput, err)
+	}
+}
+
 func TestPipeExecuteBehaviourOnRetry(t *testing.T) {
 	anyErr := errors.New("any-err")
 	tests := []struct {
@@ -166,6 +275,524 @@ func TestPipeExecuteBehaviourOnStoppingWorkflow(t *testing.T) {
 	}
 }
 
+func TestPipeExecuteBehaviourOnContextCancellationDuringRetry(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step := newPipeStepFailedRetryable[any]("step 1", anyErr)
+	input := []PipeStepConfig[any]{
+		{Step: step, RetryConfigProvider: func() (uint, time.Duration) { return 2, time.Hour }},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	c := NewPipe("some-workflow", input, nil)
+	_, actualOutput := c.Execute(ctx, nil)
+
+	if !errors.Is(actualOutput, context.Canceled) {
+		t.Errorf("The workflow error does not wrap the context error: \n expected = %#v, \n actual = %#v", context.Canceled, actualOutput)
+	}
+	if step.invocationCount != 1 {
+		t.Errorf("The step was invoked after the context cancellation: \n expected = %#v, \n actual = %#v", 1, step.invocationCount)
+	}
+}
+
+func TestPipeExecuteBehaviourOnLogging(t *testing.T) {
+	anyErr := errors.New("any-err")
+	input := []PipeStepConfig[any]{
+		{Step: newPipeStepSuccessful[any]("step 1")},
+		{Step: newPipeStepFailedNonRetryable[any]("step 2", anyErr)},
+	}
+	log := &loggerMock{}
+	// the time doesn't move, so the steps take 0ms.
+	clock := workflowtest.NewFakeClock(time.Time{})
+
+	NewPipe("some-workflow", input, WithPipeLogger(log), WithPipeClock(clock)).Execute(context.TODO(), nil)
+
+	expectedInfo := []string{
+		"[START] executing workflow: some-workflow",
+		succeed + " executing step: step 1 in 0ms",
+		"[DONE] executing workflow: some-workflow",
+	}
+	if !reflect.DeepEqual(log.info, expectedInfo) {
+		t.Errorf("The info logs are not as expected: \n expected = %#v, \n actual = %#v", expectedInfo, log.info)
+	}
+	expectedError := []string{failed + " executing step: step 2 in 0ms, err: any-err"}
+	if !reflect.DeepEqual(log.error, expectedError) {
+		t.Errorf("The error logs are not as expected: \n expected = %#v, \n actual = %#v", expectedError, log.error)
+	}
+}
+
+func TestPipeExecuteBehaviourOnFlushingLogger(t *testing.T) {
+	log := &flushingLoggerMock{}
+	input := []PipeStepConfig[string]{{Step: newPipeStepAppend("step 1", "-a")}}
+
+	NewPipe("some-workflow", input, WithPipeLogger(log)).Execute(context.TODO(), "x")
+
+	// the log is flushed once, after the end of the execution is logged.
+	if len(log.flushed) != 1 {
+		t.Fatalf("The number of flushes is not as expected: \n expected = %#v, \n actual = %#v", 1, len(log.flushed))
+	}
+	flushed := log.flushed[0]
+	if expectedOutput := "[DONE] executing workflow: some-workflow"; flushed[len(flushed)-1] != expectedOutput {
+		t.Errorf("The last message logged before the flush is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, flushed)
+	}
+}
+
+func TestPipeExecuteBehaviourOnShouldRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		shouldRun      func(ctx context.Context, req string) bool
+		expectedOutput string
+	}{
+		{
+			name:           "a step without a predicate should run",
+			shouldRun:      nil,
+			expectedOutput: "x-a-b-c",
+		},
+		{
+			name:           "a step with a predicate returning true should run",
+			shouldRun:      func(ctx context.Context, req string) bool { return req == "x-a" },
+			expectedOutput: "x-a-b-c",
+		},
+		{
+			name:           "a step with a predicate returning false should be skipped, and pass the value through",
+			shouldRun:      func(ctx context.Context, req string) bool { return req != "x-a" },
+			expectedOutput: "x-a-c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := []PipeStepConfig[string]{
+				{Step: newPipeStepAppend("step 1", "-a")},
+				{Step: newPipeStepAppend("step 2", "-b"), ShouldRun: tt.shouldRun},
+				{Step: newPipeStepAppend("step 3", "-c")},
+			}
+
+			actualOutput, err := NewPipe("some-workflow", input, nil).Execute(context.TODO(), "x")
+
+			if err != nil {
+				t.Errorf("The workflow returned an unexpected error: %v", err)
+			}
+			if actualOutput != tt.expectedOutput {
+				t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnEnabled(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        func(ctx context.Context) bool
+		expectedOutput string
+	}{
+		{
+			name:           "a step without an Enabled func should run",
+			enabled:        nil,
+			expectedOutput: "x-a-b-c",
+		},
+		{
+			name:           "an enabled step should run",
+			enabled:        func(ctx context.Context) bool { return true },
+			expectedOutput: "x-a-b-c",
+		},
+		{
+			name:           "a disabled step should be skipped, and pass the value through",
+			enabled:        func(ctx context.Context) bool { return false },
+			expectedOutput: "x-a-c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &loggerMock{}
+			input := []PipeStepConfig[string]{
+				{Step: newPipeStepAppend("step 1", "-a")},
+				{Step: newPipeStepAppend("step 2", "-b"), Enabled: tt.enabled},
+				{Step: newPipeStepAppend("step 3", "-c")},
+			}
+
+			actualOutput, err := NewPipe("some-workflow", input, WithPipeLogger(log)).Execute(context.TODO(), "x")
+
+			if err != nil {
+				t.Errorf("The workflow returned an unexpected error: %v", err)
+			}
+			if actualOutput != tt.expectedOutput {
+				t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, actualOutput)
+			}
+			if disabled := contains(log.info, "skipping step: step 2, disabled"); disabled != (tt.expectedOutput == "x-a-c") {
+				t.Errorf("The disabled step logging is not as expected: \n actual = %#v", log.info)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnValidate(t *testing.T) {
+	errMalformed := errors.New("malformed input")
+	rejectAll := func(ctx context.Context, req string) error { return errMalformed }
+	tests := []struct {
+		name            string
+		input           PipeStepConfig[string]
+		expectedOutput  string
+		expectedErr     error
+		expectedInvoked int
+	}{
+		{
+			name:            "a step without a Validate func should run",
+			input:           PipeStepConfig[string]{},
+			expectedOutput:  "x-a-b-c",
+			expectedInvoked: 1,
+		},
+		{
+			name:            "a step with a valid input should run",
+			input:           PipeStepConfig[string]{Validate: func(ctx context.Context, req string) error { return nil }},
+			expectedOutput:  "x-a-b-c",
+			expectedInvoked: 1,
+		},
+		{
+			name:           "a step with an invalid input should not run, and fail the workflow with the validation error",
+			input:          PipeStepConfig[string]{Validate: rejectAll},
+			expectedOutput: "",
+			expectedErr:    errMalformed,
+		},
+		{
+			name:           "a step with an invalid input should not run, even if retried",
+			input:          PipeStepConfig[string]{Validate: rejectAll, RetryConfigProvider: defaultRetryConfigProviderTest, RetryIf: retryAlways},
+			expectedOutput: "",
+			expectedErr:    errMalformed,
+		},
+		{
+			name:           "a step with an invalid input, continuing on error, should pass the value through",
+			input:          PipeStepConfig[string]{Validate: rejectAll, ContinueOnError: true},
+			expectedOutput: "x-a-c",
+			expectedErr:    errMalformed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := newPipeStepAppend("step 2", "-b")
+			stepCfg := tt.input
+			stepCfg.Step = step
+			input := []PipeStepConfig[string]{
+				{Step: newPipeStepAppend("step 1", "-a")},
+				stepCfg,
+				{Step: newPipeStepAppend("step 3", "-c")},
+			}
+
+			actualOutput, err := NewPipe("some-workflow", input, nil).Execute(context.TODO(), "x")
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedErr, err)
+			}
+			if actualOutput != tt.expectedOutput {
+				t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, actualOutput)
+			}
+			if step.invocationCount != tt.expectedInvoked {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedInvoked, step.invocationCount)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnStepFilter(t *testing.T) {
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: newPipeStepAppend("step 2", "-b")},
+		{Step: newPipeStepAppend("step 3", "-c")},
+	}
+	filter := func(stepName string) bool { return stepName != "step 2" }
+
+	// the filtered out step passes the value through.
+	actualOutput, err := NewPipe("some-workflow", input, WithPipeStepFilter(filter)).Execute(context.TODO(), "x")
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	if actualOutput != "x-a-c" {
+		t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", "x-a-c", actualOutput)
+	}
+}
+
+func TestPipeExecuteBehaviourOnRetryBudget(t *testing.T) {
+	step := newPipeStepFailedRetryable[any]("step 1", errors.New("any-err"))
+	input := []PipeStepConfig[any]{
+		{
+			Step:                step,
+			RetryConfigProvider: func() (uint, time.Duration) { return 1000, time.Millisecond },
+			RetryBudget:         5 * time.Millisecond,
+		},
+	}
+
+	clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+
+	NewPipe("some-workflow", input, WithPipeClock(clock)).Execute(context.TODO(), nil)
+
+	// the budget allows 5 retries of 1ms, plus the first attempt.
+	if step.invocationCount != 6 {
+		t.Errorf("The retries did not stop on the exhausted budget: \n expected = %#v, \n actual = %#v", 6, step.invocationCount)
+	}
+}
+
+func TestPipeExecuteBehaviourOnStepError(t *testing.T) {
+	anyErr := errors.New("any-err")
+	input := []PipeStepConfig[any]{
+		{Step: newPipeStepSuccessful[any]("step 1")},
+		{Step: newPipeStepFailedRetryable[any]("step 2", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+
+	_, err := NewPipe("some-workflow", input).Execute(context.TODO(), nil)
+
+	var stepErr StepError
+	expectedOutput := StepError{StepName: "step 2", Attempts: 3, Err: anyErr}
+	if !errors.As(err, &stepErr) || stepErr != expectedOutput {
+		t.Errorf("The StepError is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, err)
+	}
+}
+
+func TestPipeExecuteBehaviourOnContinueOnError(t *testing.T) {
+	anyErr := errors.New("any-err")
+	otherErr := errors.New("other-err")
+	step2 := newPipeStepAppend("step 2", "-b")
+	step2.err = anyErr
+	step3 := newPipeStepAppend("step 3", "-c")
+	step4 := newPipeStepAppend("step 4", "-d")
+	step4.err = otherErr
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: step2, ContinueOnError: true},
+		{Step: step3},
+		{Step: step4, ContinueOnError: true},
+	}
+
+	out, err := NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+
+	if step3.lastInput != "x-a" {
+		t.Errorf("The step after the failing step did not receive its input: \n expected = %#v, \n actual = %#v", "x-a", step3.lastInput)
+	}
+	if out != "x-a-c" {
+		t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", "x-a-c", out)
+	}
+	if !errors.Is(err, anyErr) || !errors.Is(err, otherErr) {
+		t.Errorf("The workflow error does not wrap the failing steps errors: \n expected = %#v, %#v, \n actual = %#v", anyErr, otherErr, err)
+	}
+}
+
+func TestPipeExecuteConcurrently(t *testing.T) {
+	log := &syncLoggerMock{}
+	// the steps hold no state, so they are safe for concurrent use.
+	input := []PipeStepConfig[int]{
+		{Step: chainStepMock[int, int]{name: "double", fn: func(req int) (int, error) { return req * 2, nil }}},
+		{Step: chainStepMock[int, int]{name: "increment", fn: func(req int) (int, error) { return req + 1, nil }}},
+	}
+	p := NewPipe("some-workflow", input, WithPipeLogger(log), WithPipeClock(workflowtest.NewFakeClock(time.Time{})))
+
+	const executions = 100
+	outputs := make([]int, executions)
+	var wg sync.WaitGroup
+	for i := 0; i < executions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outputs[i], _ = p.Execute(context.TODO(), i)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, out := range outputs {
+		if out != i*2+1 {
+			t.Errorf("The output of a concurrent execution is not as expected: \n expected = %#v, \n actual = %#v", i*2+1, out)
+		}
+	}
+	expectedOutput := map[string]bool{
+		"[START] executing workflow: some-workflow":   true,
+		succeed + " executing step: double in 0ms":    true,
+		succeed + " executing step: increment in 0ms": true,
+		"[DONE] executing workflow: some-workflow":    true,
+	}
+	for _, msg := range log.msgs {
+		if !expectedOutput[msg] {
+			t.Errorf("A log message was changed by a concurrent execution: \n expected one of = %#v, \n actual = %#v", expectedOutput, msg)
+		}
+	}
+}
+
+func TestPipeExecuteBehaviourOnMaxAttemptsBoundary(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          uint // the max attempts provided by the RetryConfigProvider
+		expectedOutput int  // the step invocations
+	}{
+		{name: "the max attempts equal to the cap should be used as they are", input: MaxRetryAttempts, expectedOutput: int(MaxRetryAttempts) + 1},
+		{name: "the max uint max attempts should be capped", input: math.MaxUint, expectedOutput: int(MaxRetryAttempts) + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := newPipeStepFailedRetryable[any]("step 1", errors.New("any-err"))
+			input := []PipeStepConfig[any]{
+				{Step: step, RetryConfigProvider: func() (uint, time.Duration) { return tt.input, 0 }},
+			}
+
+			NewPipe("some-workflow", input).Execute(context.TODO(), nil)
+
+			if step.invocationCount != tt.expectedOutput {
+				t.Errorf("The step invocations are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, step.invocationCount)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnRetryIf(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var invocationCount int
+	// the step doesn't implement RetryDecider, and succeeds at the third attempt.
+	step := newPipeStepFunc("step 1", func(req string) (string, error) {
+		invocationCount++
+		if invocationCount < 3 {
+			return req, anyErr
+		}
+		return req + "-a", nil
+	})
+	input := []PipeStepConfig[string]{{
+		Step:                step,
+		RetryConfigProvider: defaultRetryConfigProviderTest,
+		RetryIf:             func(ctx context.Context, err error, attempt uint) bool { return errors.Is(err, anyErr) },
+	}}
+
+	out, err := NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+
+	if err != nil || out != "x-a" {
+		t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x-a", nil, out, err)
+	}
+	if invocationCount != 3 {
+		t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", 3, invocationCount)
+	}
+}
+
+func TestPipeExecuteBehaviourOnErrorWrapping(t *testing.T) {
+	anyErr := errors.New("any-err")
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepFailedNonRetryable[string]("step 1", anyErr)},
+	}
+
+	_, err := NewPipe("some-workflow", input, WithPipeErrorWrapping()).Execute(context.TODO(), "x")
+
+	var wfErr WorkflowError
+	if !errors.As(err, &wfErr) || wfErr.WorkflowName != "some-workflow" || !errors.Is(err, anyErr) {
+		t.Errorf("The workflow error is not as expected: \n actual = %#v", err)
+	}
+}
+
+func TestPipeExecuteBehaviourOnReturnLastGood(t *testing.T) {
+	anyErr := errors.New("any-err")
+	failingStep := newPipeStepFunc("step 2", func(req string) (string, error) { return "", anyErr })
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: failingStep},
+		{Step: newPipeStepAppend("step 3", "-c")},
+	}
+	tests := []struct {
+		name           string
+		input          []PipeOption
+		expectedOutput string
+	}{
+		{
+			name:           "the output of the failing step should be returned by default",
+			input:          nil,
+			expectedOutput: "",
+		},
+		{
+			name:           "the input of the failing step should be returned with WithPipeReturnLastGood",
+			input:          []PipeOption{WithPipeReturnLastGood()},
+			expectedOutput: "x-a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := NewPipe("some-workflow", input, tt.input...).Execute(context.TODO(), "x")
+
+			if !errors.Is(err, anyErr) || out != tt.expectedOutput {
+				t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", tt.expectedOutput, anyErr, out, err)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnEndHooks(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var completed, failed []error
+	opts := []PipeOption{
+		WithPipeOnComplete(func(ctx context.Context, err error) { completed = append(completed, err) }),
+		WithPipeOnError(func(ctx context.Context, err error) { failed = append(failed, err) }),
+	}
+
+	NewPipe("some-workflow", []PipeStepConfig[string]{{Step: newPipeStepAppend("step 1", "-a")}}, opts...).
+		Execute(context.TODO(), "x")
+	_, err := NewPipe("some-workflow", []PipeStepConfig[string]{{Step: newPipeStepFailedNonRetryable[string]("step 1", anyErr)}}, opts...).
+		Execute(context.TODO(), "x")
+
+	expectedCompleted := []error{nil, err}
+	if !reflect.DeepEqual(completed, expectedCompleted) {
+		t.Errorf("The OnComplete calls are not as expected: \n expected = %#v, \n actual = %#v", expectedCompleted, completed)
+	}
+	if len(failed) != 1 || !errors.Is(failed[0], anyErr) {
+		t.Errorf("The OnError calls are not as expected: \n expected = %#v, \n actual = %#v", []error{err}, failed)
+	}
+}
+
+func TestPipeExecuteBehaviourOnStepLogger(t *testing.T) {
+	workflowLog := &loggerMock{}
+	stepLog := &loggerMock{}
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: newPipeStepAppend("step 2", "-b"), Logger: stepLog},
+	}
+
+	NewPipe("some-workflow", input, WithPipeLogger(workflowLog)).Execute(context.TODO(), "x")
+
+	if !containsSubstr(stepLog.info, "step: step 2") || containsSubstr(stepLog.info, "step 1") {
+		t.Errorf("The step logger messages are not as expected: \n actual = %#v", stepLog.info)
+	}
+	if containsSubstr(workflowLog.info, "step: step 2") || !containsSubstr(workflowLog.info, "step: step 1") {
+		t.Errorf("The workflow logger messages are not as expected: \n actual = %#v", workflowLog.info)
+	}
+
+	// the replay of a previously failed step is logged by the step logger as well.
+	repo := newInMemoryRepo()
+	failure := "any-err"
+	_ = repo.Save(context.TODO(), "step 2", "id-1", StepStatusFailed, &failure)
+	workflowLog, stepLog = &loggerMock{}, &loggerMock{}
+	input[1].Logger = stepLog
+
+	NewPipe("some-workflow", input, WithPipeLogger(workflowLog), WithPipeStorage(repo), WithPipeCorrelationID("id-1")).Execute(context.TODO(), "x")
+
+	expectedOutput := "replaying step: step 2, previously failed with err: any-err"
+	if !contains(stepLog.info, expectedOutput) || contains(workflowLog.info, expectedOutput) {
+		t.Errorf("The replay was not logged by the step logger: \n expected = %#v, \n actual = %#v", expectedOutput, stepLog.info)
+	}
+}
+
+func TestPipeExecuteBehaviourOnRetryHook(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var attempts []uint
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepFailedRetryable[string]("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+	onRetry := func(ctx context.Context, stepName string, attempt uint, err error, nextDelay time.Duration) {
+		if stepName != "step 1" || !errors.Is(err, anyErr) || nextDelay != time.Nanosecond {
+			t.Errorf("The retry hook arguments are not as expected: \n actual = %#v, %#v, %#v", stepName, err, nextDelay)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	NewPipe("some-workflow", input, WithPipeOnRetry(onRetry)).Execute(context.TODO(), "x")
+
+	expectedOutput := []uint{1, 2}
+	if !reflect.DeepEqual(attempts, expectedOutput) {
+		t.Errorf("The retry hook calls are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, attempts)
+	}
+}
+
 // BENCHMARKS
 
 // BenchmarkPipeExecuteHappyFlow performs a benchmark for the scenario in which there is no error in the workflow.
@@ -264,3 +891,35 @@ func newPipeStepFailedRetryableRecoverable[T any](name string, failWith error, s
 func newPipeStepFailedNonRetryable[T any](name string, failWith error) *pipeStepMock[T] {
 	return &pipeStepMock[T]{name: name, execute: executeOutput[T]{error: failWith}, canRetry: false}
 }
+
+// loggerMock records the log messages, by copying them, as the workflow messages must be consumed right away.
+type loggerMock struct {
+	info  []string
+	error []string
+}
+
+func (l *loggerMock) Info(msg string) {
+	l.info = append(l.info, strings.Clone(msg))
+}
+
+func (l *loggerMock) Error(msg string) {
+	l.error = append(l.error, strings.Clone(msg))
+}
+
+// syncLoggerMock is a Logger safe for concurrent use.
+type syncLoggerMock struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *syncLoggerMock) Info(msg string) {
+	// yields while the msg is in use, so that the concurrent executions run in the meantime.
+	runtime.Gosched()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, strings.Clone(msg))
+}
+
+func (l *syncLoggerMock) Error(msg string) {
+	l.Info(msg)
+}
@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"context"
+	"time"
+)
+
+// PlannedStep describes a step which would run, in a workflow execution plan.
+type PlannedStep struct {
+	Name                    string
+	ContinueWorkflowOnError bool
+	// MaxAttempts and AttemptDelay are the values provided by the RetryConfigProvider, the MaxAttempts being capped to
+	// MaxRetryAttempts, or 0 if there is none.
+	MaxAttempts  uint
+	AttemptDelay time.Duration
+	Backoff      BackoffStrategy
+	RetryBudget  time.Duration
+	// Retryable reports whether the step implements the RetryDecider or the ErrorRetryDecider interface, or has a
+	// RetryIf, so whether the retry configuration has any effect.
+	Retryable bool
+}
+
+// Plan returns the steps which would run if the workflow was executed for the req, in the execution order, without
+// executing any of them. It is meant to check the workflow configuration, and documents the effective plan for the req.
+// The steps filtered out by WithStepFilter, disabled by their SequentialStepConfig.Enabled, or whose ShouldRun returns
+// false, are left out, and so are the steps which already succeeded for the correlation ID, if a storage is configured.
+// The storage is only read, and a step whose stored result can't be read is planned.
+// A nil step is planned under its problem, e.g. "the step at index: 1 is nil", as it fails the execution.
+// The plan assumes every step succeeds, as the failures, and so the steps left out by a stopping failure, are only known
+// at execution time.
+func (s *Sequential[T]) Plan(ctx context.Context, req T) []PlannedStep {
+	stepsCfg := s.steps(ctx, req)
+	plan := make([]PlannedStep, 0, len(stepsCfg))
+	for i, stepCfg := range stepsCfg {
+		// the nil step fails the execution, so it's planned, under its problem, without reading its config further.
+		if stepCfg.Step == nil {
+			plan = append(plan, PlannedStep{Name: stepCfg.describedName(i)})
+
+			continue
+		}
+		if s.stepFilter != nil && !s.stepFilter(stepCfg.name()) {
+			continue
+		}
+		if (stepCfg.Enabled != nil && !stepCfg.Enabled(ctx)) || (stepCfg.ShouldRun != nil && !stepCfg.ShouldRun(ctx, req)) {
+			continue
+		}
+		if _, skip, _, _ := s.store.skipStepOrAlias(ctx, stepCfg.storageKey(), stepCfg.Aliases); skip {
+			continue
+		}
+		planned := PlannedStep{
+			Name:                    stepCfg.name(),
+			ContinueWorkflowOnError: stepCfg.ContinueWorkflowOnError,
+			Backoff:                 stepCfg.Backoff,
+			RetryBudget:             stepCfg.RetryBudget,
+			Retryable:               stepCfg.retryable(),
+		}
+		planned.MaxAttempts, planned.AttemptDelay = retryConfig(stepCfg.RetryConfigProvider)
+		plan = append(plan, planned)
+	}
+
+	return plan
+}
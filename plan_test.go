@@ -0,0 +1,96 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSequentialPlan(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	repo.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+	step1 := newStepSuccessful("step 1")
+	step2 := newStepFailedRetryable("step 2", anyErr)
+	step3 := newStepSuccessful("step 3")
+	step4 := newStepSuccessful("step 4")
+	step5 := newStepSuccessful("step 5")
+	input := []SequentialStepConfig[any]{
+		{Step: step1},
+		{Step: step2, RetryConfigProvider: defaultRetryConfigProviderTest, ContinueWorkflowOnError: true},
+		{Step: step3, ShouldRun: func(ctx context.Context, req any) bool { return false }},
+		{Step: step4},
+		{Step: step5, Enabled: func(ctx context.Context) bool { return false }},
+	}
+	c := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1"))
+
+	actualOutput := c.Plan(context.TODO(), nil)
+
+	maxAttempts, attemptDelay := defaultRetryConfigProviderTest()
+	expectedOutput := []PlannedStep{
+		{Name: "step 2", ContinueWorkflowOnError: true, MaxAttempts: maxAttempts, AttemptDelay: attemptDelay, Retryable: true},
+		{Name: "step 4", Retryable: true},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The plan is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	for _, step := range []*stepMock{step1, step2, step3, step4, step5} {
+		if step.invocationCount != 0 {
+			t.Errorf("The step: %s was executed by the plan: \n invocations = %#v", step.name, step.invocationCount)
+		}
+	}
+	if repo.len() != 1 {
+		t.Errorf("The storage was changed by the plan: \n records = %#v", repo.len())
+	}
+}
+
+func TestSequentialPlanOfDynamicWorkflow(t *testing.T) {
+	provider := func(ctx context.Context, req any) []SequentialStepConfig[any] {
+		var stepsCfg []SequentialStepConfig[any]
+		for _, region := range req.([]string) {
+			stepsCfg = append(stepsCfg, SequentialStepConfig[any]{Step: newStepSuccessful("region-" + region), RetryBudget: time.Second})
+		}
+		return stepsCfg
+	}
+	c := NewDynamicSequential("some-workflow", provider)
+
+	actualOutput := c.Plan(context.TODO(), []string{"eu", "us"})
+
+	expectedOutput := []PlannedStep{{Name: "region-eu", RetryBudget: time.Second, Retryable: true}, {Name: "region-us", RetryBudget: time.Second, Retryable: true}}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The plan is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestSequentialPlanBehaviourOnNilStep(t *testing.T) {
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: nil, ShouldRun: func(ctx context.Context, req any) bool { return false }},
+	}
+
+	actualOutput := NewSequential("some-workflow", input).Plan(context.TODO(), "some request")
+
+	// the nil step fails the execution whatever its config, so it's planned.
+	expectedOutput := []PlannedStep{{Name: "step 1", Retryable: true}, {Name: "the step at index: 1 is nil"}}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The plan is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestSequentialPlanBehaviourOnStepFilter(t *testing.T) {
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepSuccessful("step 2")},
+		{Step: newStepSuccessful("step 3"), NameOverride: "step 3 renamed"},
+	}
+	filter := func(stepName string) bool { return stepName != "step 1" && stepName != "step 3 renamed" }
+
+	actualOutput := NewSequential("some-workflow", input, WithStepFilter(filter)).Plan(context.TODO(), nil)
+
+	expectedOutput := []PlannedStep{{Name: "step 2", Retryable: true}}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The plan is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
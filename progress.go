@@ -0,0 +1,47 @@
+package workflow
+
+import "context"
+
+// StepEvent describes a step which completed, sent to the progress channel, see WithProgressChannel.
+type StepEvent struct {
+	Name    string
+	Status  StepStatus
+	Attempt uint  // the number of executions, including the retries
+	Err     error // the step error, nil for a succeeded step
+}
+
+// WithProgressChannel sends a StepEvent to the progress channel after every executed step, as soon as it completes,
+// e.g. to drive a progress bar while the workflow runs. The skipped steps send no event.
+// The send blocks until the event is received or the ctx is done, in which case the event is dropped, so no event is
+// lost for a slow consumer, which slows down the workflow instead. A buffered channel, holding an event for every step,
+// spares the workflow the waiting. The channel is never closed by the workflow, so it can be shared by several
+// executions. A nil channel sends nothing, which is the default. The DAG and Parallel workflows send the events of their
+// concurrent steps concurrently, in the completion order rather than the configuration order.
+func WithProgressChannel(progress chan<- StepEvent) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.progress = progress
+	}
+}
+
+// WithPipeProgressChannel is the Pipe version of WithProgressChannel.
+func WithPipeProgressChannel(progress chan<- StepEvent) PipeOption {
+	return func(o *pipeOptions) {
+		o.progress = progress
+	}
+}
+
+// sendProgress sends the StepEvent of the step completed after the attempts, with the err, to the progress channel, if
+// not nil, unless the ctx is done first.
+func sendProgress(ctx context.Context, progress chan<- StepEvent, stepName string, attempts uint, err error) {
+	if progress == nil {
+		return
+	}
+	event := StepEvent{Name: stepName, Status: StepStatusSuccess, Attempt: attempts, Err: err}
+	if err != nil {
+		event.Status = StepStatusFailed
+	}
+	select {
+	case progress <- event:
+	case <-ctx.Done():
+	}
+}
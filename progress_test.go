@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnProgressChannel(t *testing.T) {
+	anyErr := errors.New("any-err")
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepSuccessful("step 2"), ShouldRun: func(ctx context.Context, req any) bool { return false }},
+		{Step: newStepFailedRetryable("step 3", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, ContinueWorkflowOnError: true},
+		{Step: newStepSuccessful("step 4")},
+	}
+	progress := make(chan StepEvent)
+	done := make(chan []StepEvent)
+	go func() {
+		var events []StepEvent
+		for event := range progress {
+			events = append(events, event)
+		}
+		done <- events
+	}()
+
+	err := NewSequential("some-workflow", input, WithProgressChannel(progress)).Execute(context.TODO(), nil)
+	close(progress)
+	actualOutput := <-done
+
+	if !errors.Is(err, anyErr) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+	if len(actualOutput) != 3 || !errors.Is(actualOutput[1].Err, anyErr) {
+		t.Fatalf("The progress events are not as expected: \n actual = %#v", actualOutput)
+	}
+	actualOutput[1].Err = nil
+	// the skipped step sends no event.
+	expectedOutput := []StepEvent{
+		{Name: "step 1", Status: StepStatusSuccess, Attempt: 1},
+		{Name: "step 3", Status: StepStatusFailed, Attempt: 3},
+		{Name: "step 4", Status: StepStatusSuccess, Attempt: 1},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The progress events are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnProgressChannelWithoutConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	step := &dagStepMock{name: "step 1", fn: func(ctx context.Context) error {
+		cancel()
+
+		return nil
+	}}
+	input := []SequentialStepConfig[any]{
+		{Step: step},
+		{Step: newStepSuccessful("step 2")},
+	}
+
+	// the event nobody receives is dropped once the ctx is done, instead of blocking the workflow.
+	err := NewSequential("some-workflow", input, WithProgressChannel(make(chan StepEvent))).Execute(ctx, nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", context.Canceled, err)
+	}
+}
+
+func TestPipeExecuteBehaviourOnProgressChannel(t *testing.T) {
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: newPipeStepFunc("step 2", func(req string) (string, error) { return req + "-b", ErrStopPipe })},
+		{Step: newPipeStepAppend("step 3", "-c")},
+	}
+	progress := make(chan StepEvent, len(input))
+
+	out, err := NewPipe("some-workflow", input, WithPipeProgressChannel(progress)).Execute(context.TODO(), "x")
+	close(progress)
+
+	if err != nil || out != "x-a-b" {
+		t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x-a-b", nil, out, err)
+	}
+	var actualOutput []StepEvent
+	for event := range progress {
+		actualOutput = append(actualOutput, event)
+	}
+	// the step stopping the pipe succeeds, and the steps following it don't run.
+	expectedOutput := []StepEvent{
+		{Name: "step 1", Status: StepStatusSuccess, Attempt: 1},
+		{Name: "step 2", Status: StepStatusSuccess, Attempt: 1},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The progress events are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestDAGExecuteBehaviourOnProgressChannel(t *testing.T) {
+	input := []DAGStepConfig[any]{
+		{Step: newStepSuccessful("a")},
+		{Step: newStepSuccessful("b")},
+		{Step: newStepSuccessful("c"), DependsOn: []string{"a", "b"}},
+	}
+	progress := make(chan StepEvent, len(input))
+	d, err := NewDAG("some-workflow", input, WithProgressChannel(progress))
+	if err != nil {
+		t.Fatalf("The workflow returned an unexpected error: %v", err)
+	}
+
+	err = d.Execute(context.TODO(), nil)
+	close(progress)
+	var actualOutput []StepEvent
+	for event := range progress {
+		actualOutput = append(actualOutput, event)
+	}
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	if len(actualOutput) != 3 || actualOutput[2].Name != "c" {
+		t.Fatalf("The progress events are not as expected: \n actual = %#v", actualOutput)
+	}
+	// the concurrent steps send their events in the completion order.
+	sort.Slice(actualOutput[:2], func(i, j int) bool { return actualOutput[i].Name < actualOutput[j].Name })
+	expectedOutput := []StepEvent{
+		{Name: "a", Status: StepStatusSuccess, Attempt: 1},
+		{Name: "b", Status: StepStatusSuccess, Attempt: 1},
+		{Name: "c", Status: StepStatusSuccess, Attempt: 1},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The progress events are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
@@ -0,0 +1,43 @@
+package workflow
+
+import "context"
+
+// Limiter throttles the steps execution attempts, e.g. to stay under the rate limit of an API called by all the steps.
+// The *rate.Limiter of the golang.org/x/time/rate package implements it.
+type Limiter interface {
+	// Wait blocks until an execution attempt is allowed, or returns an error if the ctx is done before, or if the
+	// attempt can never be allowed in time.
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiter sets the Limiter consulted before every step execution attempt, including the retries, once the
+// retry delay, if any, elapsed. A Wait error, e.g. the ctx error, fails the step without executing it, and the step is
+// not retried, so the workflow stops, unless the step is configured to continue the workflow on error.
+// The steps skipped, because they already succeeded for the correlation ID or in the execution, don't consult it.
+// The DAG and Parallel workflows consult it concurrently, so it must be safe for concurrent use, as a *rate.Limiter is.
+func WithRateLimiter(limiter Limiter) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.limiter = limiter
+	}
+}
+
+// WithPipeRateLimiter is the Pipe version of WithRateLimiter.
+func WithPipeRateLimiter(limiter Limiter) PipeOption {
+	return func(o *pipeOptions) {
+		o.limiter = limiter
+	}
+}
+
+// waitLimiter waits for the limiter to allow the next execution attempt of the step, and logs the Wait error, if any.
+// A nil limiter allows every attempt.
+func waitLimiter(ctx context.Context, log logger, limiter Limiter, stepName string, attempt uint) error {
+	if limiter == nil {
+		return nil
+	}
+	err := limiter.Wait(ctx)
+	if err != nil {
+		log.printStep(ctx, LevelError, concatStr("step: ", stepName, " is not executed, the rate limiter failed: ", err.Error()), stepName, attempt)
+	}
+
+	return err
+}
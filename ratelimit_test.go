@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnRateLimiter(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name                    string
+		input                   *limiterMock
+		expectedErr             error
+		expectedWaits           int
+		expectedInvocationCount int
+	}{
+		{
+			name:                    "the limiter should be consulted once per execution attempt",
+			input:                   &limiterMock{},
+			expectedErr:             anyErr,
+			expectedWaits:           4, // step 1 succeeds, and step 2 runs 3 attempts
+			expectedInvocationCount: 3,
+		},
+		{
+			name:                    "a limiter error should stop the workflow without executing the step",
+			input:                   &limiterMock{err: context.Canceled},
+			expectedErr:             context.Canceled,
+			expectedWaits:           1,
+			expectedInvocationCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step1 := &dagStepMock{name: "step 1", fn: func(ctx context.Context) error { return nil }}
+			step2 := newStepFailedRetryable("step 2", anyErr)
+			input := []SequentialStepConfig[any]{
+				{Step: step1},
+				{Step: step2, RetryConfigProvider: defaultRetryConfigProviderTest},
+			}
+
+			err := NewSequential("some-workflow", input, WithRateLimiter(tt.input)).Execute(context.TODO(), nil)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedErr, err)
+			}
+			if tt.input.waits != tt.expectedWaits {
+				t.Errorf("The limiter waits are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedWaits, tt.input.waits)
+			}
+			if step2.invocationCount != tt.expectedInvocationCount {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedInvocationCount, step2.invocationCount)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnRateLimiter(t *testing.T) {
+	anyErr := errors.New("any-err")
+	limiter := &limiterMock{}
+	input := []PipeStepConfig[any]{
+		{Step: newPipeStepFailedRetryable[any]("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+
+	NewPipe("some-workflow", input, WithPipeRateLimiter(limiter)).Execute(context.TODO(), nil)
+
+	if limiter.waits != 3 {
+		t.Errorf("The limiter waits are not as expected: \n expected = %#v, \n actual = %#v", 3, limiter.waits)
+	}
+}
+
+// MOCKS/STUBS
+
+// limiterMock counts the Wait calls, and fails them with err, if not nil.
+type limiterMock struct {
+	waits int
+	err   error
+}
+
+func (l *limiterMock) Wait(_ context.Context) error {
+	l.waits++
+
+	return l.err
+}
@@ -0,0 +1,30 @@
+package redisstore_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/silviutanasa/workflow"
+	"github.com/silviutanasa/workflow/redisstore"
+)
+
+func ExampleNew() {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	// the replay state of a correlation ID expires a day after its last save, if the workflow doesn't succeed before.
+	storage := redisstore.New(client, redisstore.WithTTL(24*time.Hour))
+
+	// in real life usage, these must be concrete types implementing the workflow.SequentialStep interface.
+	var step1, step2 workflow.SequentialStep[any]
+	wf := workflow.NewSequential(
+		"example",
+		[]workflow.SequentialStepConfig[any]{{Step: step1}, {Step: step2}},
+		workflow.WithStorage(storage),
+		workflow.WithCorrelationID("order-42"),
+	)
+	// a failed execution can be replayed by any instance sharing the Redis, with the same correlation ID.
+	if err := wf.Execute(context.Background(), nil); err != nil {
+		return
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/silviutanasa/workflow/redisstore
+
+go 1.20
+
+require (
+	github.com/alicebob/miniredis/v2 v2.39.0
+	github.com/redis/go-redis/v9 v9.7.3
+	github.com/silviutanasa/workflow v0.0.0-20261015045913-03165fc8d034
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+)
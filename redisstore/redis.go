@@ -0,0 +1,159 @@
+// Package redisstore provides a workflow.Storage backed by Redis, allowing the workflows replay state to be shared
+// across the instances of a horizontally scaled service.
+// It lives in its own module, so the workflow package doesn't depend on the Redis client.
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/silviutanasa/workflow"
+)
+
+// the hash fields suffixes, appended to the step name.
+const (
+	statusField  = ":status"
+	outputField  = ":output"
+	savedAtField = ":saved_at"
+	valueField   = ":value"
+)
+
+// Storage is a workflow.ResultStorage keeping the steps execution results in Redis.
+// The results of a correlation ID are stored in a single hash, whose fields are prefixed by the step names.
+type Storage struct {
+	client redis.Cmdable
+	prefix string
+	ttl    time.Duration
+}
+
+// Option configures a Storage.
+type Option func(*Storage)
+
+// WithKeyPrefix sets the prefix of the hash keys, which are the correlation IDs. The default prefix is "workflow:".
+func WithKeyPrefix(prefix string) Option {
+	return func(s *Storage) {
+		s.prefix = prefix
+	}
+}
+
+// WithTTL sets the expiration of the hash holding the results of a correlation ID, refreshed on every save.
+// A zero TTL means the hash never expires, and is removed only when the workflow succeeds.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Storage) {
+		s.ttl = ttl
+	}
+}
+
+// New is the Storage constructor.
+func New(client redis.Cmdable, opts ...Option) *Storage {
+	s := Storage{
+		client: client,
+		prefix: "workflow:",
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return &s
+}
+
+// Save implements the workflow.Storage interface.
+func (s *Storage) Save(ctx context.Context, stepName, correlationID string, status workflow.StepStatus, output *string) error {
+	k := s.key(correlationID)
+	_, err := s.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, k,
+			stepName+statusField, string(status),
+			stepName+savedAtField, strconv.FormatInt(time.Now().UnixNano(), 10),
+		)
+		if output != nil {
+			p.HSet(ctx, k, stepName+outputField, *output)
+		} else {
+			p.HDel(ctx, k, stepName+outputField)
+		}
+		s.expire(ctx, p, k)
+
+		return nil
+	})
+
+	return err
+}
+
+// Get implements the workflow.Storage interface.
+// It returns an empty workflow.StepStatus and a nil error, if there is no stored record.
+func (s *Storage) Get(ctx context.Context, stepName, correlationID string) (workflow.StepStatus, error) {
+	status, err := s.client.HGet(ctx, s.key(correlationID), stepName+statusField).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+
+	return workflow.StepStatus(status), err
+}
+
+// GetResult implements the workflow.ResultStorage interface.
+// It returns a workflow.StepResult with an empty workflow.StepStatus and a nil error, if there is no stored record.
+func (s *Storage) GetResult(ctx context.Context, stepName, correlationID string) (workflow.StepResult, error) {
+	vals, err := s.client.HMGet(ctx, s.key(correlationID), stepName+statusField, stepName+outputField, stepName+savedAtField).Result()
+	if err != nil {
+		return workflow.StepResult{}, err
+	}
+	var res workflow.StepResult
+	if status, ok := vals[0].(string); ok {
+		res.Status = workflow.StepStatus(status)
+	}
+	if output, ok := vals[1].(string); ok {
+		res.Output = &output
+	}
+	if savedAt, ok := vals[2].(string); ok {
+		nanos, err := strconv.ParseInt(savedAt, 10, 64)
+		if err != nil {
+			return workflow.StepResult{}, err
+		}
+		res.SavedAt = time.Unix(0, nanos)
+	}
+
+	return res, nil
+}
+
+// Clear implements the workflow.Storage interface. Deleting a missing key is not an error, so it's idempotent.
+func (s *Storage) Clear(ctx context.Context, correlationID string) error {
+	return s.client.Del(ctx, s.key(correlationID)).Err()
+}
+
+// SaveValue implements the workflow.Storage interface.
+func (s *Storage) SaveValue(ctx context.Context, stepName, correlationID string, value []byte) error {
+	k := s.key(correlationID)
+	_, err := s.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, k, stepName+valueField, value)
+		s.expire(ctx, p, k)
+
+		return nil
+	})
+
+	return err
+}
+
+// GetValue implements the workflow.Storage interface.
+// It returns a nil value and a nil error, if there is no stored value.
+func (s *Storage) GetValue(ctx context.Context, stepName, correlationID string) ([]byte, error) {
+	value, err := s.client.HGet(ctx, s.key(correlationID), stepName+valueField).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	return value, err
+}
+
+// key returns the key of the hash holding the results of the correlation ID.
+func (s *Storage) key(correlationID string) string {
+	return s.prefix + correlationID
+}
+
+// expire queues the refresh of the hash expiration, if a TTL is set.
+func (s *Storage) expire(ctx context.Context, p redis.Pipeliner, k string) {
+	if s.ttl > 0 {
+		p.Expire(ctx, k, s.ttl)
+	}
+}
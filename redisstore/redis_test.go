@@ -0,0 +1,104 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/silviutanasa/workflow"
+)
+
+func TestStorage(t *testing.T) {
+	ctx := context.TODO()
+	s, mr := newTestStorage(t, WithTTL(time.Hour))
+	failure := "any-err"
+
+	if status, err := s.Get(ctx, "step 1", "id-1"); status != "" || err != nil {
+		t.Errorf("The missing record is not reported as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "", nil, status, err)
+	}
+	if err := s.Save(ctx, "step 1", "id-1", workflow.StepStatusFailed, &failure); err != nil {
+		t.Fatalf("The save returned an unexpected error: %v", err)
+	}
+	res, err := s.GetResult(ctx, "step 1", "id-1")
+	if err != nil || res.Status != workflow.StepStatusFailed || res.Output == nil || *res.Output != failure || res.SavedAt.IsZero() {
+		t.Errorf("The stored result is not as expected: \n actual = %#v, %#v", res, err)
+	}
+	// a success overwrites the failure, and drops its output.
+	if err = s.Save(ctx, "step 1", "id-1", workflow.StepStatusSuccess, nil); err != nil {
+		t.Fatalf("The save returned an unexpected error: %v", err)
+	}
+	if res, _ = s.GetResult(ctx, "step 1", "id-1"); res.Status != workflow.StepStatusSuccess || res.Output != nil {
+		t.Errorf("The stored result is not as expected: \n actual = %#v", res)
+	}
+	if err = s.SaveValue(ctx, "step 1", "id-1", []byte(`"x"`)); err != nil {
+		t.Fatalf("The value save returned an unexpected error: %v", err)
+	}
+	if value, _ := s.GetValue(ctx, "step 1", "id-1"); string(value) != `"x"` {
+		t.Errorf("The stored value is not as expected: \n expected = %#v, \n actual = %#v", `"x"`, string(value))
+	}
+	if ttl := mr.TTL("workflow:id-1"); ttl != time.Hour {
+		t.Errorf("The hash TTL is not as expected: \n expected = %#v, \n actual = %#v", time.Hour, ttl)
+	}
+
+	if err = s.Clear(ctx, "id-1"); err != nil {
+		t.Fatalf("The clear returned an unexpected error: %v", err)
+	}
+	if mr.Exists("workflow:id-1") {
+		t.Errorf("The hash was not removed by the clear")
+	}
+	if err = s.Clear(ctx, "id-1"); err != nil {
+		t.Errorf("The clear of a removed hash returned an unexpected error: %v", err)
+	}
+}
+
+func TestStorageWithSequential(t *testing.T) {
+	anyErr := errors.New("any-err")
+	s, mr := newTestStorage(t)
+	var step2Err = anyErr
+	var step1Count int
+	input := []workflow.SequentialStepConfig[any]{
+		{Step: stepFunc{name: "step 1", fn: func() error { step1Count++; return nil }}},
+		{Step: stepFunc{name: "step 2", fn: func() error { return step2Err }}},
+	}
+	wf := workflow.NewSequential("some-workflow", input, workflow.WithStorage(s), workflow.WithCorrelationID("id-1"))
+
+	if err := wf.Execute(context.TODO(), nil); !errors.Is(err, anyErr) {
+		t.Fatalf("The first execution error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+	step2Err = nil
+	if err := wf.Execute(context.TODO(), nil); err != nil {
+		t.Fatalf("The replay returned an unexpected error: %v", err)
+	}
+
+	if step1Count != 1 {
+		t.Errorf("The succeeded step was not skipped on replay: \n expected invocations = %#v, \n actual = %#v", 1, step1Count)
+	}
+	if mr.Exists("workflow:id-1") {
+		t.Errorf("The hash was not removed after the workflow succeeded")
+	}
+}
+
+func newTestStorage(t *testing.T, opts ...Option) (*Storage, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return New(client, opts...), mr
+}
+
+// MOCKS/STUBS
+type stepFunc struct {
+	name string
+	fn   func() error
+}
+
+func (s stepFunc) Name() string {
+	return s.name
+}
+
+func (s stepFunc) Execute(_ context.Context, _ any) error {
+	return s.fn()
+}
@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"context"
+	"time"
+)
+
+// StepReportStatus is the final status of a step, in an ExecutionReport.
+type StepReportStatus string
+
+const (
+	StepReportSucceeded StepReportStatus = "SUCCEEDED"
+	StepReportFailed    StepReportStatus = "FAILED"
+	// StepReportSkipped marks a step skipped because it already succeeded for the correlation ID, or because its ShouldRun
+	// returned false.
+	StepReportSkipped StepReportStatus = "SKIPPED"
+)
+
+// StepReport describes the execution of a step.
+type StepReport struct {
+	Name     string
+	Status   StepReportStatus
+	Attempts uint // the number of executions, including the retries, and 0 for a skipped step
+	Duration time.Duration
+	Err      error
+}
+
+// ExecutionReport describes the execution of a workflow, step by step, following the execution order.
+// The steps not reached, because the workflow stopped earlier, are not part of the report.
+type ExecutionReport struct {
+	Steps    []StepReport
+	Duration time.Duration
+}
+
+// ExecuteWithReport executes the workflow, as described by Execute, and also returns the ExecutionReport.
+func (s *Sequential[T]) ExecuteWithReport(ctx context.Context, req T) (ExecutionReport, error) {
+	ctx = s.execID.context(ctx)
+	report := ExecutionReport{Steps: make([]StepReport, 0, len(s.stepsConfig))}
+	start := now(s.clock)
+	err := wrapWorkflowErr(s.wrapErrs, s.name, s.execute(ctx, req, &report, execution{}))
+	report.Duration = now(s.clock).Sub(start)
+
+	return report, s.end.end(ctx, err)
+}
+
+// add appends a new StepReport, to be filled by the step execution. The returned StepReport stays valid only until the
+// next add, which may move the steps of the report.
+// It is a no op on a nil report, so the workflows executed without a report don't pay for it.
+func (r *ExecutionReport) add(stepName string) *StepReport {
+	if r == nil {
+		return nil
+	}
+	r.Steps = append(r.Steps, StepReport{Name: stepName})
+
+	return &r.Steps[len(r.Steps)-1]
+}
+
+// size returns the number of the steps of the report, 0 for a nil report.
+func (r *ExecutionReport) size() int {
+	if r == nil {
+		return 0
+	}
+
+	return len(r.Steps)
+}
+
+// stepAt returns the report of the step at the index i, or nil for a nil report. Same as the one returned by add, the
+// returned StepReport stays valid only until the next add.
+func (r *ExecutionReport) stepAt(i int) *StepReport {
+	if r == nil {
+		return nil
+	}
+
+	return &r.Steps[i]
+}
+
+// reset drops the steps of the report, so it can describe a new attempt of the workflow.
+func (r *ExecutionReport) reset() {
+	if r != nil {
+		r.Steps = r.Steps[:0]
+	}
+}
+
+// start returns the start time of the step execution, read from the clock, or the zero time on a nil report.
+func (r *StepReport) start(clock Clock) time.Time {
+	if r == nil {
+		return time.Time{}
+	}
+
+	return now(clock)
+}
+
+// attempt counts an execution of the step.
+func (r *StepReport) attempt() {
+	if r != nil {
+		r.Attempts++
+	}
+}
+
+// skip marks the step as skipped.
+func (r *StepReport) skip() {
+	if r != nil {
+		r.Status = StepReportSkipped
+	}
+}
+
+// finish records the outcome of the step execution, started at start, measured by the clock.
+func (r *StepReport) finish(clock Clock, start time.Time, err error) {
+	if r == nil {
+		return
+	}
+	r.Duration = now(clock).Sub(start)
+	r.Err = err
+	r.Status = StepReportSucceeded
+	if err != nil {
+		r.Status = StepReportFailed
+	}
+}
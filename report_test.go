@@ -0,0 +1,75 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/silviutanasa/workflow/workflowtest"
+)
+
+func TestSequentialExecuteWithReport(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	repo.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepFailedRetryableRecoverable("step 2", anyErr, 2), RetryConfigProvider: defaultRetryConfigProviderTest},
+		{Step: newStepFailedNonRetryable("step 3", anyErr), ContinueWorkflowOnError: true},
+		{Step: newStepFailedNonRetryable("step 4", anyErr)},
+		{Step: newStepSuccessful("step 5")},
+	}
+
+	c := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1"))
+	report, err := c.ExecuteWithReport(context.TODO(), nil)
+
+	if !errors.Is(err, anyErr) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+	expectedOutput := []StepReport{
+		{Name: "step 1", Status: StepReportSkipped, Attempts: 0},
+		{Name: "step 2", Status: StepReportSucceeded, Attempts: 2},
+		{Name: "step 3", Status: StepReportFailed, Attempts: 1, Err: anyErr},
+		{Name: "step 4", Status: StepReportFailed, Attempts: 1, Err: anyErr},
+	}
+	if len(report.Steps) != len(expectedOutput) {
+		t.Fatalf("The reported steps count is not as expected: \n expected = %#v, \n actual = %#v", len(expectedOutput), len(report.Steps))
+	}
+	for i, expected := range expectedOutput {
+		actual := report.Steps[i]
+		if actual.Name != expected.Name ||
+			actual.Status != expected.Status ||
+			actual.Attempts != expected.Attempts ||
+			!errors.Is(actual.Err, expected.Err) {
+			t.Errorf("The step report is not as expected: \n expected = %#v, \n actual = %#v", expected, actual)
+		}
+	}
+	if report.Duration <= 0 {
+		t.Errorf("The workflow duration was not reported")
+	}
+}
+
+func TestSequentialExecuteWithReportBehaviourOnClock(t *testing.T) {
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{
+			Step:                newStepFailedRetryableRecoverable("step 2", errors.New("any-err"), 3),
+			RetryConfigProvider: func() (uint, time.Duration) { return 2, time.Second },
+		},
+	}
+	clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+
+	report, err := NewSequential("some-workflow", input, WithClock(clock)).ExecuteWithReport(context.TODO(), nil)
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	// the durations are measured by the clock, so the step 2 lasts as long as its 2 retries delays.
+	actualOutput := []time.Duration{report.Steps[0].Duration, report.Steps[1].Duration, report.Duration}
+	expectedOutput := []time.Duration{0, 2 * time.Second, 2 * time.Second}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The reported durations are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
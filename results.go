@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+)
+
+// SequentialResultStep is a step of a Sequential workflow which also returns a result, e.g. the count of the items it
+// processed, collected by Sequential.ExecuteWithResults, so the side effecting steps can report a value back without
+// turning the workflow into a Pipe. It becomes a SequentialStep by ResultStep.
+type SequentialResultStep[T, R any] interface {
+	Name() string
+	Execute(ctx context.Context, req T) (R, error)
+}
+
+// ResultStep adapts the step to the SequentialStep interface, so it can be a step of a Sequential workflow, its result
+// being collected by Sequential.ExecuteWithResults, and dropped by the other execution methods.
+// The adapted step is retried as the step is, if it implements the ErrorRetryDecider or the RetryDecider interface.
+func ResultStep[T, R any](step SequentialResultStep[T, R]) SequentialStep[T] {
+	return &resultStep[T, R]{step: step}
+}
+
+// resultStep is the SequentialStep built by ResultStep.
+type resultStep[T, R any] struct {
+	step SequentialResultStep[T, R]
+}
+
+// Name implements the SequentialStep interface.
+func (r *resultStep[T, R]) Name() string {
+	return r.step.Name()
+}
+
+// Execute implements the SequentialStep interface, dropping the result.
+func (r *resultStep[T, R]) Execute(ctx context.Context, req T) error {
+	_, err := r.step.Execute(ctx, req)
+
+	return err
+}
+
+// RetryableError implements the ErrorRetryDecider interface, by deferring to the adapted step.
+func (r *resultStep[T, R]) RetryableError(err error) bool {
+	return canRetry(r.step, err)
+}
+
+// executeWithResult executes the step, returning its result.
+func (r *resultStep[T, R]) executeWithResult(ctx context.Context, req T) (any, error) {
+	return r.step.Execute(ctx, req)
+}
+
+// resultExecutor is implemented by the steps built by ResultStep, whatever the type of their result.
+type resultExecutor[T any] interface {
+	executeWithResult(ctx context.Context, req T) (any, error)
+}
+
+// stepResults collects the results of the steps of an execution. It is safe for concurrent use, as the steps may run
+// concurrently.
+type stepResults struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+// set records the result of the step named stepName.
+func (r *stepResults) set(stepName string, value any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[stepName] = value
+}
+
+// ExecuteWithResults executes the workflow, as described by Execute, and also returns the results of the succeeded steps
+// built by ResultStep, by step name, the NameOverride taking precedence. The results have the type returned by their
+// steps, so they are read by a type assertion. The steps skipped, e.g. because they already succeeded for the
+// correlation ID, have no result, and neither do the steps of the nested workflows.
+func (s *Sequential[T]) ExecuteWithResults(ctx context.Context, req T) (map[string]any, error) {
+	ctx = s.execID.context(ctx)
+	results := stepResults{values: make(map[string]any)}
+	err := wrapWorkflowErr(s.wrapErrs, s.name, s.execute(ctx, req, nil, execution{results: &results}))
+
+	return results.values, s.end.end(ctx, err)
+}
+
+// runStep executes the step once, collecting its result in the results, if not nil, for a step built by ResultStep.
+func runStep[T any](ctx context.Context, stepCfg SequentialStepConfig[T], req T, results *stepResults) error {
+	rs, ok := stepCfg.Step.(resultExecutor[T])
+	if !ok || results == nil {
+		return stepCfg.Step.Execute(ctx, req)
+	}
+	res, err := rs.executeWithResult(ctx, req)
+	if err == nil {
+		results.set(stepCfg.name(), res)
+	}
+
+	return err
+}
@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSequentialExecuteWithResults(t *testing.T) {
+	anyErr := errors.New("any-err")
+	input := []SequentialStepConfig[any]{
+		{Step: ResultStep[any, int](&resultStepMock[int]{name: "count-items", result: 3})},
+		{Step: ResultStep[any, string](&resultStepMock[string]{name: "send-email", result: "msg-1"}), NameOverride: "send-welcome-email"},
+		{Step: newStepSuccessful("no-result")},
+		{Step: ResultStep[any, int](&resultStepMock[int]{name: "failing", result: 1, err: anyErr}), ContinueWorkflowOnError: true},
+	}
+
+	actualOutput, err := NewSequential("some-workflow", input).ExecuteWithResults(context.TODO(), nil)
+
+	expectedOutput := map[string]any{"count-items": 3, "send-welcome-email": "msg-1"}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps results are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	if !errors.Is(err, anyErr) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+}
+
+func TestResultStepBehaviourOnExecute(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step := &resultStepMock[int]{name: "count-items", result: 3, err: anyErr, retryable: true}
+	input := []SequentialStepConfig[any]{
+		{Step: ResultStep[any, int](step), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+
+	err := NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+	// the adapted step is retried as the step is.
+	if !errors.Is(err, anyErr) || step.invocationCount != 3 {
+		t.Errorf("The workflow result is not as expected: \n actual = %#v, %#v", err, step.invocationCount)
+	}
+}
+
+// MOCKS/STUBS
+
+// resultStepMock is a SequentialResultStep returning the result and the err.
+type resultStepMock[R any] struct {
+	name            string
+	result          R
+	err             error
+	retryable       bool
+	invocationCount int
+}
+
+func (s *resultStepMock[R]) Name() string {
+	return s.name
+}
+
+func (s *resultStepMock[R]) Execute(_ context.Context, _ any) (R, error) {
+	s.invocationCount++
+
+	return s.result, s.err
+}
+
+func (s *resultStepMock[R]) CanRetry() bool {
+	return s.retryable
+}
@@ -3,10 +3,16 @@ package workflow
 import (
 	"context"
 	"errors"
+	"sort"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
+// ErrCorrelationIDRequired is returned by the workflows configured with WithWorkflowRetry, when executed without a
+// storage and a correlation ID.
+var ErrCorrelationIDRequired = errors.New("the workflow retry requires a storage and a correlation ID")
+
 // SequentialStep describes a step of execution.
 type SequentialStep[T any] interface {
 	// Name provides the identity of the step.
@@ -20,120 +26,997 @@ type SequentialStep[T any] interface {
 type SequentialStepConfig[T any] struct {
 	Step                    SequentialStep[T]
 	ContinueWorkflowOnError bool // decides if the workflow stops on Step errors
-	// define this only if the Step implements RetryDecider, otherwise it has no effect and no sense!
+	// Checkpoint makes the workflow executed by ExecuteUntilCheckpoint or Resume pause once the Step succeeds, to be
+	// resumed from the next step by Resume.
+	Checkpoint bool
+	// define this only if the Step implements RetryDecider or ErrorRetryDecider, or if RetryIf is set, otherwise it has no
+	// effect and no sense!
 	RetryConfigProvider func() (maxAttempts uint, attemptDelay time.Duration) // provides the retry configuration
+	// RetryIf decides if the Step runs again, after the attempt numbered attempt(0 for the first execution) failed with
+	// the err, instead of the RetryDecider or ErrorRetryDecider implemented by the Step, so any Step can be retried,
+	// without changing its type. It is called after every failed attempt, the last one included, so a failure it refuses
+	// to retry is stored as permanently failed, but the Step runs again only while the attempts provided by the
+	// RetryConfigProvider are not spent, so a nil RetryConfigProvider means no retry, even if RetryIf is set. A recovered
+	// panic is never retried.
+	RetryIf func(ctx context.Context, err error, attempt uint) bool
+	// Backoff computes the delay before every retry attempt, and takes precedence over the attemptDelay provided by the
+	// RetryConfigProvider. The maximum number of attempts is still provided by the RetryConfigProvider.
+	Backoff BackoffStrategy
+	// RetryDelay computes the delay before every retry attempt, starting from 1 for the first retry, from the lastErr
+	// returned by the previous attempt, e.g. from the Retry-After of a rate limited API, and takes precedence over the
+	// Backoff and the attemptDelay provided by the RetryConfigProvider. The maximum number of attempts is still provided
+	// by the RetryConfigProvider.
+	RetryDelay func(attempt uint, lastErr error) time.Duration
+	// RetryBudget bounds the total time spent on retrying the Step, measured from the first attempt. A retry whose delay
+	// would exceed the RetryBudget is not attempted, even if the maximum number of attempts is not reached.
+	// A zero RetryBudget means no time limit.
+	RetryBudget time.Duration
+	// Timeout bounds every execution attempt of the Step, by the deadline of the ctx passed to it. A zero Timeout means
+	// the default timeout set by WithDefaultStepTimeout, if any.
+	Timeout time.Duration
+	// ShouldRun decides, at execution time, if the Step runs for the req. A nil ShouldRun means the Step always runs.
+	ShouldRun func(ctx context.Context, req T) bool
+	// Enabled decides, at execution time, if the Step is enabled, whatever the req, e.g. by reading a feature flag, so a
+	// feature turned off is told apart from a Step not required for the req, decided by the ShouldRun. A disabled Step is
+	// skipped, and logged as disabled, before the ShouldRun is called. A nil Enabled means the Step is always enabled.
+	Enabled func(ctx context.Context) bool
+	// IdempotencyKey identifies the Step execution result in the storage, instead of the Step name, if not empty.
+	// It allows the steps sharing a name to be replayed correctly, see the Storage documentation.
+	IdempotencyKey string
+	// Aliases holds the previous storage keys of the Step, e.g. its names before a rename, so it is still skipped if it
+	// already succeeded for the correlation ID under one of them. Its current key, the IdempotencyKey if set, otherwise
+	// the name, is checked first, and then the Aliases, in order. The results are saved under the current key only.
+	Aliases []string
+	// NameOverride replaces the Step name, if not empty, in the logs, the storage keys, the errors, the hooks, the metrics
+	// and the reports, e.g. to tell apart the uses of a workflow nested in several parent workflows.
+	NameOverride string
+	// CircuitBreaker stops executing the Step, failing it with ErrCircuitOpen, once it failed too many consecutive times,
+	// if not nil. The Step is not retried while its circuit is open. See CircuitBreaker.
+	CircuitBreaker *CircuitBreaker
+	// RequestFunc adapts the req passed to the Step, e.g. to pass it a sub field, if not nil, so a Step can be reused with a
+	// narrow input, while the workflow owns the whole req. It's called once, before the Step runs, the same adapted req
+	// being passed to all the execution attempts, and to Compensate. The ShouldRun and the following steps still receive
+	// the original req. A nil RequestFunc passes the req unchanged.
+	RequestFunc func(ctx context.Context, req T) T
+	// Logger replaces the Logger of the workflow for the log lines of the Step, if not nil, e.g. to route the output of a
+	// verbose Step to a separate sink, or to log it at another level. The workflow log lines are not affected.
+	Logger Logger
+}
+
+// request returns the req passed to the Step, adapted by the RequestFunc, if any.
+func (c SequentialStepConfig[T]) request(ctx context.Context, req T) T {
+	if c.RequestFunc == nil {
+		return req
+	}
+
+	return c.RequestFunc(ctx, req)
+}
+
+// name returns the name identifying the Step in the workflow.
+func (c SequentialStepConfig[T]) name() string {
+	if c.NameOverride != "" {
+		return c.NameOverride
+	}
+
+	return c.Step.Name()
+}
+
+// retryable reports whether the Step can be retried, by implementing the RetryDecider or the ErrorRetryDecider
+// interface, or by having a RetryIf.
+func (c SequentialStepConfig[T]) retryable() bool {
+	return c.RetryIf != nil || isRetryable(c.Step)
+}
+
+// canRetry reports whether the Step can run again, after the attempt failed with the err, as decided by the RetryIf,
+// if set, otherwise by the Step.
+func (c SequentialStepConfig[T]) canRetry(ctx context.Context, err error, attempt uint) bool {
+	if c.RetryIf != nil && !isPanic(err) {
+		return c.RetryIf(ctx, err, attempt)
+	}
+
+	return canRetry(c.Step, err)
+}
+
+// storageKey returns the key identifying the Step execution result in the storage.
+func (c SequentialStepConfig[T]) storageKey() string {
+	if c.IdempotencyKey != "" {
+		return c.IdempotencyKey
+	}
+
+	return c.name()
+}
+
+// SequentialOption configures a Sequential workflow.
+type SequentialOption func(*sequentialOptions)
+
+// sequentialOptions holds the optional configuration of a Sequential workflow.
+type sequentialOptions struct {
+	log           logger // the internal logger is a no op if no Logger is provided
+	beforeStep    func(ctx context.Context, stepName string)
+	afterStep     func(ctx context.Context, stepName string, err error, attempt int)
+	onRetry       func(ctx context.Context, stepName string, attempt uint, err error, nextDelay time.Duration)
+	onTimeout     func(ctx context.Context, stepName string, attempt uint)
+	onSkip        func(ctx context.Context, stepName string)
+	end           endHooks
+	execID        executionID
+	stepFilter    func(stepName string) bool // runs only the steps it returns true for, if not nil
+	store         stepsStore
+	compensation  bool // compensates the succeeded steps, when a step failure stops the workflow
+	metrics       stepMetrics
+	retry         workflowRetry
+	clock         Clock // the system time is used if nil
+	recoverPanics bool  // converts the steps panics to errors
+	onPanic       func(ctx context.Context, stepName string, recovered any)
+	// defaultStepTimeout bounds the execution attempts of the steps without a timeout of their own, if greater than 0
+	defaultStepTimeout time.Duration
+	// globalRetryBudget bounds the retries of all the steps of an execution, if hasGlobalRetryBudget is true
+	globalRetryBudget    uint
+	hasGlobalRetryBudget bool
+	// strictValidation makes the constructor panic if the configuration is not valid
+	strictValidation bool
+	// failFast makes a Parallel workflow cancel its running steps on the first failure
+	failFast bool
+	// maxConcurrency bounds the number of the steps of a Parallel workflow running at the same time, if greater than 0
+	maxConcurrency uint
+	stepNames      bool // passes the workflow and the step names to the steps, in their ctx
+	wrapErrs       bool // wraps the returned errors in a WorkflowError
+	// enrichContext decorates the ctx passed to every step, if not nil
+	enrichContext func(ctx context.Context, stepName string) context.Context
+	// minRemainingTime stops the workflow before a step with less time left until the ctx deadline, if greater than 0
+	minRemainingTime time.Duration
+	// retryBackoffCap bounds the delay before every retry attempt, if greater than 0
+	retryBackoffCap time.Duration
+	maxSteps        int             // fails the executions with more steps, if greater than 0
+	limiter         Limiter         // throttles the steps execution attempts, if not nil
+	stop            <-chan struct{} // stops the workflow before the next step, once closed
+	// maxConsecutiveFailures stops retrying a step failing that many times with the same error, if greater than 0
+	maxConsecutiveFailures uint
+	progress               chan<- StepEvent // receives the event of every executed step
+	// stepConcurrency bounds the steps of a cluster of continue on error steps running at the same time, if greater than 1
+	stepConcurrency uint
+	streamWorkers   uint // the items of a stream processed at the same time by ExecuteStream
+	// shuffle runs the continue on error steps in an order drawn from the shuffleSeed
+	shuffle     bool
+	shuffleSeed int64
+}
+
+// WithLogger sets the logger used by the workflow.
+// If not provided, or if nil is provided, the logging is disabled.
+func WithLogger(log Logger) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.log.log = log
+	}
+}
+
+// WithBeforeStep registers a hook called right before every step execution attempt, including the retries.
+func WithBeforeStep(fn func(ctx context.Context, stepName string)) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.beforeStep = fn
+	}
+}
+
+// WithAfterStep registers a hook called right after every step execution attempt, including the retries.
+// The attempt is 0 for the first execution and is incremented for every retry.
+func WithAfterStep(fn func(ctx context.Context, stepName string, err error, attempt int)) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.afterStep = fn
+	}
+}
+
+// WithOnRetry registers a hook called right before waiting for every retry attempt of a step, with the retry attempt
+// number, starting at 1, the error of the previous attempt, which triggered the retry, and the delay about to be waited,
+// e.g. to measure how often the steps retry, to tune their retry configs. The retries not run, because a retry budget
+// is spent, don't call it. It's not called for the workflow retries, configured with WithWorkflowRetry.
+func WithOnRetry(fn func(ctx context.Context, stepName string, attempt uint, err error, nextDelay time.Duration)) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.onRetry = fn
+	}
+}
+
+// WithOnSkip registers a hook called when a step is skipped because it already succeeded for the correlation ID, e.g.
+// to count the resumed executions. The steps skipped because their ShouldRun returned false don't call it.
+func WithOnSkip(fn func(ctx context.Context, stepName string)) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.onSkip = fn
+	}
+}
+
+// WithOnComplete registers a hook called once per execution, when the workflow ends, with the final error returned by
+// Execute, nil on success, e.g. to emit a "workflow finished" event with the outcome. Unlike the steps hooks, it's
+// called exactly once, whether the workflow succeeds, or is stopped by a failing step or by the ctx. For a workflow
+// configured with WithWorkflowRetry, it's called once all the attempts are done.
+func WithOnComplete(fn func(ctx context.Context, err error)) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.end.onComplete = fn
+	}
+}
+
+// WithOnError registers a hook called once per execution, when the workflow ends with an error, with the final error
+// returned by Execute, same as WithOnComplete, but only on failure. It's called after the WithOnComplete hook, if any.
+func WithOnError(fn func(ctx context.Context, err error)) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.end.onError = fn
+	}
+}
+
+// WithStepFilter runs only the steps for which the filter returns true, given their name, e.g. to rerun only the failed
+// step of an existing workflow during an incident, without rebuilding its configuration. The filtered out steps are
+// skipped, and logged as such, before their ShouldRun is called. It has no effect on the DAG and Parallel workflows.
+func WithStepFilter(filter func(stepName string) bool) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.stepFilter = filter
+	}
+}
+
+// WithStorage sets the storage used to persist the steps execution results, for the correlation ID set by WithCorrelationID.
+// When the workflow is executed again with the same correlation ID, the steps that already succeeded are skipped.
+// The stored results are cleared once the workflow succeeds.
+func WithStorage(storage Storage) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.store.storage = storage
+	}
+}
+
+// WithCorrelationID sets the ID under which the steps execution results are persisted in the storage set by WithStorage.
+func WithCorrelationID(id string) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.store.correlationID = id
+	}
+}
+
+// WithStorageTTL sets the duration after which the steps execution results stored in the storage set by WithStorage
+// expire, and are treated as absent. It works only with a ResultStorage, which records the time the results were saved at.
+// A zero TTL means the stored results never expire.
+// The results expire one by one, so a workflow replayed after some of its results expired runs again the steps whose
+// results expired, even if they succeeded, while skipping the ones whose results are still valid: the steps must be
+// idempotent. The expired results are not removed, as this is the job of the Storage, e.g. by a native TTL.
+func WithStorageTTL(ttl time.Duration) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.store.ttl = ttl
+	}
+}
+
+// WithStorageErrorPolicy sets how the errors of the storage set by WithStorage are handled.
+// If not provided, the StoragePolicyFail is used.
+func WithStorageErrorPolicy(policy StorageErrorPolicy) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.store.errorPolicy = policy
+	}
+}
+
+// WithSkipStatuses sets the stored statuses skipping the steps on replay, instead of StepStatusSuccess only, e.g. to
+// also skip the steps stored as StepStatusPermanentlyFailed, as a failure not worth retrying. The statuses replace the
+// default, so StepStatusSuccess must be one of them for the succeeded steps to be skipped. No statuses keep the default.
+// The Pipe workflows skip only the succeeded steps, as a skipped Pipe step passes its stored output value to the next
+// step, which the other statuses don't have.
+func WithSkipStatuses(statuses ...StepStatus) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.store.skipStatuses = statuses
+	}
+}
+
+// WithErrorWrapping wraps the error returned by the workflow in a WorkflowError, identifying the workflow, e.g. for a
+// central handler receiving the errors of several workflows. The errors of all the failing steps, joined in a single
+// error, are wrapped once, and errors.Is and errors.As still find any of them. It applies to the DAG and Parallel
+// workflows as well.
+func WithErrorWrapping() SequentialOption {
+	return func(o *sequentialOptions) {
+		o.wrapErrs = true
+	}
+}
+
+// WithStepKeyFunc sets the function mapping the step names, or their IdempotencyKey if set, to the keys of their results
+// in the storage set by WithStorage, e.g. to sanitize the names for a storage restricting the key characters. It is
+// applied to all the storage operations. By default, the names are the keys. A function mapping distinct names to the
+// same key makes the steps share their result, so it should not be lossy.
+func WithStepKeyFunc(fn func(name string) string) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.store.keyFunc = fn
+	}
+}
+
+// WithStorageOutputTransformer sets the function building the output stored for a failed step, given the step name and
+// error, instead of the error message, e.g. to redact the tokens or the personal data the message may hold, before
+// they reach the storage set by WithStorage. A nil output means no output is stored.
+func WithStorageOutputTransformer(fn func(stepName string, err error) *string) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.store.outputTransformer = fn
+	}
+}
+
+// WithCorrelationIDFromContext sets the function reading, from the execution ctx, the ID under which the steps execution
+// results are persisted in the storage set by WithStorage, allowing a workflow to be reused across requests.
+// The ID read from the ctx takes precedence over the one set by WithCorrelationID, which is used only if the read ID is empty.
+func WithCorrelationIDFromContext(fn func(ctx context.Context) string) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.store.correlationIDFromCtx = fn
+	}
+}
+
+// WithWorkflowRetry makes the workflow run again from the first step, up to maxAttempts times, if its execution fails,
+// waiting the delay provided by the backoff before every retry attempt. A nil backoff means no waiting.
+// It relies on the storage, set by WithStorage, to skip the steps which already succeeded, so it requires a correlation
+// ID: the execution without one fails with ErrCorrelationIDRequired. The returned error is the one of the last attempt.
+// It is meant for the transient failures spanning the steps retries, e.g. an infrastructure blip, and is applied on
+// top of them: a failing step is retried by its own configuration first.
+func WithWorkflowRetry(maxAttempts uint, backoff BackoffStrategy) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.retry = workflowRetry{maxAttempts: maxAttempts, backoff: backoff}
+	}
+}
+
+// WithClock sets the Clock measuring the retries delays and budgets, the steps durations logged, and the durations of
+// the ExecutionReport, e.g. to test them without waiting.
+// If not provided, or if nil is provided, the system time is used.
+func WithClock(clock Clock) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.clock = clock
+	}
+}
+
+// WithDefaultStepTimeout sets the timeout of the execution attempts of the steps whose SequentialStepConfig.Timeout is
+// zero, so the timeout needs not be set on every step. The steps with a Timeout of their own keep it.
+// If not provided, or if a d lower than or equal to 0 is provided, the steps without a Timeout have no timeout.
+func WithDefaultStepTimeout(d time.Duration) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.defaultStepTimeout = d
+	}
+}
+
+// WithOnTimeout registers a hook called when an execution attempt of a step fails after exceeding its timeout, set by
+// SequentialStepConfig.Timeout or WithDefaultStepTimeout, with the attempt, 0 for the first execution, e.g. to record the
+// timeouts apart from the other failures, as they often need a different remediation. It's called right after the
+// attempt, before deciding whether the step is retried. It's not called if the ctx of the workflow is done.
+func WithOnTimeout(fn func(ctx context.Context, stepName string, attempt uint)) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.onTimeout = fn
+	}
+}
+
+// WithGlobalRetryBudget bounds the total number of retries of the steps, in a workflow execution, to n, on top of the
+// retry configuration of every step: once the n retries are spent, the failing steps are no longer retried, even if
+// their own configuration allows it. It prevents the executions running for minutes, because many steps retry to their
+// maximum. A zero n means no retries at all.
+// The budget is shared by all the steps of an execution, across the workflow retries set by WithWorkflowRetry, which
+// don't spend it, and every execution has its own budget.
+func WithGlobalRetryBudget(n uint) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.globalRetryBudget = n
+		o.hasGlobalRetryBudget = true
+	}
+}
+
+// execution holds the state of a workflow execution, shared by its steps, and across the workflow retries.
+type execution struct {
+	retries *globalRetries // the retries left to the steps, if bounded
+	// cache holds the Cacheable steps which succeeded. It is allocated by the first cached step, unless the steps run
+	// concurrently, in which case it must be allocated upfront.
+	cache *succeededSteps
+	// results collects the results of the steps built by ResultStep, if not nil, see ExecuteWithResults.
+	results *stepResults
+	// checkpoints holds the checkpoints state, if the execution pauses at the checkpoint steps, see ExecuteUntilCheckpoint.
+	checkpoints *checkpoints
+}
+
+// cached reports whether a Cacheable step already succeeded for the key k, in the execution.
+func (e *execution) cached(k string) bool {
+	return e.cache != nil && e.cache.has(k)
+}
+
+// addCached records the success of a Cacheable step for the key k.
+func (e *execution) addCached(k string) {
+	if e.cache == nil {
+		e.cache = new(succeededSteps)
+	}
+	e.cache.add(k)
+}
+
+// globalRetries counts the retries left to the steps of a workflow execution, as set by WithGlobalRetryBudget.
+// It is safe for concurrent use, as the DAG steps run concurrently.
+type globalRetries struct {
+	left atomic.Uint64
+}
+
+// newGlobalRetries returns the retries counter of a new execution, or nil if there is no global retry budget.
+func (o *sequentialOptions) newGlobalRetries() *globalRetries {
+	if !o.hasGlobalRetryBudget {
+		return nil
+	}
+	g := new(globalRetries)
+	g.left.Store(uint64(o.globalRetryBudget))
+
+	return g
+}
+
+// take reports whether a retry is left, in which case it is spent. A nil globalRetries has no limit.
+func (g *globalRetries) take() bool {
+	if g == nil {
+		return true
+	}
+	for {
+		left := g.left.Load()
+		if left == 0 {
+			return false
+		}
+		if g.left.CompareAndSwap(left, left-1) {
+			return true
+		}
+	}
+}
+
+// workflowRetry is the retry configuration of the whole workflow.
+type workflowRetry struct {
+	maxAttempts uint
+	backoff     BackoffStrategy
 }
 
 // Sequential is a workflow that runs its steps in a predefined sequence(the order of the []SequentialStepConfig).
+// Every step receives the same req, so the steps sharing an accumulated state, without switching to a Pipe, use a
+// pointer for T, e.g. Sequential[*State], and mutate the pointed state: every step sees the changes of the previous
+// ones, and the caller reads the result from its own pointer once Execute returns. The steps of a Sequential run one
+// at a time, so the state needs no synchronization, but a new state must be passed to every execution, as the
+// concurrent executions would otherwise share it, and so would the steps run concurrently by a DAG.
 type Sequential[T any] struct {
 	name        string
 	stepsConfig []SequentialStepConfig[T] // the workflow runs the steps following the slice order
-	log         Logger                    // the internal logger is a no op if nil is provided
+	// stepsProvider builds the steps at execution time, for the dynamic workflows, and takes precedence over the stepsConfig.
+	stepsProvider func(ctx context.Context, req T) []SequentialStepConfig[T]
+	sequentialOptions
 }
 
-// NewSequential is the workflow constructor.
-func NewSequential[T any](name string, stepsCfg []SequentialStepConfig[T], log Logger) *Sequential[T] {
-	if log == nil {
-		log = noOpLogger{}
-	}
+// the workflow implements the SequentialStep interface, so it can be a step of another workflow.
+var _ SequentialStep[any] = (*Sequential[any])(nil)
 
+// NewSequential is the workflow constructor.
+// The nil options are ignored, so NewSequential(name, stepsCfg, nil) builds a workflow with the default configuration.
+// If WithStrictValidation is provided, it panics with the Validate error, for a configuration which is not valid.
+func NewSequential[T any](name string, stepsCfg []SequentialStepConfig[T], opts ...SequentialOption) *Sequential[T] {
 	s := Sequential[T]{
-		name:        name,
-		stepsConfig: stepsCfg,
-		log:         log,
+		name:              name,
+		stepsConfig:       stepsCfg,
+		sequentialOptions: newSequentialOptions(name, stepsCfg, opts),
 	}
 
 	return &s
 }
 
+// NewDynamicSequential is the constructor of a workflow whose steps depend on the request.
+// The stepsProvider is called at the start of every execution, to build the steps for the req.
+// The storage based skipping works with the dynamic steps as well, as long as the step names are stable for a correlation ID.
+func NewDynamicSequential[T any](
+	name string,
+	stepsProvider func(ctx context.Context, req T) []SequentialStepConfig[T],
+	opts ...SequentialOption,
+) *Sequential[T] {
+	s := NewSequential[T](name, nil, opts...)
+	s.stepsProvider = stepsProvider
+
+	return s
+}
+
+// newSequentialOptions applies the opts over the default configuration, for the workflow named name.
+// The options are applied on a lazily allocated value, so that the workflow built without options produces no allocations.
+// It panics if the strict validation is enabled and the stepsCfg are not valid. The validation is done here, rather than
+// in the constructor, to keep the constructor inlined, and so the workflow off the heap.
+func newSequentialOptions[T any](name string, stepsCfg []SequentialStepConfig[T], opts []SequentialOption) sequentialOptions {
+	var o *sequentialOptions
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if o == nil {
+			o = new(sequentialOptions)
+		}
+		opt(o)
+	}
+	if o == nil {
+		return sequentialOptions{log: logger{log: noOpLogger{}, workflowName: name}}
+	}
+	if o.log.log == nil {
+		o.log.log = noOpLogger{}
+	}
+	o.log.workflowName = name
+	if o.strictValidation {
+		if err := validateSequentialSteps(name, stepsCfg); err != nil {
+			panic(err)
+		}
+	}
+
+	return *o
+}
+
 // Name returns the name of the workflow.
 func (s *Sequential[T]) Name() string {
 	return s.name
 }
 
-// Execute loops through all the steps from the s.stepsConfig collection and passes the ctx and the req to every SequentialStepConfig.Step.
+// SetCorrelationID replaces the ID under which the steps execution results are persisted in the storage set by
+// WithStorage, as WithCorrelationID does at construction time.
+// It allows a single workflow to be reused across requests, instead of building a new one for every request, when the
+// steps hold no per request state. It must be called between the executions, as it's not safe to call concurrently with
+// Execute: the workflows executed concurrently must read their correlation ID from the ctx, see
+// WithCorrelationIDFromContext.
+func (s *Sequential[T]) SetCorrelationID(id string) {
+	s.store.correlationID = id
+}
+
+// Execute loops through all the steps from the s.stepsConfig collection(or the ones built by the steps provider, for a
+// dynamic workflow) and passes the ctx and the req to every SequentialStepConfig.Step.
 // The errors returned by the failing steps are wrapped in a single error, so any error from any failing SequentialStepConfig.Step
-// can be checked using errors.Is or errors.As against the returned error.
+// can be checked using errors.Is or errors.As against the returned error. Every step error is wrapped in a StepError,
+// identifying the failing step. The steps errors are joined in the steps declaration order, even for the steps run
+// concurrently by WithStepConcurrency, so the failures summaries are stable, except for the steps shuffled by
+// WithShuffleSteps, whose errors are joined in the execution order.
 // In case a SequentialStepConfig.Step fails, the workflow checks for the SequentialStepConfig.ContinueWorkflowOnError flag, and stops processing
 // the remaining steps if the value is true.
+// The ctx is checked before every step, and a done ctx stops the workflow, the ctx error being returned along with the steps errors.
+// The time left until the ctx deadline, if any, is logged before every step, and the workflow stops with
+// context.DeadlineExceeded before a step if it's less than the minimum set by WithMinRemainingTime. The workflow stops
+// with ErrStopped before a step once the channel set by WithStopSignal is closed.
+// The workflow configured with WithMaxSteps fails with ErrTooManySteps, before running any step, if it has more steps
+// than the max.
+// A nil SequentialStepConfig.Step stops the workflow with an error identifying its index, see Validate to report it at
+// construction time.
+// If a storage and a correlation ID are configured, the steps that already succeeded for the correlation ID are skipped,
+// and the stored results are cleared once the workflow succeeds.
+// The steps whose SequentialStepConfig.ShouldRun returns false are skipped, and so are the steps filtered out by the
+// WithStepFilter, and the Cacheable steps which already succeeded in the execution, for their cache key.
+// If the compensation is enabled, a step failure stopping the workflow triggers the compensation of the succeeded steps,
+// and the compensation errors are returned in a CompensationError, along with the steps errors.
+// If the workflow retry is enabled, the failed workflow runs again, as described by WithWorkflowRetry.
 func (s *Sequential[T]) Execute(ctx context.Context, req T) error {
-	s.log.Info(concatStr("[START] executing workflow: ", s.name))
-	defer func() { s.log.Info(concatStr("[DONE] executing workflow: ", s.name)) }()
+	ctx = s.execID.context(ctx)
 
-	var errs []error
+	return s.end.end(ctx, wrapWorkflowErr(s.wrapErrs, s.name, s.execute(ctx, req, nil, execution{})))
+}
+
+// execute runs the workflow, retrying it if configured, as described by Execute, and fills the report if it's not nil.
+// The report describes the last attempt. The exec holds the state of the execution, its global retries being set here.
+func (s *Sequential[T]) execute(ctx context.Context, req T, report *ExecutionReport, exec execution) error {
+	exec.retries = s.newGlobalRetries()
+	if s.retry.maxAttempts == 0 {
+		return s.run(ctx, req, report, &exec)
+	}
+	if _, ok := s.store.id(ctx); !ok {
+		return ErrCorrelationIDRequired
+	}
 	var err error
-	for _, stepConfig := range s.stepsConfig {
-		err = s.executeStep(ctx, stepConfig, req)
-		if err != nil {
-			// this prevents extra allocations, by creating the slice only once, and with enough capacity.
-			if errs == nil {
-				errs = make([]error, 0, len(s.stepsConfig))
+	for attempt := uint(0); attempt <= s.retry.maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := retryDelay(s.retry.backoff, attempt, 0, s.retryBackoffCap)
+			s.log.print(
+				ctx,
+				LevelWarn,
+				concatStr(
+					"retrying workflow: ", s.name, ", retry attempt count: ", strconv.FormatUint(uint64(attempt), 10),
+					", after waiting for: ", strconv.FormatInt(delay.Milliseconds(), 10), "ms",
+				),
+			)
+			if sleepErr := sleep(ctx, s.clock, delay); sleepErr != nil {
+				return errors.Join(err, sleepErr)
 			}
-			errs = append(errs, err)
+			report.reset()
+		}
+		if err = s.run(ctx, req, report, &exec); err == nil || errors.Is(err, ErrStopped) || exec.paused() {
+			return err
+		}
+	}
+
+	return err
+}
+
+// run runs the workflow steps once, as described by Execute, and fills the report if it's not nil.
+// The exec holds the state shared by the steps of the execution.
+func (s *Sequential[T]) run(ctx context.Context, req T, report *ExecutionReport, exec *execution) error {
+	s.log.print(ctx, LevelInfo, concatStr("[START] executing workflow: ", s.name))
+	defer s.log.printDone(ctx)
+
+	stepsCfg := s.steps(ctx, req)
+	if err := maxStepsErr(s.name, len(stepsCfg), s.maxSteps); err != nil {
+		s.log.print(ctx, LevelError, concatStr(failed, " stopping workflow: ", s.name, ", err: ", err.Error()))
+
+		return err
+	}
+	stepsCfg = s.shuffled(ctx, stepsCfg)
+	first, err := s.firstStep(stepsCfg, exec)
+	if err != nil {
+		s.log.print(ctx, LevelError, concatStr(failed, " stopping workflow: ", s.name, ", err: ", err.Error()))
+
+		return err
+	}
+	var errs []error
+	var succeeded []int // the indexes of the succeeded steps, tracked only if the compensation is enabled
+	for i := first; i < len(stepsCfg); i++ {
+		stepConfig := stepsCfg[i]
+		// a done ctx, or one without enough time left, stops the workflow before running the next step.
+		if err = s.checkStep(ctx, i, stepConfig); err != nil {
+			errs = appendErr(errs, err, len(stepsCfg))
+
+			break
+		}
+		if c := s.cluster(stepsCfg, i); len(c.steps) > 1 {
+			cErrs, cSucceeded := s.runCluster(ctx, req, c, report, exec)
+			errs = append(errs, cErrs...)
+			succeeded = append(succeeded, cSucceeded...)
+			i += len(c.steps) - 1
+
+			continue
+		}
+		if !s.shouldRun(ctx, stepConfig, req) {
+			report.add(stepConfig.name()).skip()
+
+			continue
+		}
+		var cached bool
+		cached, err = s.processStep(ctx, stepConfig, req, report.add(stepConfig.name()), exec)
+		// the cached step already ran in the execution, so it's compensated once, for the occurrence which ran.
+		if err == nil && !cached && s.compensation {
+			succeeded = append(succeeded, i)
+		}
+		// the paused workflow is not done, so its stored results are not cleared.
+		if err == nil && s.pause(ctx, stepsCfg, i, exec) {
+			return joinErrs(errs)
+		}
+		if err != nil {
+			errs = appendErr(errs, err, len(stepsCfg))
 
 			if stepConfig.ContinueWorkflowOnError {
-				s.log.Info(
-					concatStr(
-						"the step name: ",
-						stepConfig.Step.Name(),
-						", is configured not to stop the workflow on error, so the following stepsConfig(if any) will still run",
-					),
-				)
+				s.logContinue(ctx, stepConfig.name())
 
 				continue
 			}
+			if s.compensation {
+				if cErr := s.compensate(ctx, req, stepsCfg, succeeded); cErr != nil {
+					errs = append(errs, cErr)
+				}
+			}
 
 			break
 		}
 	}
 
-	switch {
+	if len(errs) > 0 {
+		return joinErrs(errs)
+	}
+
+	return s.store.handleErr(ctx, s.log, s.store.clearDB(ctx))
+}
+
+// logContinue logs that the workflow goes on after the failure of the step configured to continue the workflow on error.
+func (s *Sequential[T]) logContinue(ctx context.Context, stepName string) {
+	s.log.print(
+		ctx,
+		LevelInfo,
+		concatStr(
+			"the step name: ",
+			stepName,
+			", is configured not to stop the workflow on error, so the following stepsConfig(if any) will still run",
+		),
+	)
+}
+
+// checkStep returns the error stopping the workflow before the step found at the index i: the error of a nil step, the
+// ctx error, ErrStopped if the stop signal is closed, or context.DeadlineExceeded if the time left until the ctx deadline
+// is less than the minimum set by WithMinRemainingTime.
+func (s *Sequential[T]) checkStep(ctx context.Context, i int, stepCfg SequentialStepConfig[T]) error {
+	if stepCfg.Step == nil {
+		err := nilStepErr(i)
+		s.log.print(ctx, LevelError, concatStr(failed, " stopping workflow: ", s.name, ", err: ", err.Error()))
+
+		return err
+	}
+	err := ctx.Err()
+	if err == nil {
+		err = stopped(s.stop)
+	}
+	if err == nil {
+		err = checkRemainingTime(ctx, s.log, s.clock, stepCfg.name(), s.minRemainingTime)
+	}
+	if err != nil {
+		s.log.print(ctx, LevelError, concatStr(failed, " stopping workflow: ", s.name, ", err: ", err.Error()))
+	}
+
+	return err
+}
+
+// steps returns the steps to execute for the req.
+func (s *Sequential[T]) steps(ctx context.Context, req T) []SequentialStepConfig[T] {
+	if s.stepsProvider != nil {
+		return s.stepsProvider(ctx, req)
+	}
+
+	return s.stepsConfig
+}
+
+// appendErr appends the err to errs.
+// this prevents extra allocations, by creating the slice only once, and with enough capacity(size).
+func appendErr(errs []error, err error, size int) []error {
+	if errs == nil {
+		errs = make([]error, 0, size)
+	}
+
+	return append(errs, err)
+}
+
+// indexedErr is the error of the step found at the index, in the workflow steps.
+type indexedErr struct {
+	index int
+	err   error
+}
+
+// orderedErrs collects the errors of the steps running concurrently, so they are returned in the steps declaration order,
+// whatever the order the steps finished in, keeping the failures summaries stable.
+type orderedErrs []indexedErr
+
+// add collects the err of the step found at the index.
+func (o *orderedErrs) add(index int, err error) {
+	*o = append(*o, indexedErr{index: index, err: err})
+}
+
+// errs returns the collected errors, in the order of the indexes of their steps.
+func (o orderedErrs) errs() []error {
+	if len(o) == 0 {
+		return nil
+	}
+	sort.SliceStable(o, func(i, j int) bool { return o[i].index < o[j].index })
+	errs := make([]error, 0, len(o))
+	for _, e := range o {
+		errs = append(errs, e.err)
+	}
+
+	return errs
+}
+
+// joinErrs wraps the errs in a single error.
+func joinErrs(errs []error) error {
 	// prevents unnecessary allocations caused by errors.Join, if the collection holds only 1 error.
-	case len(errs) == 1:
+	if len(errs) == 1 {
 		return errs[0]
-	case len(errs) > 1:
-		return errors.Join(errs...)
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// shouldRun reports whether the step runs for the req, according to the step filter, and to its SequentialStepConfig.Enabled
+// and ShouldRun.
+func (s *Sequential[T]) shouldRun(ctx context.Context, stepCfg SequentialStepConfig[T], req T) bool {
+	stepName := stepCfg.name()
+	log := s.log.override(stepCfg.Logger)
+	if s.stepFilter != nil && !s.stepFilter(stepName) {
+		log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", filtered out"), stepName, 0)
+
+		return false
+	}
+	if stepCfg.Enabled != nil && !stepCfg.Enabled(ctx) {
+		log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", disabled"), stepName, 0)
+
+		return false
+	}
+	if stepCfg.ShouldRun == nil || stepCfg.ShouldRun(ctx, req) {
+		return true
+	}
+	log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", not required to run"), stepName, 0)
+
+	return false
+}
+
+// processStep skips the step if it already succeeded for the correlation ID, or in the execution for its cache key,
+// otherwise it executes it. It reports whether the step was skipped for its cache key.
+// The rep is filled with the step execution details, if it's not nil, and the exec holds the state shared by the steps
+// of the execution.
+func (s *Sequential[T]) processStep(
+	ctx context.Context,
+	stepCfg SequentialStepConfig[T],
+	req T,
+	rep *StepReport,
+	exec *execution,
+) (bool, error) {
+	stepName := stepCfg.name()
+	log := s.log.override(stepCfg.Logger)
+	start := rep.start(s.clock)
+	_, skip, failure, err := s.store.skipStepOrAlias(ctx, stepCfg.storageKey(), stepCfg.Aliases)
+	if err = s.store.handleErr(ctx, log, err); err != nil {
+		rep.finish(s.clock, start, err)
+
+		return false, err
+	}
+	if skip {
+		log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", already succeeded"), stepName, 0)
+		if s.onSkip != nil {
+			s.onSkip(ctx, stepName)
+		}
+		rep.skip()
+
+		return false, nil
+	}
+	stepReq := stepCfg.request(ctx, req)
+	key, cacheable := stepCacheKey(stepCfg.Step, stepReq)
+	if cacheable && exec.cached(key) {
+		log.printStep(ctx, LevelInfo, concatStr("skipping step: ", stepName, ", already succeeded for the cache key: ", key), stepName, 0)
+		rep.skip()
+
+		return true, nil
+	}
+	log.printReplay(ctx, stepName, failure)
+	printPayload(ctx, log, stepName, "input", stepReq)
+	metricsStart := s.metrics.started(ctx, stepName)
+	attempts, err := s.executeStep(ctx, stepCfg, stepReq, rep, exec)
+	s.metrics.finished(ctx, stepName, metricsStart, err)
+	sendProgress(ctx, s.progress, stepName, attempts, err)
+	rep.finish(s.clock, start, err)
+	if cacheable && err == nil {
+		exec.addCached(key)
+	}
+
+	return false, err
 }
 
 // executeStep processes a single SequentialStep by passing it the ctx and the req.
-// It retries the SequentialStep if it implements the RetryDecider interface, and uses the max attempts and the attempt delay provided
+// It retries the SequentialStep if it implements the RetryDecider interface, with CanRetry() returning true, or the ErrorRetryDecider
+// interface, with RetryableError(err) returning true for the error of the last attempt, and uses the max attempts, capped to MaxRetryAttempts, and the attempt delay provided
 // by the SequentialStepConfig.RetryConfigProvider() if it's not nil. If the SequentialStepConfig.RetryConfigProvider() is nil, there is no retry.
-func (s *Sequential[T]) executeStep(ctx context.Context, stepCfg SequentialStepConfig[T], req T) error {
-	step := stepCfg.Step
-	stepName := step.Name()
+// The SequentialStepConfig.RetryIf, if set, decides the retry instead of the SequentialStep.
+// If the SequentialStepConfig.Backoff is not nil, it provides the delay before every retry attempt, instead of the attempt delay.
+// If the SequentialStepConfig.RetryBudget is greater than 0, the retries stop once their delay would exceed the budget.
+// Every attempt is bounded by the SequentialStepConfig.Timeout, or by the default step timeout if the former is zero.
+// The ctx passed to the SequentialStep carries the workflow and the step names, if enabled by WithStepNamesInContext,
+// and is decorated by the enricher set by WithContextEnricher, if any.
+// If the panic recovery is enabled, a panicking attempt fails with a PanicError, and is not retried.
+// The retries stop once the global retry budget of the exec, if any, is spent.
+// The waiting before a retry attempt stops as soon as the ctx is done, and the ctx error is returned along with the step error.
+// The result of the last attempt is persisted in the storage, if configured.
+// The error is wrapped in a StepError, holding the step name and the number of attempts which ran, also returned.
+func (s *Sequential[T]) executeStep(
+	ctx context.Context,
+	stepCfg SequentialStepConfig[T],
+	req T,
+	rep *StepReport,
+	exec *execution,
+) (uint, error) {
+	stepName := stepCfg.name()
+	log := s.log.override(stepCfg.Logger)
+	stepCtx := enrichContext(withStepNames(ctx, s.stepNames, s.name, stepName), s.enrichContext, stepName)
 
-	var maxAttempts uint
-	var attemptDelay time.Duration
-	if stepCfg.RetryConfigProvider != nil {
-		maxAttempts, attemptDelay = stepCfg.RetryConfigProvider()
-	}
+	maxAttempts, attemptDelay := retryConfig(stepCfg.RetryConfigProvider)
 
-	var attempt int
+	var attempt uint
+	var attempts uint // the attempts which ran, as the last loop iteration may stop before running the step
 	var err error
-	for attempt = 0; attempt <= int(maxAttempts); attempt++ {
+	var start time.Time // the start of the first attempt, measured only for a retry budget
+	if stepCfg.RetryBudget > 0 {
+		start = now(s.clock)
+	}
+	var permanent bool // the last attempt failed with an error the step can't be retried for
+	streak := failureStreak{max: s.maxConsecutiveFailures}
+	for attempt = 0; attempt <= maxAttempts; attempt++ {
 		// if the attempt is greater than 0, then it's a retry
 		if attempt > 0 {
-			s.log.Info(
-				concatStr("step: ", stepName, " is configured to retry", ", retry attempt count: ", strconv.Itoa(attempt)),
+			log.printStep(
+				ctx,
+				LevelDebug,
+				concatStr("step: ", stepName, " is configured to retry", ", retry attempt count: ", strconv.FormatUint(uint64(attempt), 10)),
+				stepName,
+				attempt,
 			)
 			// allow some waiting time before trying again
-			s.log.Info(concatStr("waiting for: ", strconv.FormatInt(attemptDelay.Milliseconds(), 10), "ms before retry attempt"))
-			time.Sleep(attemptDelay)
+			delay := stepCfg.retryDelay(attempt, err, attemptDelay, s.retryBackoffCap)
+			if stepCfg.RetryBudget > 0 && now(s.clock).Sub(start)+delay > stepCfg.RetryBudget {
+				log.printStep(ctx, LevelError, concatStr("step: ", stepName, " exhausted its retry budget"), stepName, attempt)
+
+				break
+			}
+			// the global retry budget is taken last, so a retry refused for another reason doesn't spend it.
+			if !exec.retries.take() {
+				log.printStep(
+					ctx,
+					LevelError,
+					concatStr("step: ", stepName, " is not retried, the global retry budget of workflow: ", s.name, " is spent"),
+					stepName,
+					attempt,
+				)
+
+				break
+			}
+			// the retry is measured only once no budget refuses it.
+			s.metrics.retried(ctx, stepName, attempt)
+			log.printStep(
+				ctx,
+				LevelDebug,
+				concatStr("waiting for: ", strconv.FormatInt(delay.Milliseconds(), 10), "ms before retry attempt"),
+				stepName,
+				attempt,
+			)
+			if s.onRetry != nil {
+				s.onRetry(ctx, stepName, attempt, err, delay)
+			}
+			if sleepErr := sleep(ctx, s.clock, delay); sleepErr != nil {
+				return attempts, StepError{StepName: stepName, Attempts: attempts, Err: errors.Join(err, sleepErr)}
+			}
+		}
+		if waitErr := waitLimiter(ctx, log, s.limiter, stepName, attempt); waitErr != nil {
+			return attempts, StepError{StepName: stepName, Attempts: attempts, Err: errors.Join(err, waitErr)}
+		}
+		trial, openErr := checkCircuit(ctx, log, stepCfg.CircuitBreaker, stepName, attempt)
+		if openErr != nil {
+			return attempts, StepError{StepName: stepName, Attempts: attempts, Err: errors.Join(err, openErr)}
+		}
+		if s.beforeStep != nil {
+			s.beforeStep(ctx, stepName)
+		}
+		rep.attempt()
+		attempts++
+		attemptStart := log.startTimer(s.clock)
+		err = s.executeAttempt(stepCtx, stepCfg, req, attempt, exec.results)
+		stepCfg.CircuitBreaker.record(trial, err)
+		res := attemptResult{stepName: stepName, attempt: attempt, elapsed: log.elapsed(s.clock, attemptStart), err: err}
+		if s.afterStep != nil {
+			s.afterStep(ctx, stepName, err, int(attempt))
 		}
-		err = step.Execute(ctx, req)
 		if err == nil {
-			s.log.Info(concatStr(succeed, " executing step: ", stepName))
+			log.printAttempt(ctx, LevelInfo, res)
+
+			break
+		}
+		// the failure is permanent if the step can't be retried for the err, whether or not it ran out of attempts.
+		permanent = !stepCfg.canRetry(ctx, err, attempt)
+		if attempt == maxAttempts || permanent {
+			log.printAttempt(ctx, LevelError, res)
 
 			break
 		}
-		s.log.Error(concatStr(failed, " executing step: ", stepName, ", err: ", err.Error()))
-		// only the ones implementing the RetryDecider, with CanRetry() returning true, can run more than once
-		if stepR, ok := step.(RetryDecider); !ok || (ok && !stepR.CanRetry()) {
+		if streak.stop(ctx, log, res) {
 			break
 		}
+		// the failure is recoverable, as the step is retried.
+		log.printAttempt(ctx, LevelWarn, res)
+	}
+	if dbErr := s.store.handleErr(ctx, log, s.store.storeStepResult(ctx, stepName, stepCfg.storageKey(), err, permanent)); dbErr != nil {
+		err = errors.Join(err, dbErr)
+	}
+	if err != nil {
+		return attempts, StepError{StepName: stepName, Attempts: attempts, Err: err}
+	}
+
+	return attempts, nil
+}
+
+// stepTimeout returns the timeout of the execution attempts of the step: its own Timeout if set, otherwise the default
+// step timeout.
+func (s *Sequential[T]) stepTimeout(stepCfg SequentialStepConfig[T]) time.Duration {
+	if stepCfg.Timeout > 0 {
+		return stepCfg.Timeout
+	}
+
+	return s.defaultStepTimeout
+}
+
+// executeAttempt executes the step once, with a ctx bounded by the step timeout, if any, and collects its result in the
+// results, if not nil.
+// If the panic recovery is enabled, a panic of the step is recovered, and returned as a PanicError.
+func (s *Sequential[T]) executeAttempt(
+	ctx context.Context,
+	stepCfg SequentialStepConfig[T],
+	req T,
+	attempt uint,
+	results *stepResults,
+) (err error) {
+	if s.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = s.recovered(ctx, stepCfg, attempt, r)
+			}
+		}()
+	}
+	timeout := s.stepTimeout(stepCfg)
+	if timeout <= 0 {
+		return runStep(ctx, stepCfg, req, results)
+	}
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err = runStep(stepCtx, stepCfg, req, results)
+	// only the attempt deadline is a timeout, not the one of the workflow ctx.
+	if err != nil && s.onTimeout != nil && errors.Is(stepCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		s.onTimeout(ctx, stepCfg.name(), attempt)
 	}
 
 	return err
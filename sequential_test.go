@@ -3,8 +3,13 @@ package workflow
 import (
 	"context"
 	"errors"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/silviutanasa/workflow/workflowtest"
 )
 
 var defaultRetryConfigProviderTest = func() (maxAttempts uint, attemptDelay time.Duration) { return 2, time.Nanosecond }
@@ -78,6 +83,36 @@ func TestSequentialExecuteBehaviourOnReturningErrors(t *testing.T) {
 	}
 }
 
+func TestSequentialExecuteBehaviourOnNestedWorkflow(t *testing.T) {
+	var executed []string
+	newStep := func(name string) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			executed = append(executed, name)
+
+			return nil
+		}}
+	}
+	nested := NewSequential("nested", []SequentialStepConfig[any]{
+		{Step: newStep("nested step 1")},
+		{Step: newStep("nested step 2")},
+	})
+	input := []SequentialStepConfig[any]{
+		{Step: newStep("step 1")},
+		{Step: nested},
+		{Step: newStep("step 3")},
+	}
+
+	err := NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	expectedOutput := []string{"step 1", "nested step 1", "nested step 2", "step 3"}
+	if !reflect.DeepEqual(executed, expectedOutput) {
+		t.Errorf("The steps execution order is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, executed)
+	}
+}
+
 func TestSequentialExecuteBehaviourOnRetry(t *testing.T) {
 	anyErr := errors.New("any-err")
 	tests := []struct {
@@ -169,6 +204,1043 @@ func TestSequentialExecuteBehaviourOnStoppingWorkflow(t *testing.T) {
 	}
 }
 
+func TestSequentialExecuteBehaviourOnStepHooks(t *testing.T) {
+	anyErr := errors.New("any-err")
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepFailedRetryableRecoverable("step 2", anyErr, 3), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+	var before []string
+	var after []int
+	var afterErrs []error
+	c := NewSequential(
+		"some-workflow",
+		input,
+		WithBeforeStep(func(ctx context.Context, stepName string) {
+			before = append(before, stepName)
+		}),
+		WithAfterStep(func(ctx context.Context, stepName string, err error, attempt int) {
+			after = append(after, attempt)
+			afterErrs = append(afterErrs, err)
+		}),
+	)
+	c.Execute(context.TODO(), nil)
+
+	expectedBefore := []string{"step 1", "step 2", "step 2", "step 2"}
+	if !reflect.DeepEqual(before, expectedBefore) {
+		t.Errorf("The before step hook was not called as expected: \n expected = %#v, \n actual = %#v", expectedBefore, before)
+	}
+	expectedAfter := []int{0, 0, 1, 2}
+	if !reflect.DeepEqual(after, expectedAfter) {
+		t.Errorf("The after step hook attempts are not as expected: \n expected = %#v, \n actual = %#v", expectedAfter, after)
+	}
+	expectedAfterErrs := []error{nil, anyErr, anyErr, nil}
+	if !reflect.DeepEqual(afterErrs, expectedAfterErrs) {
+		t.Errorf("The after step hook errors are not as expected: \n expected = %#v, \n actual = %#v", expectedAfterErrs, afterErrs)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnContextCancellationDuringRetry(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step := newStepFailedRetryable("step 1", anyErr)
+	input := []SequentialStepConfig[any]{
+		{Step: step, RetryConfigProvider: func() (uint, time.Duration) { return 2, time.Hour }},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	c := NewSequential("some-workflow", input, nil)
+	actualOutput := c.Execute(ctx, nil)
+
+	if !errors.Is(actualOutput, context.Canceled) {
+		t.Errorf("The workflow error does not wrap the context error: \n expected = %#v, \n actual = %#v", context.Canceled, actualOutput)
+	}
+	if !errors.Is(actualOutput, anyErr) {
+		t.Errorf("The workflow error does not wrap the step error: \n expected = %#v, \n actual = %#v", anyErr, actualOutput)
+	}
+	if step.invocationCount != 1 {
+		t.Errorf("The step was invoked after the context cancellation: \n expected = %#v, \n actual = %#v", 1, step.invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnContextCancellationBetweenSteps(t *testing.T) {
+	anyErr := errors.New("any-err")
+	lastStep := newStepSuccessful("step 3")
+	input := []SequentialStepConfig[any]{
+		{Step: newStepFailedNonRetryable("step 1", anyErr), ContinueWorkflowOnError: true},
+		{Step: stepFuncMock(func(ctx context.Context, request any) error {
+			<-ctx.Done()
+
+			return nil
+		})},
+		{Step: lastStep},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	c := NewSequential("some-workflow", input, nil)
+	actualOutput := c.Execute(ctx, nil)
+
+	if !errors.Is(actualOutput, context.DeadlineExceeded) {
+		t.Errorf("The workflow error does not wrap the context error: \n expected = %#v, \n actual = %#v", context.DeadlineExceeded, actualOutput)
+	}
+	if !errors.Is(actualOutput, anyErr) {
+		t.Errorf("The workflow error does not wrap the step error: \n expected = %#v, \n actual = %#v", anyErr, actualOutput)
+	}
+	if lastStep.invocationCount != 0 {
+		t.Errorf("The step was invoked after the context deadline: \n expected = %#v, \n actual = %#v", 0, lastStep.invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnShouldRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		shouldRun      func(ctx context.Context, req any) bool
+		expectedOutput int
+	}{
+		{
+			name:           "a step without a predicate should run",
+			shouldRun:      nil,
+			expectedOutput: 1,
+		},
+		{
+			name:           "a step with a predicate returning true should run",
+			shouldRun:      func(ctx context.Context, req any) bool { return req == "run" },
+			expectedOutput: 1,
+		},
+		{
+			name:           "a step with a predicate returning false should be skipped",
+			shouldRun:      func(ctx context.Context, req any) bool { return req != "run" },
+			expectedOutput: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := newStepSuccessful("step 1")
+			lastStep := newStepSuccessful("step 2")
+			input := []SequentialStepConfig[any]{
+				{Step: step, ShouldRun: tt.shouldRun},
+				{Step: lastStep},
+			}
+
+			err := NewSequential("some-workflow", input, nil).Execute(context.TODO(), "run")
+
+			if err != nil {
+				t.Errorf("The workflow returned an unexpected error: %v", err)
+			}
+			if step.invocationCount != tt.expectedOutput {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, step.invocationCount)
+			}
+			if lastStep.invocationCount != 1 {
+				t.Errorf("The step following the predicate step did not run")
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnEnabled(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        func(ctx context.Context) bool
+		expectedOutput int
+	}{
+		{
+			name:           "a step without an Enabled func should run",
+			enabled:        nil,
+			expectedOutput: 1,
+		},
+		{
+			name:           "an enabled step should run",
+			enabled:        func(ctx context.Context) bool { return true },
+			expectedOutput: 1,
+		},
+		{
+			name:           "a disabled step should be skipped, without calling its ShouldRun",
+			enabled:        func(ctx context.Context) bool { return false },
+			expectedOutput: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := newStepSuccessful("step 1")
+			lastStep := newStepSuccessful("step 2")
+			shouldRunCount := 0
+			shouldRun := func(ctx context.Context, req any) bool {
+				shouldRunCount++
+
+				return true
+			}
+			log := &loggerMock{}
+			input := []SequentialStepConfig[any]{
+				{Step: step, Enabled: tt.enabled, ShouldRun: shouldRun},
+				{Step: lastStep},
+			}
+
+			err := NewSequential("some-workflow", input, WithLogger(log)).Execute(context.TODO(), nil)
+
+			if err != nil {
+				t.Errorf("The workflow returned an unexpected error: %v", err)
+			}
+			if step.invocationCount != tt.expectedOutput {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, step.invocationCount)
+			}
+			if shouldRunCount != tt.expectedOutput {
+				t.Errorf("The ShouldRun invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, shouldRunCount)
+			}
+			if lastStep.invocationCount != 1 {
+				t.Errorf("The step following the feature flagged step did not run")
+			}
+			if disabled := contains(log.info, "skipping step: step 1, disabled"); disabled != (tt.expectedOutput == 0) {
+				t.Errorf("The disabled step logging is not as expected: \n actual = %#v", log.info)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStepFilter(t *testing.T) {
+	step1 := newStepSuccessful("step 1")
+	step2 := newStepSuccessful("step 2")
+	step3 := newStepSuccessful("step 3")
+	log := &loggerMock{}
+	input := []SequentialStepConfig[any]{
+		{Step: step1},
+		{Step: step2},
+		{Step: step3, ShouldRun: func(ctx context.Context, req any) bool { return true }},
+	}
+
+	err := NewSequential(
+		"some-workflow",
+		input,
+		WithLogger(log),
+		WithStepFilter(func(stepName string) bool { return stepName == "step 2" }),
+	).Execute(context.TODO(), nil)
+
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+	actualOutput := []int{step1.invocationCount, step2.invocationCount, step3.invocationCount}
+	expectedOutput := []int{0, 1, 0}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps invocation counts are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	if !contains(log.info, "skipping step: step 1, filtered out") || !contains(log.info, "skipping step: step 3, filtered out") {
+		t.Errorf("The filtered out steps are not logged: \n actual = %#v", log.info)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnRetryBudget(t *testing.T) {
+	step := newStepFailedRetryable("step 1", errors.New("any-err"))
+	input := []SequentialStepConfig[any]{
+		{
+			Step:                step,
+			RetryConfigProvider: func() (uint, time.Duration) { return 1000, time.Millisecond },
+			RetryBudget:         5 * time.Millisecond,
+		},
+	}
+
+	clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+
+	NewSequential("some-workflow", input, WithClock(clock)).Execute(context.TODO(), nil)
+
+	// the budget allows 5 retries of 1ms, plus the first attempt.
+	if step.invocationCount != 6 {
+		t.Errorf("The retries did not stop on the exhausted budget: \n expected = %#v, \n actual = %#v", 6, step.invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnCancellationDuringRetryWait(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step := newStepFailedRetryable("step 1", anyErr)
+	input := []SequentialStepConfig[any]{
+		{Step: step, RetryConfigProvider: func() (uint, time.Duration) { return 1, time.Hour }},
+	}
+	clock := workflowtest.NewFakeClock(time.Time{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	res, _ := NewSequential("some-workflow", input, WithClock(clock)).ExecuteAsync(ctx, nil)
+	// the workflow waits before the retry, until the time is advanced.
+	clock.BlockUntil(1)
+	cancel()
+
+	err := <-res
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, anyErr) {
+		t.Errorf("The execution error is not as expected: \n expected = %#v, %#v, \n actual = %#v", context.Canceled, anyErr, err)
+	}
+	if step.invocationCount != 1 {
+		t.Errorf("The step was retried after the cancellation: \n invocation count = %#v", step.invocationCount)
+	}
+}
+
+func TestDynamicSequentialExecuteBehaviourOnStepsProvider(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	steps := map[string]*stepMock{
+		"region-eu": newStepSuccessful("region-eu"),
+		"region-us": newStepFailedNonRetryable("region-us", anyErr),
+		"region-ap": newStepSuccessful("region-ap"),
+	}
+	provider := func(ctx context.Context, req any) []SequentialStepConfig[any] {
+		var stepsCfg []SequentialStepConfig[any]
+		for _, region := range req.([]string) {
+			stepsCfg = append(stepsCfg, SequentialStepConfig[any]{Step: steps["region-"+region]})
+		}
+		return stepsCfg
+	}
+	c := NewDynamicSequential("some-workflow", provider, WithStorage(repo), WithCorrelationID("id-1"))
+
+	err := c.Execute(context.TODO(), []string{"eu", "us"})
+	if !errors.Is(err, anyErr) {
+		t.Fatalf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+	// the replay skips the succeeded dynamic step.
+	steps["region-us"].execute = nil
+	if err = c.Execute(context.TODO(), []string{"eu", "us"}); err != nil {
+		t.Fatalf("The replay returned an unexpected error: %v", err)
+	}
+
+	expectedOutput := map[string]int{"region-eu": 1, "region-us": 2, "region-ap": 0}
+	for name, expected := range expectedOutput {
+		if steps[name].invocationCount != expected {
+			t.Errorf("The step %s invocation count is not as expected: \n expected = %#v, \n actual = %#v", name, expected, steps[name].invocationCount)
+		}
+	}
+}
+
+func TestSequentialExecuteBehaviourOnFlushingLogger(t *testing.T) {
+	log := &flushingLoggerMock{}
+	input := []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}}
+
+	NewSequential("some-workflow", input, WithLogger(log)).Execute(context.TODO(), nil)
+
+	// the log is flushed once, after the end of the execution is logged.
+	if len(log.flushed) != 1 {
+		t.Fatalf("The number of flushes is not as expected: \n expected = %#v, \n actual = %#v", 1, len(log.flushed))
+	}
+	flushed := log.flushed[0]
+	if expectedOutput := "[DONE] executing workflow: some-workflow"; flushed[len(flushed)-1] != expectedOutput {
+		t.Errorf("The last message logged before the flush is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, flushed)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnLoggingStepDuration(t *testing.T) {
+	anyErr := errors.New("any-err")
+	clock := workflowtest.NewFakeClock(time.Time{})
+	input := []SequentialStepConfig[any]{
+		{Step: stepFuncMock(func(ctx context.Context, request any) error {
+			clock.Advance(42 * time.Millisecond)
+			return nil
+		})},
+		{Step: stepFuncMock(func(ctx context.Context, request any) error {
+			clock.Advance(1500 * time.Millisecond)
+			return anyErr
+		})},
+	}
+	log := &loggerMock{}
+
+	NewSequential("some-workflow", input, WithLogger(log), WithClock(clock)).Execute(context.TODO(), nil)
+
+	expectedOutput := succeed + " executing step: step-func in 42ms"
+	if !contains(log.info, expectedOutput) {
+		t.Errorf("The success log doesn't hold the step duration: \n expected = %#v, \n actual = %#v", expectedOutput, log.info)
+	}
+	expectedOutput = failed + " executing step: step-func in 1500ms, err: any-err"
+	if !contains(log.error, expectedOutput) {
+		t.Errorf("The failure log doesn't hold the step duration: \n expected = %#v, \n actual = %#v", expectedOutput, log.error)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnLoggingLevels(t *testing.T) {
+	anyErr := errors.New("any-err")
+	input := []SequentialStepConfig[any]{
+		{Step: newStepFailedRetryableRecoverable("step 1", anyErr, 2), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+	retryMsgs := []string{
+		"step: step 1 is configured to retry, retry attempt count: 1",
+		"waiting for: 0ms before retry attempt",
+	}
+	failureMsgs := []string{failed + " executing step: step 1 in 0ms, err: any-err"}
+
+	// a Logger implementing the optional interfaces receives the retry messages at Debug, and the recoverable failure at Warn.
+	log := &leveledLoggerMock{}
+	// the time moves only by the retry delays, so the step attempts take 0ms.
+	clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+	NewSequential("some-workflow", input, WithLogger(log), WithClock(clock)).Execute(context.TODO(), nil)
+	if !reflect.DeepEqual(log.debug, retryMsgs) {
+		t.Errorf("The debug logs are not as expected: \n expected = %#v, \n actual = %#v", retryMsgs, log.debug)
+	}
+	if !reflect.DeepEqual(log.warn, failureMsgs) || len(log.error) != 0 {
+		t.Errorf("The warn and error logs are not as expected: \n expected = %#v, \n actual = %#v, %#v", failureMsgs, log.warn, log.error)
+	}
+
+	// a Logger not implementing them receives the Debug messages at Info, and the Warn ones at Error.
+	input[0].Step = newStepFailedRetryableRecoverable("step 1", anyErr, 2)
+	fallbackLog := &loggerMock{}
+	NewSequential("some-workflow", input, WithLogger(fallbackLog), WithClock(clock)).Execute(context.TODO(), nil)
+	for _, msg := range retryMsgs {
+		if !contains(fallbackLog.info, msg) {
+			t.Errorf("The info logs do not hold the debug message: \n expected = %#v, \n actual = %#v", msg, fallbackLog.info)
+		}
+	}
+	if !reflect.DeepEqual(fallbackLog.error, failureMsgs) {
+		t.Errorf("The error logs are not as expected: \n expected = %#v, \n actual = %#v", failureMsgs, fallbackLog.error)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnErrorRetryDecider(t *testing.T) {
+	errUnavailable := errors.New("503 service unavailable")
+	errBadRequest := errors.New("400 bad request")
+	tests := []struct {
+		name           string
+		input          *errorRetryStepMock
+		expectedOutput int // the step invocation count
+	}{
+		{
+			name:           "a step deciding an error is retryable should be retried",
+			input:          &errorRetryStepMock{stepMock: stepMock{name: "step 1", execute: errUnavailable}, retryable: errUnavailable},
+			expectedOutput: 3,
+		},
+		{
+			name:           "a step deciding an error is not retryable should not be retried",
+			input:          &errorRetryStepMock{stepMock: stepMock{name: "step 1", execute: errBadRequest}, retryable: errUnavailable},
+			expectedOutput: 1,
+		},
+		{
+			name: "a step deciding an error is retryable should be retried, even if its CanRetry returns false",
+			input: &errorRetryStepMock{
+				stepMock:  stepMock{name: "step 1", execute: errUnavailable, canRetry: false},
+				retryable: errUnavailable,
+			},
+			expectedOutput: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := []SequentialStepConfig[any]{
+				{Step: tt.input, RetryConfigProvider: defaultRetryConfigProviderTest},
+			}
+
+			NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+			if tt.input.invocationCount != tt.expectedOutput {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, tt.input.invocationCount)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStepError(t *testing.T) {
+	anyErr := errors.New("any-err")
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepFailedRetryable("step 2", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, ContinueWorkflowOnError: true},
+		{Step: newStepFailedNonRetryable("step 3", anyErr)},
+	}
+
+	err := NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+	var stepErr StepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("The workflow error does not wrap a StepError: \n actual = %#v", err)
+	}
+	expectedOutput := StepError{StepName: "step 2", Attempts: 3, Err: anyErr}
+	if stepErr != expectedOutput {
+		t.Errorf("The StepError is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, stepErr)
+	}
+	if !errors.Is(err, anyErr) {
+		t.Errorf("The StepError does not wrap the step error: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+	// every failing step error is wrapped in its own StepError.
+	var stepNames []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if errors.As(e, &stepErr) {
+			stepNames = append(stepNames, stepErr.StepName)
+		}
+	}
+	if !reflect.DeepEqual(stepNames, []string{"step 2", "step 3"}) {
+		t.Errorf("The failing step names are not as expected: \n expected = %#v, \n actual = %#v", []string{"step 2", "step 3"}, stepNames)
+	}
+}
+
+func TestAllErrorsAndFailedSteps(t *testing.T) {
+	anyErr := errors.New("any-err")
+	otherErr := errors.New("other-err")
+	tests := []struct {
+		name                string
+		input               []SequentialStepConfig[any]
+		expectedErrs        []error
+		expectedFailedSteps []string
+	}{
+		{
+			name:                "a succeeding workflow should have no errors",
+			input:               []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}},
+			expectedErrs:        nil,
+			expectedFailedSteps: nil,
+		},
+		{
+			name: "a single failing step should give a single error",
+			input: []SequentialStepConfig[any]{
+				{Step: newStepSuccessful("step 1")},
+				{Step: newStepFailedNonRetryable("step 2", anyErr)},
+			},
+			expectedErrs:        []error{StepError{StepName: "step 2", Attempts: 1, Err: anyErr}},
+			expectedFailedSteps: []string{"step 2"},
+		},
+		{
+			name: "multiple failing steps should give an error for every step, in the failure order",
+			input: []SequentialStepConfig[any]{
+				{Step: newStepFailedNonRetryable("step 1", anyErr), ContinueWorkflowOnError: true},
+				{Step: newStepSuccessful("step 2")},
+				{Step: NewSequential("nested", []SequentialStepConfig[any]{
+					{Step: newStepFailedNonRetryable("nested step 1", otherErr), ContinueWorkflowOnError: true},
+					{Step: newStepFailedNonRetryable("nested step 2", otherErr)},
+				}), ContinueWorkflowOnError: true},
+				{Step: newStepFailedNonRetryable("step 4", otherErr)},
+			},
+			expectedErrs: []error{
+				StepError{StepName: "step 1", Attempts: 1, Err: anyErr},
+				StepError{StepName: "nested", Attempts: 1, Err: errors.Join(
+					StepError{StepName: "nested step 1", Attempts: 1, Err: otherErr},
+					StepError{StepName: "nested step 2", Attempts: 1, Err: otherErr},
+				)},
+				StepError{StepName: "step 4", Attempts: 1, Err: otherErr},
+			},
+			expectedFailedSteps: []string{"step 1", "nested", "step 4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSequential("some-workflow", tt.input).Execute(context.TODO(), nil)
+
+			if actualErrs := AllErrors(err); !reflect.DeepEqual(actualErrs, tt.expectedErrs) {
+				t.Errorf("The errors are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedErrs, actualErrs)
+			}
+			if actualFailedSteps := FailedSteps(err); !reflect.DeepEqual(actualFailedSteps, tt.expectedFailedSteps) {
+				t.Errorf("The failed steps are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedFailedSteps, actualFailedSteps)
+			}
+		})
+	}
+}
+
+func TestFailedStepPathsOfNestedWorkflows(t *testing.T) {
+	anyErr := errors.New("any-err")
+	// two levels of nesting: ETL -> extract-data -> transform -> get-raw-data-from-db.
+	transform := NewSequential("transform", []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("normalize")},
+		{Step: newStepFailedNonRetryable("get-raw-data-from-db", anyErr)},
+	})
+	extract := NewSequential("extract-data", []SequentialStepConfig[any]{
+		{Step: newStepFailedNonRetryable("read-config", anyErr), ContinueWorkflowOnError: true},
+		{Step: transform},
+	})
+	input := []SequentialStepConfig[any]{
+		{Step: extract, ContinueWorkflowOnError: true},
+		{Step: newStepFailedNonRetryable("load-data", anyErr)},
+	}
+	log := &loggerMock{}
+
+	err := NewSequential("ETL", input, WithLogger(log), WithErrorWrapping()).Execute(context.TODO(), nil)
+
+	expectedOutput := []string{
+		"ETL/extract-data/read-config",
+		"ETL/extract-data/transform/get-raw-data-from-db",
+		"ETL/load-data",
+	}
+	if actualOutput := FailedStepPaths(err); !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The failed step paths are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	// the failure of the nested workflow is logged with the paths of its failing steps.
+	expectedLog := ", failed steps: ETL/extract-data/read-config, ETL/extract-data/transform/get-raw-data-from-db"
+	var logged bool
+	for _, msg := range log.error {
+		logged = logged || strings.HasPrefix(msg, failed+" executing step: extract-data") && strings.HasSuffix(msg, expectedLog)
+	}
+	if !logged {
+		t.Errorf("The nested workflow failure log is not as expected: \n expected suffix = %#v, \n actual = %#v", expectedLog, log.error)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnMaxAttemptsBoundary(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          uint // the max attempts provided by the RetryConfigProvider
+		expectedOutput int  // the step invocations
+	}{
+		{name: "the max attempts equal to the cap should be used as they are", input: MaxRetryAttempts, expectedOutput: int(MaxRetryAttempts) + 1},
+		{name: "the max attempts over the cap should be capped", input: MaxRetryAttempts + 1, expectedOutput: int(MaxRetryAttempts) + 1},
+		{name: "the max uint max attempts should be capped", input: math.MaxUint, expectedOutput: int(MaxRetryAttempts) + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := newStepFailedRetryable("step 1", errors.New("any-err"))
+			input := []SequentialStepConfig[any]{
+				{Step: step, RetryConfigProvider: func() (uint, time.Duration) { return tt.input, 0 }},
+			}
+
+			NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+			if step.invocationCount != tt.expectedOutput {
+				t.Errorf("The step invocations are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, step.invocationCount)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnGlobalRetryBudget(t *testing.T) {
+	anyErr := errors.New("any-err")
+	log := &loggerMock{}
+	retryConfig := func() (uint, time.Duration) { return 3, 0 }
+	step1 := newStepFailedRetryable("step 1", anyErr)
+	step2 := newStepFailedRetryable("step 2", anyErr)
+	input := []SequentialStepConfig[any]{
+		{Step: step1, RetryConfigProvider: retryConfig, ContinueWorkflowOnError: true},
+		{Step: step2, RetryConfigProvider: retryConfig},
+	}
+	c := NewSequential("some-workflow", input, WithGlobalRetryBudget(2), WithLogger(log))
+
+	c.Execute(context.TODO(), nil)
+
+	// the first step spends the whole budget, so the second one is not retried, even if its configuration allows it.
+	if step1.invocationCount != 3 || step2.invocationCount != 1 {
+		t.Errorf("The steps invocations are not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", 3, 1, step1.invocationCount, step2.invocationCount)
+	}
+	expectedOutput := "step: step 2 is not retried, the global retry budget of workflow: some-workflow is spent"
+	if !contains(log.error, expectedOutput) {
+		t.Errorf("The spent budget was not logged: \n expected = %#v, \n actual = %#v", expectedOutput, log.error)
+	}
+
+	// every execution has its own budget.
+	c.Execute(context.TODO(), nil)
+	if step1.invocationCount != 6 {
+		t.Errorf("The budget was not renewed for the next execution: \n expected = %#v, \n actual = %#v", 6, step1.invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnGlobalRetryBudgetWithRefusedRetry(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step1 := newStepFailedRetryable("step 1", anyErr)
+	step2 := newStepFailedRetryable("step 2", anyErr)
+	input := []SequentialStepConfig[any]{
+		{
+			Step:                    step1,
+			RetryConfigProvider:     func() (uint, time.Duration) { return 3, time.Second },
+			RetryBudget:             time.Millisecond,
+			ContinueWorkflowOnError: true,
+		},
+		{Step: step2, RetryConfigProvider: func() (uint, time.Duration) { return 3, 0 }},
+	}
+	clock := workflowtest.NewAutoAdvancingFakeClock(time.Time{})
+
+	NewSequential("some-workflow", input, WithGlobalRetryBudget(1), WithClock(clock)).Execute(context.TODO(), nil)
+
+	// the retry of the first step is refused by its own budget, so the global budget is left for the second step.
+	if step1.invocationCount != 1 || step2.invocationCount != 2 {
+		t.Errorf("The steps invocations are not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", 1, 2, step1.invocationCount, step2.invocationCount)
+	}
+}
+
+func TestSequentialExecuteStepTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          time.Duration // the step Timeout
+		opts           []SequentialOption
+		expectedOutput time.Duration // the timeout of the step ctx, 0 meaning no deadline
+	}{
+		{
+			name:           "the step Timeout should take precedence over the default step timeout",
+			input:          time.Hour,
+			opts:           []SequentialOption{WithDefaultStepTimeout(time.Minute)},
+			expectedOutput: time.Hour,
+		},
+		{
+			name:           "the default step timeout should apply to a step without Timeout",
+			input:          0,
+			opts:           []SequentialOption{WithDefaultStepTimeout(time.Minute)},
+			expectedOutput: time.Minute,
+		},
+		{
+			name:           "a step without Timeout should have no deadline, if there is no default step timeout",
+			input:          0,
+			opts:           nil,
+			expectedOutput: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+			step := stepFuncMock(func(ctx context.Context, request any) error {
+				deadline, hasDeadline = ctx.Deadline()
+				return nil
+			})
+			wf := NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: step, Timeout: tt.input}}, tt.opts...)
+
+			before := time.Now()
+			err := wf.Execute(context.Background(), nil)
+			after := time.Now()
+
+			if err != nil {
+				t.Fatalf("The workflow failed: %v", err)
+			}
+			if hasDeadline != (tt.expectedOutput > 0) {
+				t.Fatalf("The step ctx deadline presence is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput > 0, hasDeadline)
+			}
+			if hasDeadline && (deadline.Before(before.Add(tt.expectedOutput)) || deadline.After(after.Add(tt.expectedOutput))) {
+				t.Errorf("The step ctx deadline is not as expected: \n expected = %v + %v, \n actual = %v", before, tt.expectedOutput, deadline)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteStepTimeoutPerAttempt(t *testing.T) {
+	var invocationCount int
+	step := retryableStepFuncMock(func(ctx context.Context, request any) error {
+		invocationCount++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	input := []SequentialStepConfig[any]{{
+		Step:                step,
+		Timeout:             time.Millisecond,
+		RetryConfigProvider: func() (uint, time.Duration) { return 2, 0 },
+	}}
+
+	err := NewSequential("some-workflow", input).Execute(context.Background(), nil)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", context.DeadlineExceeded, err)
+	}
+	if invocationCount != 3 {
+		t.Errorf("Every attempt should have its own timeout: \n expected invocations = %#v, \n actual = %#v", 3, invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnTimeoutHook(t *testing.T) {
+	var timeouts []string
+	var attempts []uint
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1"), Timeout: time.Second},
+		{
+			Step: retryableStepFuncMock(func(ctx context.Context, request any) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+			Timeout:             time.Millisecond,
+			RetryConfigProvider: func() (uint, time.Duration) { return 1, 0 },
+		},
+	}
+	onTimeout := func(ctx context.Context, stepName string, attempt uint) {
+		timeouts = append(timeouts, stepName)
+		attempts = append(attempts, attempt)
+	}
+
+	NewSequential("some-workflow", input, WithOnTimeout(onTimeout)).Execute(context.Background(), nil)
+
+	expectedOutput := []string{"retryable-step-func", "retryable-step-func"}
+	if !reflect.DeepEqual(timeouts, expectedOutput) {
+		t.Errorf("The timeout hook calls are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, timeouts)
+	}
+	if expectedAttempts := []uint{0, 1}; !reflect.DeepEqual(attempts, expectedAttempts) {
+		t.Errorf("The timeout hook attempts are not as expected: \n expected = %#v, \n actual = %#v", expectedAttempts, attempts)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnRetryIf(t *testing.T) {
+	anyErr, fatalErr := errors.New("any-err"), errors.New("fatal-err")
+	tests := []struct {
+		name                    string
+		input                   []error // the errors returned by the step attempts, the step succeeding after them
+		retryConfigProvider     func() (uint, time.Duration)
+		expectedInvocationCount int
+		expectedAttempts        []uint // the attempts RetryIf is called with
+	}{
+		{
+			name:                    "a step not implementing RetryDecider should be retried, as decided by RetryIf",
+			input:                   []error{anyErr, anyErr},
+			retryConfigProvider:     defaultRetryConfigProviderTest,
+			expectedInvocationCount: 3,
+			expectedAttempts:        []uint{0, 1},
+		},
+		{
+			name:                    "a step should not be retried, once RetryIf returns false",
+			input:                   []error{anyErr, fatalErr, anyErr},
+			retryConfigProvider:     defaultRetryConfigProviderTest,
+			expectedInvocationCount: 2,
+			expectedAttempts:        []uint{0, 1},
+		},
+		{
+			name:                    "RetryIf should be called for the last attempt, without retrying the step",
+			input:                   []error{anyErr, anyErr, anyErr},
+			retryConfigProvider:     defaultRetryConfigProviderTest,
+			expectedInvocationCount: 3,
+			expectedAttempts:        []uint{0, 1, 2},
+		},
+		{
+			name:                    "a step without RetryConfigProvider should not be retried, even with RetryIf",
+			input:                   []error{anyErr},
+			retryConfigProvider:     nil,
+			expectedInvocationCount: 1,
+			expectedAttempts:        []uint{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var invocationCount int
+			step := stepFuncMock(func(ctx context.Context, request any) error {
+				invocationCount++
+				if invocationCount > len(tt.input) {
+					return nil
+				}
+				return tt.input[invocationCount-1]
+			})
+			var attempts []uint
+			retryIf := func(ctx context.Context, err error, attempt uint) bool {
+				attempts = append(attempts, attempt)
+				return !errors.Is(err, fatalErr)
+			}
+			input := []SequentialStepConfig[any]{{Step: step, RetryConfigProvider: tt.retryConfigProvider, RetryIf: retryIf}}
+
+			NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+			if invocationCount != tt.expectedInvocationCount {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedInvocationCount, invocationCount)
+			}
+			if !reflect.DeepEqual(attempts, tt.expectedAttempts) {
+				t.Errorf("The RetryIf attempts are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnRetryIfOverridingRetryDecider(t *testing.T) {
+	step := newStepFailedRetryable("step 1", errors.New("any-err"))
+	input := []SequentialStepConfig[any]{{
+		Step:                step,
+		RetryConfigProvider: defaultRetryConfigProviderTest,
+		RetryIf:             func(ctx context.Context, err error, attempt uint) bool { return false },
+	}}
+
+	NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+	if step.invocationCount != 1 {
+		t.Errorf("The RetryIf did not override the RetryDecider: \n expected = %#v, \n actual = %#v", 1, step.invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnErrorWrapping(t *testing.T) {
+	anyErr := errors.New("any-err")
+	otherErr := errors.New("other-err")
+	tests := []struct {
+		name                string
+		input               []SequentialStepConfig[any]
+		expectedErrs        []error
+		expectedFailedSteps []string
+	}{
+		{
+			name:                "a single step error should be wrapped",
+			input:               []SequentialStepConfig[any]{{Step: newStepFailedNonRetryable("step 1", anyErr)}},
+			expectedErrs:        []error{anyErr},
+			expectedFailedSteps: []string{"step 1"},
+		},
+		{
+			name: "the joined steps errors should be wrapped once",
+			input: []SequentialStepConfig[any]{
+				{Step: newStepFailedNonRetryable("step 1", anyErr), ContinueWorkflowOnError: true},
+				{Step: newStepFailedNonRetryable("step 2", otherErr)},
+			},
+			expectedErrs:        []error{anyErr, otherErr},
+			expectedFailedSteps: []string{"step 1", "step 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSequential("some-workflow", tt.input, WithErrorWrapping()).Execute(context.TODO(), nil)
+
+			var wfErr WorkflowError
+			if !errors.As(err, &wfErr) || wfErr.WorkflowName != "some-workflow" {
+				t.Fatalf("The workflow error is not wrapped: \n actual = %#v", err)
+			}
+			if !strings.HasPrefix(err.Error(), "workflow: some-workflow, err: ") {
+				t.Errorf("The workflow error message is not as expected: \n actual = %#v", err.Error())
+			}
+			for _, expectedErr := range tt.expectedErrs {
+				if !errors.Is(err, expectedErr) {
+					t.Errorf("The workflow error does not wrap the step error: \n expected = %#v, \n actual = %#v", expectedErr, err)
+				}
+			}
+			if !reflect.DeepEqual(FailedSteps(err), tt.expectedFailedSteps) {
+				t.Errorf("The failed steps are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedFailedSteps, FailedSteps(err))
+			}
+		})
+	}
+
+	// a succeeded workflow returns no error.
+	err := NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}}, WithErrorWrapping()).
+		Execute(context.TODO(), nil)
+	if err != nil {
+		t.Errorf("The workflow returned an unexpected error: %v", err)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnEndHooks(t *testing.T) {
+	anyErr := errors.New("any-err")
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		input          []SequentialStepConfig[any]
+		expectedOutput error // the error passed to the hooks
+	}{
+		{
+			name:           "the hooks should receive a nil error on success",
+			ctx:            context.TODO(),
+			input:          []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}},
+			expectedOutput: nil,
+		},
+		{
+			name: "the hooks should receive the error of a step stopping the workflow",
+			ctx:  context.TODO(),
+			input: []SequentialStepConfig[any]{
+				{Step: newStepFailedNonRetryable("step 1", anyErr)},
+				{Step: newStepSuccessful("step 2")},
+			},
+			expectedOutput: anyErr,
+		},
+		{
+			name:           "the hooks should receive the ctx error of a workflow stopped before any step",
+			ctx:            cancelledCtx,
+			input:          []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}},
+			expectedOutput: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var completed, failed []error
+			wf := NewSequential(
+				"some-workflow",
+				tt.input,
+				WithOnComplete(func(ctx context.Context, err error) { completed = append(completed, err) }),
+				WithOnError(func(ctx context.Context, err error) { failed = append(failed, err) }),
+			)
+
+			err := wf.Execute(tt.ctx, nil)
+
+			if len(completed) != 1 || completed[0] != err || !errors.Is(err, tt.expectedOutput) {
+				t.Errorf("The OnComplete calls are not as expected: \n expected = %#v, \n actual = %#v", []error{err}, completed)
+			}
+			expectedFailed := 0
+			if tt.expectedOutput != nil {
+				expectedFailed = 1
+			}
+			if len(failed) != expectedFailed || (expectedFailed == 1 && failed[0] != err) {
+				t.Errorf("The OnError calls are not as expected: \n expected = %#v, \n actual = %#v", expectedFailed, failed)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnRequestFunc(t *testing.T) {
+	type request struct {
+		user  string
+		order string
+	}
+	var actualOutput []any
+	record := stepFuncMock(func(ctx context.Context, req any) error {
+		actualOutput = append(actualOutput, req)
+
+		return nil
+	})
+	input := []SequentialStepConfig[any]{
+		{
+			Step:         record,
+			NameOverride: "step 1",
+			RequestFunc:  func(ctx context.Context, req any) any { return req.(request).user },
+		},
+		{Step: record, NameOverride: "step 2"},
+	}
+
+	NewSequential("some-workflow", input).Execute(context.TODO(), request{user: "some-user", order: "some-order"})
+
+	expectedOutput := []any{"some-user", request{user: "some-user", order: "some-order"}}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps requests are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStepLogger(t *testing.T) {
+	anyErr := errors.New("any-err")
+	workflowLog := &loggerMock{}
+	stepLog := &loggerMock{}
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepFailedNonRetryable("step 2", anyErr), ContinueWorkflowOnError: true, Logger: stepLog},
+	}
+
+	NewSequential("some-workflow", input, WithLogger(workflowLog)).Execute(context.TODO(), nil)
+
+	stepMsgs := append(stepLog.info, stepLog.error...)
+	if len(stepMsgs) == 0 {
+		t.Fatalf("The step logger received no message")
+	}
+	for _, msg := range stepMsgs {
+		if !strings.Contains(msg, "step 2") {
+			t.Errorf("The step logger received a message of another step: \n actual = %#v", msg)
+		}
+	}
+	if containsSubstr(append(workflowLog.info, workflowLog.error...), "step: step 2") {
+		t.Errorf("The workflow logger received the messages of the step having its own logger: \n actual = %#v, %#v", workflowLog.info, workflowLog.error)
+	}
+	if !containsSubstr(workflowLog.info, "step: step 1") {
+		t.Errorf("The workflow logger didn't receive the messages of the other steps: \n actual = %#v", workflowLog.info)
+	}
+
+	// the compensation of a step is logged by the step logger as well.
+	var compensated []string
+	workflowLog, stepLog = &loggerMock{}, &loggerMock{}
+	input = []SequentialStepConfig[any]{
+		{Step: &compensableStepMock{stepMock: stepMock{name: "step 1"}, compensated: &compensated}, Logger: stepLog},
+		{Step: newStepFailedNonRetryable("step 2", anyErr)},
+	}
+
+	NewSequential("some-workflow", input, WithLogger(workflowLog), WithCompensation()).Execute(context.TODO(), nil)
+
+	expectedOutput := succeed + " compensating step: step 1"
+	if !contains(stepLog.info, expectedOutput) || contains(workflowLog.info, expectedOutput) {
+		t.Errorf("The compensation was not logged by the step logger: \n expected = %#v, \n actual = %#v", expectedOutput, stepLog.info)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnRetryHook(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var attempts []uint
+	var delays []time.Duration
+	input := []SequentialStepConfig[any]{
+		{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+		{Step: newStepSuccessful("step 2"), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+	onRetry := func(ctx context.Context, stepName string, attempt uint, err error, nextDelay time.Duration) {
+		if stepName != "step 1" || !errors.Is(err, anyErr) {
+			t.Errorf("The retry hook arguments are not as expected: \n actual = %#v, %#v", stepName, err)
+		}
+		attempts = append(attempts, attempt)
+		delays = append(delays, nextDelay)
+	}
+
+	NewSequential("some-workflow", input, WithOnRetry(onRetry)).Execute(context.TODO(), nil)
+
+	expectedOutput := []uint{1, 2}
+	if !reflect.DeepEqual(attempts, expectedOutput) {
+		t.Errorf("The retry hook calls are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, attempts)
+	}
+	expectedDelays := []time.Duration{time.Nanosecond, time.Nanosecond}
+	if !reflect.DeepEqual(delays, expectedDelays) {
+		t.Errorf("The retry hook delays are not as expected: \n expected = %#v, \n actual = %#v", expectedDelays, delays)
+	}
+}
+
 // BENCHMARKS
 
 // BenchmarkSequentialHappyFlow performs a benchmark for the scenario in which there is no error in the workflow.
@@ -243,6 +1315,40 @@ func BenchmarkSequentialExecuteErrFlowMultipleErr(b *testing.B) {
 	}
 }
 
+// BenchmarkSequentialExecuteNewPerRequest performs a benchmark for the scenario in which a workflow with a storage is
+// built for every request, to set its correlation ID.
+func BenchmarkSequentialExecuteNewPerRequest(b *testing.B) {
+	stepsCfg := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("extract-data-from-data-provider")},
+		{Step: newStepSuccessful("load-the-data-into-the-data-source")},
+	}
+	ids := []string{"id-1", "id-2", "id-3"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := NewSequential("just-execute-these-steps-workflow", stepsCfg, WithStorage(nopStorage{}), WithCorrelationID(ids[i%len(ids)]))
+		s.Execute(context.TODO(), nil)
+	}
+}
+
+// BenchmarkSequentialExecuteReused performs a benchmark for the scenario in which a single workflow with a storage is
+// reused for every request, by setting its correlation ID.
+// This should produce 0 allocations.
+func BenchmarkSequentialExecuteReused(b *testing.B) {
+	stepsCfg := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("extract-data-from-data-provider")},
+		{Step: newStepSuccessful("load-the-data-into-the-data-source")},
+	}
+	ids := []string{"id-1", "id-2", "id-3"}
+	s := NewSequential("just-execute-these-steps-workflow", stepsCfg, WithStorage(nopStorage{}))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.SetCorrelationID(ids[i%len(ids)])
+		s.Execute(context.TODO(), nil)
+	}
+}
+
 // MOCKS/STUBS
 type stepMock struct {
 	invocationCount                      int
@@ -288,3 +1394,109 @@ func newStepFailedRetryableRecoverable(name string, failWith error, succeedsAtIn
 func newStepFailedNonRetryable(name string, failWith error) *stepMock {
 	return &stepMock{name: name, execute: failWith, canRetry: false}
 }
+
+type stepFuncMock func(ctx context.Context, request any) error
+
+func (f stepFuncMock) Name() string {
+	return "step-func"
+}
+
+func (f stepFuncMock) Execute(ctx context.Context, request any) error {
+	return f(ctx, request)
+}
+
+// retryableStepFuncMock is a stepFuncMock which can be retried.
+type retryableStepFuncMock func(ctx context.Context, request any) error
+
+func (f retryableStepFuncMock) Name() string {
+	return "retryable-step-func"
+}
+
+func (f retryableStepFuncMock) Execute(ctx context.Context, request any) error {
+	return f(ctx, request)
+}
+
+func (f retryableStepFuncMock) CanRetry() bool {
+	return true
+}
+
+// errorRetryStepMock is a stepMock deciding the retry based on the error of the last attempt.
+type errorRetryStepMock struct {
+	stepMock
+	retryable error
+}
+
+func (c *errorRetryStepMock) RetryableError(err error) bool {
+	return errors.Is(err, c.retryable)
+}
+
+// leveledLoggerMock is a loggerMock implementing the DebugLogger and WarnLogger interfaces.
+type leveledLoggerMock struct {
+	loggerMock
+	debug []string
+	warn  []string
+}
+
+func (l *leveledLoggerMock) Debug(msg string) {
+	l.debug = append(l.debug, strings.Clone(msg))
+}
+
+func (l *leveledLoggerMock) Warn(msg string) {
+	l.warn = append(l.warn, strings.Clone(msg))
+}
+
+// flushingLoggerMock is a Logger implementing the Flusher interface, which records the logged messages at every flush.
+type flushingLoggerMock struct {
+	loggerMock
+	flushed [][]string
+}
+
+func (l *flushingLoggerMock) Flush() error {
+	l.flushed = append(l.flushed, l.info)
+
+	return nil
+}
+
+func contains(msgs []string, msg string) bool {
+	for _, m := range msgs {
+		if m == msg {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsSubstr reports whether any of the msgs contains the substr.
+func containsSubstr(msgs []string, substr string) bool {
+	for _, m := range msgs {
+		if strings.Contains(m, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nopStorage is a Storage holding nothing, so it produces no allocations.
+type nopStorage struct{}
+
+func (nopStorage) Save(_ context.Context, _, _ string, _ StepStatus, _ *string) error {
+	return nil
+}
+
+func (nopStorage) Get(_ context.Context, _, _ string) (StepStatus, error) {
+	return "", nil
+}
+
+func (nopStorage) Clear(_ context.Context, _ string) error {
+	return nil
+}
+
+func (nopStorage) SaveValue(_ context.Context, _, _ string, _ []byte) error {
+	return nil
+}
+
+func (nopStorage) GetValue(_ context.Context, _, _ string) ([]byte, error) {
+	return nil, nil
+}
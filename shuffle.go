@@ -0,0 +1,49 @@
+package workflow
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+)
+
+// WithShuffleSteps runs the steps of every contiguous run of the steps configured with
+// SequentialStepConfig.ContinueWorkflowOnError in a random order, e.g. as a chaos testing aid surfacing the hidden
+// ordering dependencies among the steps assumed independent. The other steps keep their position, as the steps stopping
+// the workflow on error are the ones the next steps depend on. The order is drawn from the seed, so every execution runs
+// the steps in the same order, and a failing order can be reproduced. The errors of the failing steps are returned in
+// the execution order. A warning is logged at the start of every execution, as it's not meant for production.
+// It has no effect on the DAG and Parallel workflows.
+func WithShuffleSteps(seed int64) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.shuffleSeed, o.shuffle = seed, true
+	}
+}
+
+// shuffled returns a copy of the stepsCfg with the runs of the continue on error steps shuffled, as described by
+// WithShuffleSteps, or the stepsCfg if the shuffling is not enabled.
+func (s *Sequential[T]) shuffled(ctx context.Context, stepsCfg []SequentialStepConfig[T]) []SequentialStepConfig[T] {
+	if !s.shuffle {
+		return stepsCfg
+	}
+	s.log.print(
+		ctx,
+		LevelWarn,
+		concatStr("the steps order of workflow: ", s.name, " is shuffled, with the seed: ", strconv.FormatInt(s.shuffleSeed, 10)),
+	)
+	rnd := rand.New(rand.NewSource(s.shuffleSeed))
+	out := append([]SequentialStepConfig[T](nil), stepsCfg...)
+	for i := 0; i < len(out); i++ {
+		n := 0
+		for _, stepCfg := range out[i:] {
+			if !stepCfg.ContinueWorkflowOnError {
+				break
+			}
+			n++
+		}
+		run := out[i : i+n]
+		rnd.Shuffle(len(run), func(a, b int) { run[a], run[b] = run[b], run[a] })
+		i += n
+	}
+
+	return out
+}
@@ -0,0 +1,60 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnShuffleSteps(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var order []string
+	newStep := func(name string, err error) *dagStepMock {
+		return &dagStepMock{name: name, fn: func(ctx context.Context) error {
+			order = append(order, name)
+
+			return err
+		}}
+	}
+	input := []SequentialStepConfig[any]{
+		{Step: newStep("step 1", nil)},
+		{Step: newStep("step 2", anyErr), ContinueWorkflowOnError: true},
+		{Step: newStep("step 3", nil), ContinueWorkflowOnError: true},
+		{Step: newStep("step 4", anyErr), ContinueWorkflowOnError: true},
+		{Step: newStep("step 5", nil), ContinueWorkflowOnError: true},
+		{Step: newStep("step 6", nil)},
+	}
+	log := &leveledLoggerMock{}
+	wf := NewSequential("some-workflow", input, WithShuffleSteps(42), WithLogger(log))
+
+	var orders [][]string
+	for i := 0; i < 2; i++ {
+		order = nil
+		err := wf.Execute(context.TODO(), nil)
+		if !errors.Is(err, anyErr) {
+			t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+		}
+		orders = append(orders, order)
+	}
+
+	// the same seed gives the same order, for every execution.
+	if !reflect.DeepEqual(orders[0], orders[1]) {
+		t.Errorf("The steps order is not deterministic: \n actual = %#v, %#v", orders[0], orders[1])
+	}
+	// the steps stopping the workflow on error keep their position, and all the steps run.
+	actualOutput := orders[0]
+	if len(actualOutput) != 6 || actualOutput[0] != "step 1" || actualOutput[5] != "step 6" {
+		t.Fatalf("The steps order is not as expected: \n actual = %#v", actualOutput)
+	}
+	shuffled := append([]string(nil), actualOutput[1:5]...)
+	sort.Strings(shuffled)
+	expectedOutput := []string{"step 2", "step 3", "step 4", "step 5"}
+	if !reflect.DeepEqual(shuffled, expectedOutput) {
+		t.Errorf("The shuffled steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput[1:5])
+	}
+	if !containsSubstr(log.warn, "is shuffled, with the seed: 42") {
+		t.Errorf("The shuffling warning is not logged: \n actual = %#v", log.warn)
+	}
+}
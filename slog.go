@@ -0,0 +1,64 @@
+//go:build go1.21
+
+package workflow
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogLogger adapts a *slog.Logger to the StructuredLogger interface.
+type SlogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger is the SlogLogger constructor.
+// If nil is provided, the slog.Default() logger is used.
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	if l == nil {
+		l = slog.Default()
+	}
+
+	return &SlogLogger{l: l}
+}
+
+// Debug is the Debug level log.
+func (s *SlogLogger) Debug(msg string) {
+	s.l.Debug(msg)
+}
+
+// Info is the Info level log.
+func (s *SlogLogger) Info(msg string) {
+	s.l.Info(msg)
+}
+
+// Warn is the Warn level log.
+func (s *SlogLogger) Warn(msg string) {
+	s.l.Warn(msg)
+}
+
+// Error is the Error level log.
+func (s *SlogLogger) Error(msg string) {
+	s.l.Error(msg)
+}
+
+// Log implements the StructuredLogger interface, by converting the fields to slog attributes.
+func (s *SlogLogger) Log(ctx context.Context, level Level, msg string, fields ...Field) {
+	lvl := slog.LevelInfo
+	switch level {
+	case LevelDebug:
+		lvl = slog.LevelDebug
+	case LevelWarn:
+		lvl = slog.LevelWarn
+	case LevelError:
+		lvl = slog.LevelError
+	}
+	if !s.l.Enabled(ctx, lvl) {
+		return
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	s.l.LogAttrs(ctx, lvl, msg, attrs...)
+}
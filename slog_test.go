@@ -0,0 +1,104 @@
+//go:build go1.21
+
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/silviutanasa/workflow/workflowtest"
+)
+
+func TestSlogLoggerReceivesStructuredFields(t *testing.T) {
+	anyErr := errors.New("any-err")
+	var buf bytes.Buffer
+	log := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	input := []SequentialStepConfig[any]{
+		{Step: newStepFailedRetryableRecoverable("step 1", anyErr, 2), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+
+	NewSequential("some-workflow", input, WithLogger(log)).Execute(context.TODO(), nil)
+
+	type record struct {
+		Level    string `json:"level"`
+		Msg      string `json:"msg"`
+		Workflow string `json:"workflow"`
+		Step     string `json:"step"`
+		Attempt  *uint  `json:"attempt"`
+	}
+	var records []record
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("The slog output is not valid JSON: %v", err)
+		}
+		records = append(records, r)
+	}
+
+	var stepRecords, warnRecords, errorRecords int
+	for _, r := range records {
+		if r.Workflow != "some-workflow" {
+			t.Errorf("The workflow field is not as expected: \n expected = %#v, \n actual = %#v", "some-workflow", r.Workflow)
+		}
+		if r.Step == "" {
+			continue
+		}
+		stepRecords++
+		if r.Step != "step 1" || r.Attempt == nil {
+			t.Errorf("The step fields are not as expected: \n actual = %#v", r)
+		}
+		switch r.Level {
+		case slog.LevelWarn.String():
+			warnRecords++
+		case slog.LevelError.String():
+			errorRecords++
+		}
+	}
+	if stepRecords == 0 {
+		t.Errorf("No log record has the step fields")
+	}
+	// the failure is recovered by the retry, so it's a warning.
+	if warnRecords != 1 || errorRecords != 0 {
+		t.Errorf("The number of warn and error records is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", 1, 0, warnRecords, errorRecords)
+	}
+}
+
+func TestSlogLoggerReceivesStepDuration(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	clock := workflowtest.NewFakeClock(time.Time{})
+	step := stepFuncMock(func(ctx context.Context, request any) error {
+		clock.Advance(42 * time.Millisecond)
+		return nil
+	})
+
+	NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: step}}, WithLogger(log), WithClock(clock)).Execute(context.TODO(), nil)
+
+	type record struct {
+		Msg      string         `json:"msg"`
+		Duration *time.Duration `json:"duration"`
+	}
+	var actualOutput *record
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("The slog output is not valid JSON: %v", err)
+		}
+		if r.Duration != nil {
+			actualOutput = &r
+		}
+	}
+	// the duration is a field, so it's not repeated in the message.
+	expectedOutput := record{Msg: succeed + " executing step: step-func", Duration: new(time.Duration)}
+	*expectedOutput.Duration = 42 * time.Millisecond
+	if actualOutput == nil || actualOutput.Msg != expectedOutput.Msg || *actualOutput.Duration != *expectedOutput.Duration {
+		t.Errorf("The step duration record is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
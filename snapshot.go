@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// errSnapshotStorage is returned by Snapshot, for a workflow without a storage and a correlation ID.
+var errSnapshotStorage = errors.New("the snapshot requires a storage and a correlation ID")
+
+// workflowSnapshot is the progress of a workflow execution, as encoded by Snapshot.
+type workflowSnapshot struct {
+	Workflow      string         `json:"workflow"`
+	CorrelationID string         `json:"correlation_id"`
+	Steps         []stepSnapshot `json:"steps"`
+}
+
+// stepSnapshot is the stored result of a step, identified by its storage key.
+type stepSnapshot struct {
+	Key    string     `json:"key"`
+	Status StepStatus `json:"status"`
+	Output *string    `json:"output,omitempty"`
+}
+
+// Snapshot returns the progress of the workflow execution for the correlation ID, encoded as JSON: the stored results
+// of the configured steps, read from the storage. It lets the progress be checkpointed in a single file or blob, e.g.
+// after a failed execution, using a MemoryStorage, instead of a Storage persisting every step result, and be resumed
+// by NewSequentialFromSnapshot. It fails without a storage and a correlation ID.
+// A succeeded workflow has no progress, as its stored results are cleared. The steps built by the steps provider of a
+// dynamic workflow are not part of the snapshot.
+func (s *Sequential[T]) Snapshot(ctx context.Context) ([]byte, error) {
+	id, ok := s.store.id(ctx)
+	if !ok {
+		return nil, errSnapshotStorage
+	}
+	snapshot := workflowSnapshot{Workflow: s.name, CorrelationID: id, Steps: make([]stepSnapshot, 0, len(s.stepsConfig))}
+	for _, stepCfg := range s.stepsConfig {
+		if stepCfg.Step == nil {
+			continue
+		}
+		key := s.store.key(stepCfg.storageKey())
+		res, err := s.store.getResult(ctx, key, id)
+		if err != nil {
+			return nil, fmt.Errorf("error getting step execution result: %w", err)
+		}
+		if res.Status != "" {
+			snapshot.Steps = append(snapshot.Steps, stepSnapshot{Key: key, Status: res.Status, Output: res.Output})
+		}
+	}
+
+	return json.Marshal(snapshot)
+}
+
+// NewSequentialFromSnapshot is the NewSequential version resuming the execution whose progress is held by the snapshot
+// returned by Snapshot: the workflow stores the steps results in a MemoryStorage holding the progress, for the
+// correlation ID of the snapshot, so the steps which already succeeded are skipped. The storage and the correlation ID
+// take precedence over the ones set by the opts, including WithCorrelationIDFromContext. It fails if the snapshot is not
+// valid, or is the one of another workflow, by name.
+func NewSequentialFromSnapshot[T any](
+	name string,
+	stepsCfg []SequentialStepConfig[T],
+	snapshot []byte,
+	opts ...SequentialOption,
+) (*Sequential[T], error) {
+	var progress workflowSnapshot
+	if err := json.Unmarshal(snapshot, &progress); err != nil {
+		return nil, fmt.Errorf("error decoding the workflow snapshot: %w", err)
+	}
+	if progress.Workflow != name || progress.CorrelationID == "" {
+		return nil, fmt.Errorf(
+			"the snapshot of workflow: %s, for the correlation ID: %s, can't resume workflow: %s",
+			progress.Workflow,
+			progress.CorrelationID,
+			name,
+		)
+	}
+	storage := NewMemoryStorage()
+	for _, step := range progress.Steps {
+		_ = storage.Save(context.Background(), step.Key, progress.CorrelationID, step.Status, step.Output)
+	}
+	opts = append(
+		opts[:len(opts):len(opts)],
+		WithStorage(storage),
+		WithCorrelationID(progress.CorrelationID),
+		WithCorrelationIDFromContext(nil),
+	)
+
+	return NewSequential(name, stepsCfg, opts...), nil
+}
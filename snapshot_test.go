@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSequentialSnapshotRoundTrip(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step1 := newStepSuccessful("step 1")
+	step2 := newStepFailedNonRetryable("step 2", anyErr)
+	step3 := newStepSuccessful("step 3")
+	input := []SequentialStepConfig[any]{{Step: step1}, {Step: step2}, {Step: step3}}
+	wf := NewSequential("some-workflow", input, WithStorage(NewMemoryStorage()), WithCorrelationID("id-1"))
+	wf.Execute(context.TODO(), nil)
+
+	snapshot, err := wf.Snapshot(context.TODO())
+	if err != nil {
+		t.Fatalf("The snapshot returned an unexpected error: %v", err)
+	}
+	var actualOutput workflowSnapshot
+	if err = json.Unmarshal(snapshot, &actualOutput); err != nil {
+		t.Fatalf("The snapshot is not valid JSON: %v", err)
+	}
+	failure := "any-err"
+	expectedOutput := workflowSnapshot{
+		Workflow:      "some-workflow",
+		CorrelationID: "id-1",
+		Steps: []stepSnapshot{
+			{Key: "step 1", Status: StepStatusSuccess},
+			{Key: "step 2", Status: StepStatusPermanentlyFailed, Output: &failure},
+		},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The snapshot is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+
+	// the resumed workflow skips the succeeded step, and runs the others.
+	step2.execute = nil
+	resumed, err := NewSequentialFromSnapshot("some-workflow", input, snapshot)
+	if err != nil {
+		t.Fatalf("The restore returned an unexpected error: %v", err)
+	}
+	err = resumed.Execute(context.TODO(), nil)
+
+	if err != nil || step1.invocationCount != 1 || step2.invocationCount != 2 || step3.invocationCount != 1 {
+		t.Errorf("The resumed execution is not as expected: \n actual = %#v, %#v, %#v, %#v", err, step1.invocationCount, step2.invocationCount, step3.invocationCount)
+	}
+	// the succeeded workflow has no progress left.
+	snapshot, _ = resumed.Snapshot(context.TODO())
+	if err = json.Unmarshal(snapshot, &actualOutput); err != nil || len(actualOutput.Steps) != 0 {
+		t.Errorf("The snapshot of the succeeded workflow is not as expected: \n actual = %s", snapshot)
+	}
+}
+
+func TestSequentialSnapshotBehaviourOnInvalidInput(t *testing.T) {
+	if _, err := NewSequential[any]("some-workflow", nil).Snapshot(context.TODO()); err == nil {
+		t.Errorf("The snapshot without a storage should fail")
+	}
+
+	tests := []struct {
+		name           string
+		input          string // the snapshot
+		expectedOutput string // a substring of the error
+	}{
+		{name: "a snapshot which is not JSON should fail", input: "{", expectedOutput: "error decoding the workflow snapshot"},
+		{
+			name:           "the snapshot of another workflow should fail",
+			input:          `{"workflow":"other-workflow","correlation_id":"id-1"}`,
+			expectedOutput: "the snapshot of workflow: other-workflow, for the correlation ID: id-1, can't resume workflow: some-workflow",
+		},
+		{
+			name:           "a snapshot without a correlation ID should fail",
+			input:          `{"workflow":"some-workflow"}`,
+			expectedOutput: "can't resume workflow: some-workflow",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf, err := NewSequentialFromSnapshot[any]("some-workflow", nil, []byte(tt.input))
+
+			if wf != nil || err == nil || !strings.Contains(err.Error(), tt.expectedOutput) {
+				t.Errorf("The restore result is not as expected: \n expected = %#v, \n actual = %#v, %#v", tt.expectedOutput, wf, err)
+			}
+		})
+	}
+}
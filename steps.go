@@ -0,0 +1,101 @@
+package workflow
+
+// StepInfo describes a configured step of a workflow.
+type StepInfo struct {
+	Name string
+	// Retryable reports whether the step implements the RetryDecider or the ErrorRetryDecider interface, or has a
+	// RetryIf, so whether the retry configuration has any effect.
+	Retryable bool
+	// ContinueOnError reports whether the workflow goes on when the step fails.
+	ContinueOnError bool
+	// Nested reports whether the step is itself a workflow, whose steps are described by its own Steps method.
+	Nested bool
+}
+
+// stepsDescriber is implemented by the workflows describing their steps, so they are reported as nested when used as
+// a step of another workflow.
+type stepsDescriber interface {
+	Steps() []StepInfo
+}
+
+// Steps describes the configured steps of the workflow, in the execution order, e.g. to display them.
+// The returned slice is built on every call, so changing it doesn't change the workflow. A nil step is named after its
+// problem, e.g. "the step at index: 1 is nil", as it fails the execution.
+// The steps of a dynamic workflow are built at execution time, so it returns nil for a dynamic workflow.
+func (s *Sequential[T]) Steps() []StepInfo {
+	if s.stepsProvider != nil {
+		return nil
+	}
+	steps := make([]StepInfo, len(s.stepsConfig))
+	for i, stepCfg := range s.stepsConfig {
+		steps[i] = newStepInfo(stepCfg.describedName(i), stepCfg.Step, stepCfg.retryable(), stepCfg.ContinueWorkflowOnError)
+	}
+
+	return steps
+}
+
+// Steps describes the configured steps of the workflow, in the execution order, e.g. to display them, same as
+// Sequential.Steps.
+func (p *Pipe[T]) Steps() []StepInfo {
+	steps := make([]StepInfo, len(p.stepsConfig))
+	for i, stepCfg := range p.stepsConfig {
+		steps[i] = newStepInfo(stepCfg.describedName(i), stepCfg.Step, stepCfg.retryable(), stepCfg.ContinueOnError)
+	}
+
+	return steps
+}
+
+// Steps describes the configured steps of the workflow, in the configuration order, e.g. to display them, same as
+// Sequential.Steps. The steps run at the same time, so none stops the others on error.
+func (p *Parallel[T]) Steps() []StepInfo {
+	stepsCfg := p.runner.stepsConfig
+	steps := make([]StepInfo, len(stepsCfg))
+	for i, stepCfg := range stepsCfg {
+		steps[i] = newStepInfo(stepCfg.describedName(i), stepCfg.Step, stepCfg.retryable(), false)
+	}
+
+	return steps
+}
+
+// Steps describes the configured steps of the workflow, in the configuration order, e.g. to display them, same as
+// Sequential.Steps. The dependencies of the steps are not described, and a failing step stops only its dependents.
+func (d *DAG[T]) Steps() []StepInfo {
+	steps := make([]StepInfo, len(d.stepsConfig))
+	for i, stepCfg := range d.stepsConfig {
+		steps[i] = newStepInfo(stepCfg.Step.Name(), stepCfg.Step, isRetryable(stepCfg.Step), false)
+	}
+
+	return steps
+}
+
+// newStepInfo describes the step named name.
+func newStepInfo(name string, step any, retryable, continueOnError bool) StepInfo {
+	_, nested := step.(stepsDescriber)
+
+	return StepInfo{
+		Name:            name,
+		Retryable:       retryable,
+		ContinueOnError: continueOnError,
+		Nested:          nested,
+	}
+}
+
+// describedName returns the name of the step configured at the index i, or the problem of a nil step, so the workflow
+// can be described whatever its configuration.
+func (c SequentialStepConfig[T]) describedName(i int) string {
+	if c.Step == nil {
+		return nilStepErr(i).Error()
+	}
+
+	return c.name()
+}
+
+// describedName returns the name of the step configured at the index i, or the problem of a nil step, same as for the
+// SequentialStepConfig.
+func (c PipeStepConfig[T]) describedName(i int) string {
+	if c.Step == nil {
+		return nilStepErr(i).Error()
+	}
+
+	return c.name()
+}
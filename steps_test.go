@@ -0,0 +1,133 @@
+package workflow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestSequentialSteps(t *testing.T) {
+	nested := NewSequential("nested", []SequentialStepConfig[any]{{Step: newStepSuccessful("nested step")}})
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1"), RetryConfigProvider: defaultRetryConfigProviderTest},
+		{Step: stepFuncMock(nil), ContinueWorkflowOnError: true, NameOverride: "step 2"},
+		{Step: nested},
+	}
+	wf := NewSequential("some-workflow", input)
+
+	actualOutput := wf.Steps()
+
+	expectedOutput := []StepInfo{
+		{Name: "step 1", Retryable: true},
+		{Name: "step 2", ContinueOnError: true},
+		{Name: "nested", Nested: true},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	// the returned steps are a copy.
+	actualOutput[0].Name = "changed"
+	if wf.Steps()[0].Name != "step 1" {
+		t.Errorf("Changing the returned steps changed the workflow steps: \n actual = %#v", wf.Steps())
+	}
+}
+
+func TestSequentialStepsOfDynamicWorkflow(t *testing.T) {
+	wf := NewDynamicSequential("some-workflow", func(ctx context.Context, req any) []SequentialStepConfig[any] {
+		return []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}}
+	})
+
+	if actualOutput := wf.Steps(); actualOutput != nil {
+		t.Errorf("The steps of a dynamic workflow are not as expected: \n expected = %#v, \n actual = %#v", nil, actualOutput)
+	}
+}
+
+func TestPipeSteps(t *testing.T) {
+	nested := NewPipe("nested", []PipeStepConfig[string]{{Step: newPipeStepAppend("nested step", "-n")}})
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepFailedRetryable[string]("step 1", nil), ContinueOnError: true},
+		{Step: newPipeStepAppend("step 2", "-b")},
+		{Step: nested},
+	}
+
+	actualOutput := NewPipe("some-workflow", input).Steps()
+
+	expectedOutput := []StepInfo{
+		{Name: "step 1", Retryable: true, ContinueOnError: true},
+		{Name: "step 2"},
+		{Name: "nested", Nested: true},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestSequentialStepsBehaviourOnNilStep(t *testing.T) {
+	input := []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}, {Step: nil}}
+
+	actualOutput := NewSequential("some-workflow", input).Steps()
+
+	expectedOutput := []StepInfo{{Name: "step 1", Retryable: true}, {Name: "the step at index: 1 is nil"}}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestPipeStepsBehaviourOnNilStep(t *testing.T) {
+	input := []PipeStepConfig[string]{{Step: nil}, {Step: newPipeStepAppend("step 2", "-b")}}
+
+	actualOutput := NewPipe("some-workflow", input).Steps()
+
+	expectedOutput := []StepInfo{{Name: "the step at index: 0 is nil"}, {Name: "step 2"}}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestParallelSteps(t *testing.T) {
+	nested := NewParallel("nested", []ParallelStepConfig[any]{{Step: newStepSuccessful("nested step")}})
+	input := []ParallelStepConfig[any]{
+		{Step: newStepSuccessful("step 1"), Priority: -1},
+		{Step: stepFuncMock(nil), NameOverride: "step 2", RetryIf: retryAlways},
+		{Step: nested},
+	}
+
+	actualOutput := NewParallel("some-workflow", input).Steps()
+
+	// the steps are described in the configuration order, whatever their priority.
+	expectedOutput := []StepInfo{
+		{Name: "step 1", Retryable: true},
+		{Name: "step 2", Retryable: true},
+		{Name: "nested", Nested: true},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+}
+
+func TestDAGSteps(t *testing.T) {
+	nested, _ := NewDAG("nested", []DAGStepConfig[any]{{Step: newStepSuccessful("nested step")}})
+	d, err := NewDAG("some-workflow", []DAGStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: stepFuncMock(nil), DependsOn: []string{"step 1"}},
+		{Step: nested, DependsOn: []string{"step 1"}},
+	})
+	if err != nil {
+		t.Fatalf("The workflow returned an unexpected error: %v", err)
+	}
+
+	actualOutput := d.Steps()
+
+	expectedOutput := []StepInfo{
+		{Name: "step 1", Retryable: true},
+		{Name: "step-func"},
+		{Name: "nested", Nested: true},
+	}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	// the DAG is reported as nested when used as a step of another workflow.
+	if steps := NewSequential("some-workflow", []SequentialStepConfig[any]{{Step: d}}).Steps(); !steps[0].Nested {
+		t.Errorf("The DAG step is not reported as nested: \n actual = %#v", steps)
+	}
+}
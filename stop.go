@@ -0,0 +1,33 @@
+package workflow
+
+import "errors"
+
+// ErrStopped is returned by the workflows stopped by their stop signal, see WithStopSignal.
+var ErrStopped = errors.New("workflow stopped")
+
+// WithStopSignal stops the workflow before the next step, with ErrStopped, once the stop channel is closed, e.g. to halt
+// a batch of running workflows from an operational control plane, without cancelling their ctx, which keeps carrying the
+// requests deadlines. The running step is not interrupted, and a stopped workflow is not retried by WithWorkflowRetry.
+// A nil stop channel never stops the workflow, which is the default. It has no effect on the DAG and Parallel workflows.
+func WithStopSignal(stop <-chan struct{}) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.stop = stop
+	}
+}
+
+// WithPipeStopSignal is the Pipe version of WithStopSignal.
+func WithPipeStopSignal(stop <-chan struct{}) PipeOption {
+	return func(o *pipeOptions) {
+		o.stop = stop
+	}
+}
+
+// stopped returns ErrStopped if the stop channel is closed.
+func stopped(stop <-chan struct{}) error {
+	select {
+	case <-stop:
+		return ErrStopped
+	default:
+		return nil
+	}
+}
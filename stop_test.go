@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSequentialExecuteBehaviourOnStopSignal(t *testing.T) {
+	stop := make(chan struct{})
+	step1 := &dagStepMock{name: "step 1", fn: func(ctx context.Context) error {
+		close(stop)
+
+		return nil
+	}}
+	step2 := &dagStepMock{name: "step 2", fn: func(ctx context.Context) error { return nil }}
+	input := []SequentialStepConfig[any]{
+		{Step: step1},
+		{Step: step2},
+	}
+
+	err := NewSequential(
+		"some-workflow",
+		input,
+		WithStopSignal(stop),
+		WithStorage(NewMemoryStorage()),
+		WithCorrelationID("id-1"),
+		WithWorkflowRetry(2, nil),
+	).Execute(context.TODO(), nil)
+
+	if !errors.Is(err, ErrStopped) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", ErrStopped, err)
+	}
+	// the stopped workflow is not retried.
+	if step1.invocationCount != 1 || step2.invocationCount != 0 {
+		t.Errorf("The steps invocation counts are not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", 1, 0, step1.invocationCount, step2.invocationCount)
+	}
+}
+
+func TestPipeExecuteBehaviourOnStopSignal(t *testing.T) {
+	stop := make(chan struct{})
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepFunc("step 1", func(req string) (string, error) {
+			close(stop)
+
+			return req + "-a", nil
+		})},
+		{Step: newPipeStepAppend("step 2", "-b")},
+	}
+
+	out, err := NewPipe("some-workflow", input, WithPipeStopSignal(stop)).Execute(context.TODO(), "x")
+
+	// the value the step 2 would have received is returned.
+	if !errors.Is(err, ErrStopped) || out != "x-a" {
+		t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x-a", ErrStopped, out, err)
+	}
+}
@@ -0,0 +1,273 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StepStatus is the stored status of a step execution.
+type StepStatus string
+
+const (
+	StepStatusSuccess StepStatus = "SUCCESS"
+	StepStatusFailed  StepStatus = "FAILED"
+	// StepStatusPermanentlyFailed is the status of a step which failed with an error it can't be retried for, as decided
+	// by its RetryDecider, ErrorRetryDecider or RetryIf, or because it implements none of them, so it would fail again on
+	// replay, as opposed to the StepStatusFailed of a step which ran out of attempts. It lets the operators route the
+	// permanent failures to a dead-letter queue. A recovered panic is a permanent failure as well.
+	StepStatusPermanentlyFailed StepStatus = "PERMANENTLY_FAILED"
+	// StepStatusSkipped is never saved by the workflows, but by the callers, e.g. to mark a step not to run again on
+	// replay, see WithSkipStatuses.
+	StepStatusSkipped StepStatus = "SKIPPED"
+)
+
+// Storage persists the steps execution results, under a correlation ID, allowing a workflow to be replayed:
+// the steps that already succeeded for the same correlation ID are skipped, or the ones stored with any of the statuses
+// set by WithSkipStatuses.
+// The results are identified by the step names, so the steps sharing a name share their result as well. The same goes
+// for the step names that an implementation normalizes to the same key, e.g. by lower casing them or by dropping their
+// special characters, like "Send email" and "send_email". Setting the IdempotencyKey of the step configs avoids these
+// collisions, as the results are then identified by the keys, whatever the step names. The Aliases of the step configs
+// keep the results of a renamed step reachable, under its previous names.
+// The implementations should store the step names as they are, without a lossy sanitization, and the workflows needing
+// one, e.g. for a storage restricting the key characters, should set it by WithStepKeyFunc or WithPipeStepKeyFunc, so it
+// is visible, and applied the same way to all the storage operations.
+type Storage interface {
+	// Save stores the status and the output(nil on success, the error message on failure) of the step execution.
+	Save(ctx context.Context, stepName, correlationID string, status StepStatus, output *string) error
+	// Get returns the stored status of the step execution.
+	// If there is no stored record, it must return an empty StepStatus and a nil error.
+	Get(ctx context.Context, stepName, correlationID string) (StepStatus, error)
+	// Clear removes all the stored records of the correlation ID, including the values.
+	// It must be idempotent: if there is no stored record, e.g. as they were already removed by a concurrent execution or
+	// expired, it must return a nil error.
+	Clear(ctx context.Context, correlationID string) error
+	// SaveValue stores the serialized output value of the step execution.
+	// It is used only by the Pipe workflow, because a skipped step must hand its stored output to the next step.
+	SaveValue(ctx context.Context, stepName, correlationID string, value []byte) error
+	// GetValue returns the serialized output value of the step execution.
+	GetValue(ctx context.Context, stepName, correlationID string) ([]byte, error)
+}
+
+// StorageErrorPolicy decides how the workflows handle the Storage errors.
+type StorageErrorPolicy int
+
+const (
+	// StoragePolicyFail makes a Storage error fail the step, or the workflow when clearing the results. It's the default.
+	StoragePolicyFail StorageErrorPolicy = iota
+	// StoragePolicyLog makes the workflow log and ignore the Storage errors: a step whose result can't be read is
+	// executed, a step whose result can't be saved doesn't fail, and a workflow whose results can't be cleared succeeds.
+	StoragePolicyLog
+)
+
+// StepResult is the stored result of a step execution.
+type StepResult struct {
+	Status StepStatus
+	Output *string // nil on success, the error message on failure
+	// SavedAt is the time the result was saved at, as recorded by the Storage.
+	SavedAt time.Time
+}
+
+// ResultStorage is a Storage able to return the full stored result of a step execution, and not only its status.
+// If the Storage provided to a workflow implements it, GetResult is used instead of Get, and the error message of a
+// previously failed step execution is logged, when the step is replayed.
+type ResultStorage interface {
+	Storage
+	// GetResult returns the stored result of the step execution.
+	// If there is no stored record, it must return a StepResult with an empty StepStatus and a nil error.
+	GetResult(ctx context.Context, stepName, correlationID string) (StepResult, error)
+}
+
+// Codec serializes the output values of the Pipe steps, for the Storage.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec is the default Codec, and uses the encoding/json package.
+type JSONCodec struct{}
+
+// Marshal implements the Codec interface.
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements the Codec interface.
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// stepsStore persists the steps execution results in the Storage, under the correlation ID.
+// It is disabled if either the storage or the correlation ID is missing.
+type stepsStore struct {
+	storage       Storage
+	correlationID string
+	// correlationIDFromCtx reads the correlation ID from the execution ctx, and takes precedence over the correlationID
+	correlationIDFromCtx func(ctx context.Context) string
+	ttl                  time.Duration // the stored results older than the ttl are treated as absent, if greater than 0
+	errorPolicy          StorageErrorPolicy
+	// outputTransformer builds the stored output of the failed steps, instead of their error message, if not nil
+	outputTransformer func(stepName string, err error) *string
+	// keyFunc maps the step names, or idempotency keys, to the storage keys, which are the names themselves if nil
+	keyFunc func(name string) string
+	// skipStatuses are the stored statuses skipping the steps on replay, which is only StepStatusSuccess if empty
+	skipStatuses []StepStatus
+}
+
+// id returns the correlation ID of the execution, and reports whether the steps execution results are persisted.
+// The correlation ID read from the ctx takes precedence, and the static one is used only if it's empty.
+func (ss stepsStore) id(ctx context.Context) (string, bool) {
+	if ss.storage == nil {
+		return "", false
+	}
+	id := ss.correlationID
+	if ss.correlationIDFromCtx != nil {
+		if ctxID := ss.correlationIDFromCtx(ctx); ctxID != "" {
+			id = ctxID
+		}
+	}
+
+	return id, id != ""
+}
+
+// key returns the storage key of the step name, or idempotency key, k.
+func (ss stepsStore) key(k string) string {
+	if ss.keyFunc != nil {
+		return ss.keyFunc(k)
+	}
+
+	return k
+}
+
+// handleErr applies the error policy to the storage err: the err is returned as it is, or logged and dropped.
+func (ss stepsStore) handleErr(ctx context.Context, log logger, err error) error {
+	if err == nil || ss.errorPolicy == StoragePolicyFail {
+		return err
+	}
+	log.print(ctx, LevelError, concatStr(failed, " ignoring storage err: ", err.Error()))
+
+	return nil
+}
+
+// skipStep reports whether the step execution already succeeded, for the correlation ID, or more generally whether its
+// stored status is one of the skip statuses. It also returns the error message of a previously failed step execution, if the Storage is a ResultStorage.
+func (ss stepsStore) skipStep(ctx context.Context, stepName string) (bool, string, error) {
+	id, ok := ss.id(ctx)
+	if !ok {
+		return false, "", nil
+	}
+	res, err := ss.getResult(ctx, ss.key(stepName), id)
+	if err != nil {
+		return false, "", fmt.Errorf("error getting step execution result: %w", err)
+	}
+	if ss.expired(res) {
+		return false, "", nil
+	}
+	var failure string
+	if (res.Status == StepStatusFailed || res.Status == StepStatusPermanentlyFailed) && res.Output != nil {
+		failure = *res.Output
+	}
+
+	return ss.skips(res.Status), failure, nil
+}
+
+// skips reports whether the stored status skips the step on replay.
+func (ss stepsStore) skips(status StepStatus) bool {
+	if len(ss.skipStatuses) == 0 {
+		return status == StepStatusSuccess
+	}
+	for _, s := range ss.skipStatuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipStepOrAlias reports whether the step execution already succeeded, for the correlation ID, as skipStep does, under
+// the key, or else under one of the aliases, checked in order, and returns the key the succeeded result is stored
+// under. The error message returned is the one stored under the key.
+func (ss stepsStore) skipStepOrAlias(ctx context.Context, key string, aliases []string) (string, bool, string, error) {
+	skip, failure, err := ss.skipStep(ctx, key)
+	for i := 0; !skip && err == nil && i < len(aliases); i++ {
+		key = aliases[i]
+		skip, _, err = ss.skipStep(ctx, key)
+	}
+
+	return key, skip, failure, err
+}
+
+// expired reports whether the stored result is older than the ttl.
+// The results without a SavedAt time, like the ones returned by a Storage which is not a ResultStorage, never expire.
+func (ss stepsStore) expired(res StepResult) bool {
+	return ss.ttl > 0 && !res.SavedAt.IsZero() && time.Since(res.SavedAt) > ss.ttl
+}
+
+// getResult returns the stored result of the step execution, which holds only the status if the Storage is not a ResultStorage.
+func (ss stepsStore) getResult(ctx context.Context, stepName, id string) (StepResult, error) {
+	if rs, ok := ss.storage.(ResultStorage); ok {
+		return rs.GetResult(ctx, stepName, id)
+	}
+	status, err := ss.storage.Get(ctx, stepName, id)
+
+	return StepResult{Status: status}, err
+}
+
+// printReplay logs the error message of the previous execution of the replayed step, if it failed.
+func (l logger) printReplay(ctx context.Context, stepName, failure string) {
+	if failure == "" {
+		return
+	}
+	l.printStep(ctx, LevelInfo, concatStr("replaying step: ", stepName, ", previously failed with err: ", failure), stepName, 0)
+}
+
+// storeStepResult saves the result of the step execution, for the correlation ID.
+// The stepErr is the error returned by the step, and it is stored as the output of a failed step, unless an output
+// transformer is set, in which case the output is the transformer one. The key identifies the step execution result.
+// A failed step is stored as permanently failed if the permanent is true.
+func (ss stepsStore) storeStepResult(ctx context.Context, stepName, key string, stepErr error, permanent bool) error {
+	id, ok := ss.id(ctx)
+	if !ok {
+		return nil
+	}
+	status := StepStatusSuccess
+	var output *string
+	if stepErr != nil {
+		status = StepStatusFailed
+		if permanent {
+			status = StepStatusPermanentlyFailed
+		}
+		output = ss.output(stepName, stepErr)
+	}
+	if err := ss.storage.Save(ctx, ss.key(key), id, status, output); err != nil {
+		return fmt.Errorf("error storing step execution result: %w", err)
+	}
+
+	return nil
+}
+
+// output returns the stored output of the failed step: the stepErr message, or the output transformer one, if set.
+func (ss stepsStore) output(stepName string, stepErr error) *string {
+	if ss.outputTransformer != nil {
+		return ss.outputTransformer(stepName, stepErr)
+	}
+	msg := stepErr.Error()
+
+	return &msg
+}
+
+// clearDB removes the steps execution results of the correlation ID, as there is nothing to replay after a successful workflow.
+func (ss stepsStore) clearDB(ctx context.Context) error {
+	id, ok := ss.id(ctx)
+	if !ok {
+		return nil
+	}
+	if err := ss.storage.Clear(ctx, id); err != nil {
+		return fmt.Errorf("error clearing steps execution results: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,1063 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestSequentialExecuteBehaviourOnReplay(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	step1 := newStepSuccessful("step 1")
+	step2 := newStepFailedNonRetryable("step 2", anyErr)
+	input := []SequentialStepConfig[any]{
+		{Step: step1},
+		{Step: step2},
+	}
+
+	c := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1"))
+	if err := c.Execute(context.TODO(), nil); !errors.Is(err, anyErr) {
+		t.Fatalf("The first execution error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+	if status, _ := repo.Get(context.TODO(), "step 2", "id-1"); status != StepStatusPermanentlyFailed {
+		t.Errorf("The failed step status is not as expected: \n expected = %#v, \n actual = %#v", StepStatusPermanentlyFailed, status)
+	}
+
+	// the replay runs only the failed step, which now succeeds.
+	step2.execute = nil
+	if err := c.Execute(context.TODO(), nil); err != nil {
+		t.Fatalf("The replay returned an unexpected error: %v", err)
+	}
+	if step1.invocationCount != 1 {
+		t.Errorf("The succeeded step was not skipped on replay: \n expected invocations = %#v, \n actual = %#v", 1, step1.invocationCount)
+	}
+	if step2.invocationCount != 2 {
+		t.Errorf("The failed step was not executed on replay: \n expected invocations = %#v, \n actual = %#v", 2, step2.invocationCount)
+	}
+	if repo.len() != 0 {
+		t.Errorf("The storage was not cleared after the workflow succeeded: \n records = %#v", repo.len())
+	}
+}
+
+func TestSequentialExecuteBehaviourOnReplayOfFailedStep(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	step := newStepFailedNonRetryable("step 1", anyErr)
+	input := []SequentialStepConfig[any]{
+		{Step: step},
+	}
+	log := &loggerMock{}
+
+	c := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1"), WithLogger(log))
+	c.Execute(context.TODO(), nil)
+	res, _ := repo.GetResult(context.TODO(), "step 1", "id-1")
+	if res.Status != StepStatusPermanentlyFailed || res.Output == nil || res.SavedAt.IsZero() {
+		t.Errorf("The stored step result is not as expected: \n actual = %#v", res)
+	}
+
+	step.execute = nil
+	c.Execute(context.TODO(), nil)
+
+	expectedOutput := "replaying step: step 1, previously failed with err: any-err"
+	if !contains(log.info, expectedOutput) {
+		t.Errorf("The previous failure of the replayed step was not logged: \n expected = %#v, \n actual = %#v", expectedOutput, log.info)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStorageOutputTransformer(t *testing.T) {
+	secretErr := errors.New("GET https://api/users?token=secret failed")
+	redacted := "redacted"
+	tests := []struct {
+		name           string
+		input          func(stepName string, err error) *string
+		expectedOutput *string
+	}{
+		{
+			name:           "a failed step output should be its error message, without a transformer",
+			input:          nil,
+			expectedOutput: func() *string { msg := secretErr.Error(); return &msg }(),
+		},
+		{
+			name: "a failed step output should be the transformer output",
+			input: func(stepName string, err error) *string {
+				msg := stepName + ": " + redacted
+
+				return &msg
+			},
+			expectedOutput: func() *string { msg := "step 1: " + redacted; return &msg }(),
+		},
+		{
+			name:           "a failed step output should be nil, for a transformer returning nil",
+			input:          func(stepName string, err error) *string { return nil },
+			expectedOutput: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newInMemoryRepo()
+			input := []SequentialStepConfig[any]{
+				{Step: newStepFailedNonRetryable("step 1", secretErr), IdempotencyKey: "key-1"},
+			}
+
+			NewSequential(
+				"some-workflow",
+				input,
+				WithStorage(repo),
+				WithCorrelationID("id-1"),
+				WithStorageOutputTransformer(tt.input),
+			).Execute(context.TODO(), nil)
+
+			res, _ := repo.GetResult(context.TODO(), "key-1", "id-1")
+			if !reflect.DeepEqual(res.Output, tt.expectedOutput) {
+				t.Errorf("The stored step output is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, res.Output)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStepKeyFunc(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name                    string
+		input                   func(name string) string
+		expectedInvocationCount int // of the replayed failed step
+	}{
+		{
+			name:                    "similar step names should stay distinct by default",
+			input:                   nil,
+			expectedInvocationCount: 2,
+		},
+		{
+			name:                    "step names mapped to the same key should share their result",
+			input:                   func(name string) string { return strings.ReplaceAll(strings.ToLower(name), " ", "_") },
+			expectedInvocationCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := NewMemoryStorage()
+			failing := newStepFailedNonRetryable("send_email", anyErr)
+			input := []SequentialStepConfig[any]{
+				{Step: newStepSuccessful("Send email")},
+				{Step: failing},
+			}
+			wf := NewSequential("some-workflow", input, WithStorage(storage), WithCorrelationID("id-1"), WithStepKeyFunc(tt.input))
+
+			wf.Execute(context.TODO(), nil)
+			failing.execute = nil
+			wf.Execute(context.TODO(), nil)
+
+			if failing.invocationCount != tt.expectedInvocationCount {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedInvocationCount, failing.invocationCount)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnStepKeyFunc(t *testing.T) {
+	storage := NewMemoryStorage()
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("Step 1", "-a")},
+		{Step: newPipeStepFailedNonRetryable[string]("Step 2", errors.New("any-err"))},
+	}
+	wf := NewPipe("some-workflow", input, WithPipeStorage(storage), WithPipeCorrelationID("id-1"), WithPipeStepKeyFunc(strings.ToLower))
+	wf.Execute(context.TODO(), "x")
+
+	// both the result and the output value are stored under the key.
+	status, _ := storage.Get(context.TODO(), "step 1", "id-1")
+	value, _ := storage.GetValue(context.TODO(), "step 1", "id-1")
+	if status != StepStatusSuccess || string(value) != `"x-a"` {
+		t.Errorf("The stored step result is not as expected: \n actual = %#v, %#v", status, string(value))
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStorageTTL(t *testing.T) {
+	tests := []struct {
+		name           string
+		ttl            time.Duration
+		expectedOutput int // the invocation count of the step which succeeded an hour ago
+	}{
+		{
+			name:           "a workflow without a TTL should skip the step which succeeded",
+			ttl:            0,
+			expectedOutput: 0,
+		},
+		{
+			name:           "a workflow with a TTL longer than the result age should skip the step which succeeded",
+			ttl:            2 * time.Hour,
+			expectedOutput: 0,
+		},
+		{
+			name:           "a workflow with a TTL shorter than the result age should run the step which succeeded",
+			ttl:            time.Minute,
+			expectedOutput: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newInMemoryRepo()
+			repo.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+			repo.savedAt[key("step 1", "id-1")] = time.Now().Add(-time.Hour)
+			step := newStepSuccessful("step 1")
+			input := []SequentialStepConfig[any]{
+				{Step: step},
+			}
+
+			NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1"), WithStorageTTL(tt.ttl)).
+				Execute(context.TODO(), nil)
+
+			if step.invocationCount != tt.expectedOutput {
+				t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, step.invocationCount)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnIdempotencyKey(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	// the step names collapse to the same storage key, once cleaned.
+	step1 := newStepSuccessful("Send email")
+	step2 := newStepFailedNonRetryable("send_email", anyErr)
+	input := []SequentialStepConfig[any]{
+		{Step: step1, IdempotencyKey: "send-welcome-email"},
+		{Step: step2, IdempotencyKey: "send-invoice-email"},
+	}
+
+	c := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1"))
+	if err := c.Validate(); err != nil {
+		t.Fatalf("The steps with distinct keys are not valid: %v", err)
+	}
+	c.Execute(context.TODO(), nil)
+	c.Execute(context.TODO(), nil)
+
+	// without the keys, the failed step would be skipped on replay, as it would share the result of the succeeded one.
+	if step1.invocationCount != 1 || step2.invocationCount != 2 {
+		t.Errorf("The steps were not replayed by their keys: \n invocations = %#v, %#v", step1.invocationCount, step2.invocationCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnNameOverride(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	log := &loggerMock{}
+	notifyStep := newStepSuccessful("send-notification")
+	// the same nested workflow is used twice, so its name is overridden at every use.
+	notify := NewSequential("notify", []SequentialStepConfig[any]{{Step: notifyStep}})
+	charge := newStepFailedNonRetryable("charge", anyErr)
+	input := []SequentialStepConfig[any]{
+		{Step: notify, NameOverride: "notify-customer"},
+		{Step: charge},
+		{Step: notify, NameOverride: "notify-merchant"},
+	}
+
+	c := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1"), WithLogger(log))
+	if err := c.Validate(); err != nil {
+		t.Fatalf("The uses with distinct name overrides are not valid: %v", err)
+	}
+	c.Execute(context.TODO(), nil)
+	if status, _ := repo.Get(context.TODO(), "notify-customer", "id-1"); status != StepStatusSuccess {
+		t.Errorf("The step result is not stored by the name override: \n expected = %#v, \n actual = %#v", StepStatusSuccess, status)
+	}
+	charge.execute = nil
+	if err := c.Execute(context.TODO(), nil); err != nil {
+		t.Fatalf("The replay returned an unexpected error: %v", err)
+	}
+
+	if notifyStep.invocationCount != 2 {
+		t.Errorf("The nested workflow was not skipped by the name override: \n expected invocations = %#v, \n actual = %#v", 2, notifyStep.invocationCount)
+	}
+	expectedOutput := "skipping step: notify-customer, already succeeded"
+	if !contains(log.info, expectedOutput) {
+		t.Errorf("The name override was not logged: \n expected = %#v, \n actual = %#v", expectedOutput, log.info)
+	}
+}
+
+func TestPipeExecuteBehaviourOnNameOverride(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	step2 := newPipeStepAppend("step 2", "-b")
+	step2.err = anyErr
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a"), NameOverride: "append-a"},
+		{Step: step2},
+	}
+
+	_, err := NewPipe("some-workflow", input, WithPipeStorage(repo), WithPipeCorrelationID("id-1")).Execute(context.TODO(), "x")
+
+	var stepErr StepError
+	if !errors.As(err, &stepErr) || stepErr.StepName != "step 2" {
+		t.Fatalf("The workflow error is not as expected: \n actual = %#v", err)
+	}
+	if status, _ := repo.Get(context.TODO(), "append-a", "id-1"); status != StepStatusSuccess {
+		t.Errorf("The step result is not stored by the name override: \n expected = %#v, \n actual = %#v", StepStatusSuccess, status)
+	}
+	if value, _ := repo.GetValue(context.TODO(), "append-a", "id-1"); string(value) != `"x-a"` {
+		t.Errorf("The step output is not stored by the name override: \n expected = %#v, \n actual = %#v", `"x-a"`, string(value))
+	}
+}
+
+func TestSequentialExecuteBehaviourOnSkipHook(t *testing.T) {
+	repo := newInMemoryRepo()
+	repo.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+	var skipped []string
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepSuccessful("step 2"), ShouldRun: func(ctx context.Context, req any) bool { return false }},
+		{Step: newStepSuccessful("step 3")},
+	}
+	onSkip := func(ctx context.Context, stepName string) {
+		skipped = append(skipped, stepName)
+	}
+
+	NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1"), WithOnSkip(onSkip)).Execute(context.TODO(), nil)
+
+	expectedOutput := []string{"step 1"}
+	if !reflect.DeepEqual(skipped, expectedOutput) {
+		t.Errorf("The skipped steps are not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, skipped)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStorageWithoutCorrelationID(t *testing.T) {
+	repo := newInMemoryRepo()
+	input := []SequentialStepConfig[any]{
+		{Step: newStepFailedNonRetryable("step 1", errors.New("any-err"))},
+	}
+
+	NewSequential("some-workflow", input, WithStorage(repo)).Execute(context.TODO(), nil)
+
+	if repo.len() != 0 {
+		t.Errorf("The storage was used without a correlation ID: \n records = %#v", repo.len())
+	}
+}
+
+func TestSequentialExecuteBehaviourOnCorrelationIDFromContext(t *testing.T) {
+	type ctxKey struct{}
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	step1 := newStepSuccessful("step 1")
+	step2 := newStepFailedNonRetryable("step 2", anyErr)
+	input := []SequentialStepConfig[any]{
+		{Step: step1},
+		{Step: step2},
+	}
+	fromCtx := func(ctx context.Context) string {
+		id, _ := ctx.Value(ctxKey{}).(string)
+		return id
+	}
+
+	c := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("static-id"), WithCorrelationIDFromContext(fromCtx))
+	c.Execute(context.WithValue(context.TODO(), ctxKey{}, "id-1"), nil)
+	c.Execute(context.TODO(), nil)
+
+	// the IDs read from the ctx take precedence, and the static ID is used for the ctx without an ID.
+	for _, id := range []string{"id-1", "static-id"} {
+		if status, _ := repo.Get(context.TODO(), "step 1", id); status != StepStatusSuccess {
+			t.Errorf("The step status for the correlation ID: %s is not as expected: \n expected = %#v, \n actual = %#v", id, StepStatusSuccess, status)
+		}
+	}
+	// the replay for the ID read from the ctx skips the step which succeeded for it.
+	step2.execute = nil
+	if err := c.Execute(context.WithValue(context.TODO(), ctxKey{}, "id-1"), nil); err != nil {
+		t.Fatalf("The replay returned an unexpected error: %v", err)
+	}
+	if step1.invocationCount != 2 {
+		t.Errorf("The succeeded step was not skipped on replay: \n expected invocations = %#v, \n actual = %#v", 2, step1.invocationCount)
+	}
+	if status, _ := repo.Get(context.TODO(), "step 1", "static-id"); status != StepStatusSuccess {
+		t.Errorf("The results of another correlation ID were cleared: \n expected = %#v, \n actual = %#v", StepStatusSuccess, status)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnStorageErrorPolicy(t *testing.T) {
+	dbErr := errors.New("db-err")
+	tests := []struct {
+		name           string
+		policy         StorageErrorPolicy
+		repo           *failingRepo
+		expectedOutput error
+	}{
+		{
+			name:           "a workflow with the fail policy should fail if the step result can't be read",
+			policy:         StoragePolicyFail,
+			repo:           &failingRepo{inMemoryRepo: newInMemoryRepo(), getErr: dbErr},
+			expectedOutput: dbErr,
+		},
+		{
+			name:           "a workflow with the fail policy should fail if the step result can't be saved",
+			policy:         StoragePolicyFail,
+			repo:           &failingRepo{inMemoryRepo: newInMemoryRepo(), saveErr: dbErr},
+			expectedOutput: dbErr,
+		},
+		{
+			name:           "a workflow with the fail policy should fail if the results can't be cleared",
+			policy:         StoragePolicyFail,
+			repo:           &failingRepo{inMemoryRepo: newInMemoryRepo(), clearErr: dbErr},
+			expectedOutput: dbErr,
+		},
+		{
+			name:           "a workflow with the log policy should succeed if the storage fails",
+			policy:         StoragePolicyLog,
+			repo:           &failingRepo{inMemoryRepo: newInMemoryRepo(), getErr: dbErr, saveErr: dbErr, clearErr: dbErr},
+			expectedOutput: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := newStepSuccessful("step 1")
+			input := []SequentialStepConfig[any]{
+				{Step: step},
+			}
+			log := &loggerMock{}
+
+			err := NewSequential(
+				"some-workflow",
+				input,
+				WithStorage(tt.repo),
+				WithCorrelationID("id-1"),
+				WithStorageErrorPolicy(tt.policy),
+				WithLogger(log),
+			).Execute(context.TODO(), nil)
+
+			if !errors.Is(err, tt.expectedOutput) || (tt.expectedOutput == nil && err != nil) {
+				t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, err)
+			}
+			if tt.policy == StoragePolicyLog && (step.invocationCount != 1 || len(log.error) != 3) {
+				t.Errorf("The storage errors were not ignored: \n invocations = %#v, \n error logs = %#v", step.invocationCount, log.error)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnStorageErrorPolicy(t *testing.T) {
+	dbErr := errors.New("db-err")
+	repo := &failingRepo{inMemoryRepo: newInMemoryRepo(), getErr: dbErr, saveErr: dbErr, clearErr: dbErr}
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+	}
+
+	_, err := NewPipe("some-workflow", input, WithPipeStorage(repo), WithPipeCorrelationID("id-1")).Execute(context.TODO(), "x")
+	if !errors.Is(err, dbErr) {
+		t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", dbErr, err)
+	}
+	out, err := NewPipe(
+		"some-workflow",
+		input,
+		WithPipeStorage(repo),
+		WithPipeCorrelationID("id-1"),
+		WithPipeStorageErrorPolicy(StoragePolicyLog),
+	).Execute(context.TODO(), "x")
+	if err != nil || out != "x-a" {
+		t.Errorf("The workflow output is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x-a", nil, out, err)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnFailingSave(t *testing.T) {
+	anyErr := errors.New("any-err")
+	dbErr := errors.New("db-err")
+	repo := &failingRepo{inMemoryRepo: newInMemoryRepo(), saveErr: dbErr}
+	input := []SequentialStepConfig[any]{
+		{Step: newStepFailedNonRetryable("step 1", anyErr)},
+	}
+
+	err := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1")).Execute(context.TODO(), nil)
+
+	// both the step failure and the storage failure must be visible.
+	for _, expectedErr := range []error{anyErr, dbErr} {
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("The workflow error does not wrap the expected error: \n expected = %#v, \n actual = %#v", expectedErr, err)
+		}
+	}
+	if strings.Count(err.Error(), anyErr.Error()) != 1 {
+		t.Errorf("The step error is reported more than once: \n actual = %#v", err.Error())
+	}
+}
+
+func TestSequentialExecuteBehaviourOnWorkflowRetry(t *testing.T) {
+	anyErr := errors.New("any-err")
+	tests := []struct {
+		name                     string
+		succeedAtInvocationCount int
+		expectedOutput           error
+		expectedInvocations      int
+	}{
+		{
+			name:                     "a workflow failing transiently should succeed on a retry",
+			succeedAtInvocationCount: 3,
+			expectedOutput:           nil,
+			expectedInvocations:      3,
+		},
+		{
+			name:                     "a workflow failing on every attempt should return the error of the last attempt",
+			succeedAtInvocationCount: 0,
+			expectedOutput:           anyErr,
+			expectedInvocations:      3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step1 := newStepSuccessful("step 1")
+			// the step is not retried by its own configuration, as it has no RetryConfigProvider.
+			step2 := newStepFailedRetryableRecoverable("step 2", anyErr, tt.succeedAtInvocationCount)
+			input := []SequentialStepConfig[any]{{Step: step1}, {Step: step2}}
+			c := NewSequential(
+				"some-workflow",
+				input,
+				WithStorage(newInMemoryRepo()),
+				WithCorrelationID("id-1"),
+				WithWorkflowRetry(2, ConstantBackoff(time.Millisecond)),
+			)
+
+			err := c.Execute(context.TODO(), nil)
+
+			if !errors.Is(err, tt.expectedOutput) || (tt.expectedOutput == nil && err != nil) {
+				t.Errorf("The execution error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, err)
+			}
+			if step1.invocationCount != 1 {
+				t.Errorf("The succeeded step was not skipped on retry: \n expected invocations = %#v, \n actual = %#v", 1, step1.invocationCount)
+			}
+			if step2.invocationCount != tt.expectedInvocations {
+				t.Errorf("The failed step invocations are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedInvocations, step2.invocationCount)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnWorkflowRetryWithoutCorrelationID(t *testing.T) {
+	step := newStepSuccessful("step 1")
+	input := []SequentialStepConfig[any]{{Step: step}}
+
+	err := NewSequential("some-workflow", input, WithStorage(newInMemoryRepo()), WithWorkflowRetry(2, nil)).Execute(context.TODO(), nil)
+
+	if !errors.Is(err, ErrCorrelationIDRequired) {
+		t.Errorf("The execution error is not as expected: \n expected = %#v, \n actual = %#v", ErrCorrelationIDRequired, err)
+	}
+	if step.invocationCount != 0 {
+		t.Errorf("The workflow ran without a correlation ID: \n invocations = %#v", step.invocationCount)
+	}
+}
+
+func TestPipeExecuteBehaviourOnReplay(t *testing.T) {
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	step1 := newPipeStepAppend("step 1", "-a")
+	step2 := newPipeStepAppend("step 2", "-b")
+	step3 := newPipeStepAppend("step 3", "-c")
+	step3.err = anyErr
+	input := []PipeStepConfig[string]{
+		{Step: step1},
+		{Step: step2},
+		{Step: step3},
+	}
+
+	c := NewPipe("some-workflow", input, WithPipeStorage(repo), WithPipeCorrelationID("id-1"))
+	if _, err := c.Execute(context.TODO(), "x"); !errors.Is(err, anyErr) {
+		t.Fatalf("The first execution error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+
+	// the replay runs only the failed step, which receives the stored output of the previous step.
+	step3.err = nil
+	out, err := c.Execute(context.TODO(), "x")
+	if err != nil {
+		t.Fatalf("The replay returned an unexpected error: %v", err)
+	}
+	if step1.invocationCount != 1 || step2.invocationCount != 1 {
+		t.Errorf("The succeeded steps were not skipped on replay: \n invocations = %#v, %#v", step1.invocationCount, step2.invocationCount)
+	}
+	if step3.lastInput != "x-a-b" {
+		t.Errorf("The step after the skipped steps did not receive the stored output: \n expected = %#v, \n actual = %#v", "x-a-b", step3.lastInput)
+	}
+	if out != "x-a-b-c" {
+		t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", "x-a-b-c", out)
+	}
+	if repo.len() != 0 {
+		t.Errorf("The storage was not cleared after the workflow succeeded: \n records = %#v", repo.len())
+	}
+}
+
+func TestPipeExecuteBehaviourOnReplayWithCodec(t *testing.T) {
+	type payload struct {
+		Path []string
+	}
+	anyErr := errors.New("any-err")
+	repo := newInMemoryRepo()
+	codec := &codecMock{}
+	step1 := newPipeStepFunc("step 1", func(req payload) (payload, error) {
+		return payload{Path: append(req.Path, "step 1")}, nil
+	})
+	step2Err := anyErr
+	step2 := newPipeStepFunc("step 2", func(req payload) (payload, error) {
+		if step2Err != nil {
+			return payload{}, step2Err
+		}
+		return payload{Path: append(req.Path, "step 2")}, nil
+	})
+	var step3Input payload
+	step3 := newPipeStepFunc("step 3", func(req payload) (payload, error) {
+		step3Input = req
+		return payload{Path: append(req.Path, "step 3")}, nil
+	})
+	input := []PipeStepConfig[payload]{
+		{Step: step1},
+		{Step: step2},
+		{Step: step3},
+	}
+
+	c := NewPipe("some-workflow", input, WithPipeStorage(repo), WithPipeCorrelationID("id-1"), WithPipeCodec(codec))
+	if _, err := c.Execute(context.TODO(), payload{}); !errors.Is(err, anyErr) {
+		t.Fatalf("The first execution error is not as expected: \n expected = %#v, \n actual = %#v", anyErr, err)
+	}
+
+	// the replay skips the first step, and reconstructs its output from the storage.
+	step2Err = nil
+	out, err := c.Execute(context.TODO(), payload{})
+	if err != nil {
+		t.Fatalf("The replay returned an unexpected error: %v", err)
+	}
+	if step1.invocationCount != 1 {
+		t.Errorf("The succeeded step was not skipped on replay: \n expected invocations = %#v, \n actual = %#v", 1, step1.invocationCount)
+	}
+	expectedStep3Input := payload{Path: []string{"step 1", "step 2"}}
+	if !reflect.DeepEqual(step3Input, expectedStep3Input) {
+		t.Errorf("The step input is not as expected: \n expected = %#v, \n actual = %#v", expectedStep3Input, step3Input)
+	}
+	expectedOut := payload{Path: []string{"step 1", "step 2", "step 3"}}
+	if !reflect.DeepEqual(out, expectedOut) {
+		t.Errorf("The workflow output is not as expected: \n expected = %#v, \n actual = %#v", expectedOut, out)
+	}
+	if codec.marshalCount != 3 || codec.unmarshalCount != 1 {
+		t.Errorf("The codec was not used as expected: \n marshal count = %#v, \n unmarshal count = %#v", codec.marshalCount, codec.unmarshalCount)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnAliases(t *testing.T) {
+	repo := newInMemoryRepo()
+	// the results stored before the step was renamed from "old step 2", itself renamed from "step 2".
+	repo.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+	repo.Save(context.TODO(), "step 2", "id-1", StepStatusSuccess, nil)
+	step1 := newStepSuccessful("step 1")
+	renamed := newStepSuccessful("new step 2")
+	other := newStepSuccessful("step 3")
+	input := []SequentialStepConfig[any]{
+		{Step: step1},
+		{Step: renamed, Aliases: []string{"old step 2", "step 2"}},
+		{Step: other, Aliases: []string{"step 4"}},
+	}
+
+	err := NewSequential("some-workflow", input, WithStorage(repo), WithCorrelationID("id-1")).Execute(context.TODO(), nil)
+
+	if err != nil || step1.invocationCount != 0 || renamed.invocationCount != 0 || other.invocationCount != 1 {
+		t.Errorf("The replay is not as expected: \n actual = %#v, %#v, %#v, %#v", err, step1.invocationCount, renamed.invocationCount, other.invocationCount)
+	}
+}
+
+func TestPipeExecuteBehaviourOnAliases(t *testing.T) {
+	repo := newInMemoryRepo()
+	repo.SaveValue(context.TODO(), "step 1", "id-1", []byte(`"x-old"`))
+	repo.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("new step 1", "-a"), Aliases: []string{"step 1"}},
+		{Step: newPipeStepAppend("step 2", "-b")},
+	}
+
+	out, err := NewPipe("some-workflow", input, WithPipeStorage(repo), WithPipeCorrelationID("id-1")).Execute(context.TODO(), "x")
+
+	// the renamed step is skipped, and its output is read under its alias.
+	if err != nil || out != "x-old-b" {
+		t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x-old-b", nil, out, err)
+	}
+}
+
+func TestSequentialExecuteBehaviourOnSkipStatuses(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          []StepStatus
+		expectedOutput []int
+	}{
+		{
+			name:           "by default, only the succeeded step should be skipped on replay",
+			input:          nil,
+			expectedOutput: []int{0, 1, 1},
+		},
+		{
+			name:           "the steps with a configured skip status should be skipped on replay",
+			input:          []StepStatus{StepStatusSuccess, StepStatusPermanentlyFailed},
+			expectedOutput: []int{0, 0, 1},
+		},
+		{
+			name:           "the succeeded step should run again, if its status is not configured to skip",
+			input:          []StepStatus{StepStatusPermanentlyFailed},
+			expectedOutput: []int{1, 0, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newInMemoryRepo()
+			repo.Save(context.TODO(), "step 1", "id-1", StepStatusSuccess, nil)
+			repo.Save(context.TODO(), "step 2", "id-1", StepStatusPermanentlyFailed, nil)
+			repo.Save(context.TODO(), "step 3", "id-1", StepStatusFailed, nil)
+			steps := []*stepMock{newStepSuccessful("step 1"), newStepSuccessful("step 2"), newStepSuccessful("step 3")}
+			input := []SequentialStepConfig[any]{{Step: steps[0]}, {Step: steps[1]}, {Step: steps[2]}}
+
+			err := NewSequential(
+				"some-workflow",
+				input,
+				WithStorage(repo),
+				WithCorrelationID("id-1"),
+				WithSkipStatuses(tt.input...),
+			).Execute(context.TODO(), nil)
+
+			if err != nil {
+				t.Errorf("The workflow returned an unexpected error: %v", err)
+			}
+			actualOutput := []int{steps[0].invocationCount, steps[1].invocationCount, steps[2].invocationCount}
+			if !reflect.DeepEqual(actualOutput, tt.expectedOutput) {
+				t.Errorf("The steps invocation counts are not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestSequentialExecuteBehaviourOnPermanentFailure(t *testing.T) {
+	anyErr := errors.New("any-err")
+	retryNever := func(ctx context.Context, err error, attempt uint) bool { return false }
+	maxAttempts, _ := defaultRetryConfigProviderTest()
+	retryUntilLast := func(ctx context.Context, err error, attempt uint) bool { return attempt < maxAttempts }
+	var attempt int
+	panicLast := stepFuncMock(func(ctx context.Context, request any) error {
+		if attempt++; attempt > int(maxAttempts) {
+			panic("boom")
+		}
+		return anyErr
+	})
+	tests := []struct {
+		name           string
+		input          SequentialStepConfig[any]
+		expectedOutput StepStatus
+	}{
+		{
+			name:           "a step out of attempts should be stored as failed",
+			input:          SequentialStepConfig[any]{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+			expectedOutput: StepStatusFailed,
+		},
+		{
+			name:           "a step refusing the retry should be stored as permanently failed",
+			input:          SequentialStepConfig[any]{Step: newStepFailedNonRetryable("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest},
+			expectedOutput: StepStatusPermanentlyFailed,
+		},
+		{
+			name:           "a step implementing no retry decider should be stored as permanently failed",
+			input:          SequentialStepConfig[any]{Step: &dagStepMock{name: "step 1", fn: func(ctx context.Context) error { return anyErr }}},
+			expectedOutput: StepStatusPermanentlyFailed,
+		},
+		{
+			name:           "a step out of the attempts allowed by its RetryIf should be stored as failed",
+			input:          SequentialStepConfig[any]{Step: newStepFailedNonRetryable("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, RetryIf: retryAlways},
+			expectedOutput: StepStatusFailed,
+		},
+		{
+			name:           "a step refused the retry by its RetryIf should be stored as permanently failed",
+			input:          SequentialStepConfig[any]{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, RetryIf: retryNever},
+			expectedOutput: StepStatusPermanentlyFailed,
+		},
+		{
+			name:           "a step refused the retry by its RetryIf on the last attempt should be stored as permanently failed",
+			input:          SequentialStepConfig[any]{Step: newStepFailedRetryable("step 1", anyErr), RetryConfigProvider: defaultRetryConfigProviderTest, RetryIf: retryUntilLast},
+			expectedOutput: StepStatusPermanentlyFailed,
+		},
+		{
+			name:           "a step panicking on the last attempt should be stored as permanently failed, even with RetryIf",
+			input:          SequentialStepConfig[any]{Step: panicLast, NameOverride: "step 1", RetryConfigProvider: defaultRetryConfigProviderTest, RetryIf: retryAlways},
+			expectedOutput: StepStatusPermanentlyFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newInMemoryRepo()
+
+			opts := []SequentialOption{WithStorage(repo), WithCorrelationID("id-1"), WithPanicRecovery()}
+
+			NewSequential("some-workflow", []SequentialStepConfig[any]{tt.input}, opts...).Execute(context.TODO(), nil)
+
+			if status, _ := repo.Get(context.TODO(), "step 1", "id-1"); status != tt.expectedOutput {
+				t.Errorf("The failed step status is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, status)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteBehaviourOnPermanentFailure(t *testing.T) {
+	anyErr := errors.New("any-err")
+	maxAttempts, _ := defaultRetryConfigProviderTest()
+	retryUntilLast := func(ctx context.Context, err error, attempt uint) bool { return attempt < maxAttempts }
+	tests := []struct {
+		name           string
+		input          PipeStep[string]
+		retryIf        func(ctx context.Context, err error, attempt uint) bool
+		expectedOutput StepStatus
+	}{
+		{
+			name:           "a step out of attempts should be stored as failed",
+			input:          newPipeStepFailedRetryable[string]("step 1", anyErr),
+			expectedOutput: StepStatusFailed,
+		},
+		{
+			name:           "a step refusing the retry should be stored as permanently failed",
+			input:          newPipeStepFailedNonRetryable[string]("step 1", anyErr),
+			expectedOutput: StepStatusPermanentlyFailed,
+		},
+		{
+			name:           "a step out of the attempts allowed by its RetryIf should be stored as failed",
+			input:          newPipeStepFailedNonRetryable[string]("step 1", anyErr),
+			retryIf:        retryAlways,
+			expectedOutput: StepStatusFailed,
+		},
+		{
+			name:           "a step refused the retry by its RetryIf on the last attempt should be stored as permanently failed",
+			input:          newPipeStepFailedRetryable[string]("step 1", anyErr),
+			retryIf:        retryUntilLast,
+			expectedOutput: StepStatusPermanentlyFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newInMemoryRepo()
+			input := []PipeStepConfig[string]{{Step: tt.input, RetryConfigProvider: defaultRetryConfigProviderTest, RetryIf: tt.retryIf}}
+
+			NewPipe("some-workflow", input, WithPipeStorage(repo), WithPipeCorrelationID("id-1")).Execute(context.TODO(), "x")
+
+			if status, _ := repo.Get(context.TODO(), "step 1", "id-1"); status != tt.expectedOutput {
+				t.Errorf("The failed step status is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, status)
+			}
+		})
+	}
+}
+
+// MOCKS/STUBS
+
+// inMemoryRepo is a Storage keeping the steps execution results in memory.
+type inMemoryRepo struct {
+	mu      sync.Mutex
+	status  map[string]StepStatus
+	output  map[string]*string
+	savedAt map[string]time.Time
+	values  map[string][]byte
+}
+
+func newInMemoryRepo() *inMemoryRepo {
+	return &inMemoryRepo{
+		status:  make(map[string]StepStatus),
+		output:  make(map[string]*string),
+		savedAt: make(map[string]time.Time),
+		values:  make(map[string][]byte),
+	}
+}
+
+func (r *inMemoryRepo) Save(_ context.Context, stepName, correlationID string, status StepStatus, output *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := key(stepName, correlationID)
+	r.status[k] = status
+	r.output[k] = output
+	r.savedAt[k] = time.Now()
+
+	return nil
+}
+
+func (r *inMemoryRepo) Get(_ context.Context, stepName, correlationID string) (StepStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.status[key(stepName, correlationID)], nil
+}
+
+func (r *inMemoryRepo) GetResult(_ context.Context, stepName, correlationID string) (StepResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := key(stepName, correlationID)
+
+	return StepResult{Status: r.status[k], Output: r.output[k], SavedAt: r.savedAt[k]}, nil
+}
+
+func (r *inMemoryRepo) Clear(_ context.Context, correlationID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prefix := clean(correlationID) + ":"
+	for k := range r.status {
+		if strings.HasPrefix(k, prefix) {
+			delete(r.status, k)
+			delete(r.output, k)
+		}
+	}
+	for k := range r.values {
+		if strings.HasPrefix(k, prefix) {
+			delete(r.values, k)
+		}
+	}
+
+	return nil
+}
+
+func (r *inMemoryRepo) SaveValue(_ context.Context, stepName, correlationID string, value []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.values[key(stepName, correlationID)] = value
+
+	return nil
+}
+
+func (r *inMemoryRepo) GetValue(_ context.Context, stepName, correlationID string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.values[key(stepName, correlationID)], nil
+}
+
+func (r *inMemoryRepo) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.status) + len(r.values)
+}
+
+// failingRepo is an inMemoryRepo whose operations fail with the configured errors.
+type failingRepo struct {
+	*inMemoryRepo
+	getErr   error
+	saveErr  error
+	clearErr error
+}
+
+func (r *failingRepo) Save(ctx context.Context, stepName, correlationID string, status StepStatus, output *string) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+
+	return r.inMemoryRepo.Save(ctx, stepName, correlationID, status, output)
+}
+
+func (r *failingRepo) Get(ctx context.Context, stepName, correlationID string) (StepStatus, error) {
+	if r.getErr != nil {
+		return "", r.getErr
+	}
+
+	return r.inMemoryRepo.Get(ctx, stepName, correlationID)
+}
+
+func (r *failingRepo) GetResult(ctx context.Context, stepName, correlationID string) (StepResult, error) {
+	if r.getErr != nil {
+		return StepResult{}, r.getErr
+	}
+
+	return r.inMemoryRepo.GetResult(ctx, stepName, correlationID)
+}
+
+func (r *failingRepo) Clear(ctx context.Context, correlationID string) error {
+	if r.clearErr != nil {
+		return r.clearErr
+	}
+
+	return r.inMemoryRepo.Clear(ctx, correlationID)
+}
+
+func key(stepName, correlationID string) string {
+	return clean(correlationID) + ":" + clean(stepName)
+}
+
+// clean normalizes a key part, by lower casing it, dropping the characters other than letters, digits, spaces and
+// underscores, and collapsing the consecutive spaces and underscores into a single underscore.
+func clean(s string) string {
+	var b strings.Builder
+	var sep bool
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if sep {
+				b.WriteByte('_')
+				sep = false
+			}
+			b.WriteRune(r)
+		case r == ' ' || r == '_':
+			sep = b.Len() > 0
+		}
+	}
+
+	return b.String()
+}
+
+// pipeStepAppend appends a suffix to the received string.
+type pipeStepAppend struct {
+	name            string
+	suffix          string
+	err             error
+	invocationCount int
+	lastInput       string
+}
+
+func newPipeStepAppend(name, suffix string) *pipeStepAppend {
+	return &pipeStepAppend{name: name, suffix: suffix}
+}
+
+func (s *pipeStepAppend) Name() string {
+	return s.name
+}
+
+func (s *pipeStepAppend) Execute(_ context.Context, req string) (string, error) {
+	s.invocationCount++
+	s.lastInput = req
+	if s.err != nil {
+		return "", s.err
+	}
+
+	return req + s.suffix, nil
+}
+
+// pipeStepFunc is a PipeStep running the provided function.
+type pipeStepFunc[T any] struct {
+	name            string
+	fn              func(req T) (T, error)
+	invocationCount int
+}
+
+func newPipeStepFunc[T any](name string, fn func(req T) (T, error)) *pipeStepFunc[T] {
+	return &pipeStepFunc[T]{name: name, fn: fn}
+}
+
+func (s *pipeStepFunc[T]) Name() string {
+	return s.name
+}
+
+func (s *pipeStepFunc[T]) Execute(_ context.Context, req T) (T, error) {
+	s.invocationCount++
+
+	return s.fn(req)
+}
+
+// codecMock is a JSONCodec counting its invocations.
+type codecMock struct {
+	JSONCodec
+	marshalCount   int
+	unmarshalCount int
+}
+
+func (c *codecMock) Marshal(v any) ([]byte, error) {
+	c.marshalCount++
+
+	return c.JSONCodec.Marshal(v)
+}
+
+func (c *codecMock) Unmarshal(data []byte, v any) error {
+	c.unmarshalCount++
+
+	return c.JSONCodec.Unmarshal(data, v)
+}
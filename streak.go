@@ -0,0 +1,61 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// WithMaxConsecutiveFailures stops retrying a step once its last n execution attempts failed with the same error, e.g.
+// a persistently failing step configured with a very high number of attempts, and returns the error, instead of waiting
+// out all the attempts. The error of every attempt is compared to the first error of the streak using errors.Is, so
+// the errors wrapping it are the same error, and a distinct error starts a new streak. A zero n means no limit, which
+// is the default.
+func WithMaxConsecutiveFailures(n uint) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.maxConsecutiveFailures = n
+	}
+}
+
+// WithPipeMaxConsecutiveFailures is the Pipe version of WithMaxConsecutiveFailures.
+func WithPipeMaxConsecutiveFailures(n uint) PipeOption {
+	return func(o *pipeOptions) {
+		o.maxConsecutiveFailures = n
+	}
+}
+
+// failureStreak counts the consecutive attempts of a step failing with the same error.
+type failureStreak struct {
+	max   uint // the failures stopping the retries, no limit if 0
+	last  error
+	count uint
+}
+
+// stop records the failure of the attempt res, about to be retried, and reports whether the retries must stop, as the
+// step failed the max consecutive times with the same error, in which case the failure is logged.
+func (f *failureStreak) stop(ctx context.Context, log logger, res attemptResult) bool {
+	if f.max == 0 {
+		return false
+	}
+	if f.last != nil && errors.Is(res.err, f.last) {
+		f.count++
+	} else {
+		f.last, f.count = res.err, 1
+	}
+	if f.count < f.max {
+		return false
+	}
+	log.printAttempt(ctx, LevelError, res)
+	log.printStep(
+		ctx,
+		LevelError,
+		concatStr(
+			"step: ", res.stepName, " is not retried, it failed with the same error: ",
+			strconv.FormatUint(uint64(f.count), 10), " consecutive times",
+		),
+		res.stepName,
+		res.attempt,
+	)
+
+	return true
+}
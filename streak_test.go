@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExecuteBehaviourOnMaxConsecutiveFailures(t *testing.T) {
+	anyErr, otherErr := errors.New("any-err"), errors.New("other-err")
+	retryConfig := func() (uint, time.Duration) { return 10, 0 }
+	tests := []struct {
+		name                    string
+		input                   uint                 // the max consecutive failures
+		errFunc                 func(call int) error // the error of the attempt numbered call, starting at 1
+		expectedInvocationCount int
+	}{
+		{
+			name:                    "the retries should stop once the step failed n times with the same error",
+			input:                   3,
+			errFunc:                 func(int) error { return anyErr },
+			expectedInvocationCount: 3,
+		},
+		{
+			name:  "the errors wrapping the first one should be the same error, as compared using errors.Is",
+			input: 3,
+			errFunc: func(call int) error {
+				if call == 1 {
+					return anyErr
+				}
+
+				return fmt.Errorf("call %d: %w", call, anyErr)
+			},
+			expectedInvocationCount: 3,
+		},
+		{
+			name:  "the streak should restart on a distinct error",
+			input: 3,
+			errFunc: func(call int) error {
+				if call == 2 {
+					return otherErr
+				}
+
+				return anyErr
+			},
+			expectedInvocationCount: 5,
+		},
+		{
+			name:                    "all the attempts should run without a limit",
+			input:                   0,
+			errFunc:                 func(int) error { return anyErr },
+			expectedInvocationCount: 11,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seqCalls, pipeCalls int
+			seqStep := retryableStepFuncMock(func(ctx context.Context, request any) error {
+				seqCalls++
+
+				return tt.errFunc(seqCalls)
+			})
+			pipeStep := newPipeStepFunc("step 1", func(req string) (string, error) {
+				pipeCalls++
+
+				return req, tt.errFunc(pipeCalls)
+			})
+
+			err := NewSequential(
+				"some-workflow",
+				[]SequentialStepConfig[any]{{Step: seqStep, RetryConfigProvider: retryConfig}},
+				WithMaxConsecutiveFailures(tt.input),
+			).Execute(context.TODO(), nil)
+			_, pipeErr := NewPipe(
+				"some-workflow",
+				[]PipeStepConfig[string]{{Step: pipeStep, RetryConfigProvider: retryConfig, RetryIf: retryAlways}},
+				WithPipeMaxConsecutiveFailures(tt.input),
+			).Execute(context.TODO(), "x")
+
+			if !errors.Is(err, anyErr) || !errors.Is(pipeErr, anyErr) {
+				t.Errorf("The workflow errors are not as expected: \n expected = %#v, \n actual = %#v, %#v", anyErr, err, pipeErr)
+			}
+			if seqCalls != tt.expectedInvocationCount || pipeCalls != tt.expectedInvocationCount {
+				t.Errorf("The step invocation counts are not as expected: \n expected = %#v, \n actual = %#v, %#v", tt.expectedInvocationCount, seqCalls, pipeCalls)
+			}
+		})
+	}
+}
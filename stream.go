@@ -0,0 +1,82 @@
+package workflow
+
+import "context"
+
+// StreamResult is the outcome of the execution of the workflow for an item of a stream, delivered by ExecuteStream.
+type StreamResult[T any] struct {
+	Item T
+	Err  error
+}
+
+// WithStreamWorkers sets the number of the items of a stream processed at the same time by ExecuteStream. A zero n is
+// replaced by 1, which is the default, so the items are processed one after the other.
+// It has no effect on the other execution methods.
+func WithStreamWorkers(n uint) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.streamWorkers = n
+	}
+}
+
+// ExecuteStream runs the workflow, as described by Execute, for every item received from the items channel, so a single
+// workflow processes a stream of items, instead of a new workflow being built for every item. Up to the number of
+// workers set by WithStreamWorkers items are processed at the same time, and no further item is read from the items
+// channel until a worker is free, so a slow workflow applies backpressure to the producer.
+// The result of every item is delivered on the returned channel, in the order the items were received, whatever the
+// order their executions completed in. The returned channel is closed once all the items are processed, after the
+// items channel is closed, or once the ctx is done, in which case no further item is read, and the items being
+// processed are delivered, along with their errors. The returned channel must be drained, as the workers wait for their
+// results to be read.
+// The workflow executions share the ctx, so the steps and the storage must be safe for concurrent use, and the correlation
+// ID, if any, is shared by all the items.
+func (s *Sequential[T]) ExecuteStream(ctx context.Context, items <-chan T) <-chan StreamResult[T] {
+	workers := s.streamWorkers
+	if workers == 0 {
+		workers = 1
+	}
+	// pending holds the channels delivering the results, in the order the items were received.
+	pending := make(chan chan StreamResult[T], workers)
+	results := make(chan StreamResult[T], workers)
+	go s.dispatch(ctx, items, pending, make(chan struct{}, workers))
+	go func() {
+		defer close(results)
+		for res := range pending {
+			results <- <-res
+		}
+	}()
+
+	return results
+}
+
+// dispatch starts the execution of the workflow for every item, once a worker is free, until the items channel is
+// closed or the ctx is done, and queues the channels delivering their results in the pending channel, which it closes
+// before returning.
+func (s *Sequential[T]) dispatch(ctx context.Context, items <-chan T, pending chan<- chan StreamResult[T], workers chan struct{}) {
+	defer close(pending)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case workers <- struct{}{}:
+		}
+		// the ctx takes precedence over a free worker, when both are ready.
+		if ctx.Err() != nil {
+			return
+		}
+		var item T
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok = <-items:
+		}
+		if !ok {
+			return
+		}
+		res := make(chan StreamResult[T], 1)
+		pending <- res
+		go func() {
+			defer func() { <-workers }()
+			res <- StreamResult[T]{Item: item, Err: s.Execute(ctx, item)}
+		}()
+	}
+}
@@ -0,0 +1,100 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSequentialExecuteStreamOrder(t *testing.T) {
+	anyErr := errors.New("any-err")
+	thirdDone := make(chan struct{})
+	input := []SequentialStepConfig[any]{
+		{Step: stepFuncMock(func(ctx context.Context, request any) error {
+			switch request {
+			case 0:
+				// the first item completes after the third one.
+				<-thirdDone
+			case 2:
+				close(thirdDone)
+			}
+			if request.(int)%2 == 1 {
+				return anyErr
+			}
+
+			return nil
+		})},
+	}
+	items := make(chan any)
+	go func() {
+		defer close(items)
+		for i := 0; i < 4; i++ {
+			items <- i
+		}
+	}()
+
+	var actualOutput []any
+	var actualErrs []error
+	for res := range NewSequential("some-workflow", input, WithStreamWorkers(3)).ExecuteStream(context.TODO(), items) {
+		actualOutput = append(actualOutput, res.Item)
+		actualErrs = append(actualErrs, res.Err)
+	}
+
+	expectedOutput := []any{0, 1, 2, 3}
+	if !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The results order is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, actualOutput)
+	}
+	for i, err := range actualErrs {
+		if (i%2 == 1) != errors.Is(err, anyErr) {
+			t.Errorf("The error of the item: %d is not as expected: \n actual = %#v", i, err)
+		}
+	}
+}
+
+func TestSequentialExecuteStreamBehaviourOnCancel(t *testing.T) {
+	started := make(chan struct{})
+	input := []SequentialStepConfig[any]{
+		{Step: stepFuncMock(func(ctx context.Context, request any) error {
+			close(started)
+			<-ctx.Done()
+
+			return ctx.Err()
+		})},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the items channel is never closed, so only the cancellation ends the stream.
+	items := make(chan any, 2)
+	items <- 1
+	items <- 2
+
+	results := NewSequential("some-workflow", input).ExecuteStream(ctx, items)
+	<-started
+	cancel()
+
+	var actualOutput []StreamResult[any]
+	timeout := time.After(time.Second)
+	for done := false; !done; {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				done = true
+
+				break
+			}
+			actualOutput = append(actualOutput, res)
+		case <-timeout:
+			t.Fatalf("The results channel was not closed")
+		}
+	}
+
+	if len(actualOutput) != 1 || actualOutput[0].Item != 1 || !errors.Is(actualOutput[0].Err, context.Canceled) {
+		t.Errorf("The results are not as expected: \n actual = %#v", actualOutput)
+	}
+	// the item not yet read is left in the items channel.
+	if len(items) != 1 {
+		t.Errorf("The unread items count is not as expected: \n expected = %#v, \n actual = %#v", 1, len(items))
+	}
+}
@@ -0,0 +1,38 @@
+package workflow
+
+import "context"
+
+// PipeStepOutput describes the outcome of a Pipe step, as recorded by ExecuteTrace.
+type PipeStepOutput[T any] struct {
+	StepName string
+	// Value is the output of the step, which is its stored or cached output for a step skipped because it already
+	// succeeded, and its unchanged input for a step skipped because of its PipeStepConfig.ShouldRun.
+	Value T
+	Err   error
+	// Skipped reports that the step didn't run, because it already succeeded for the correlation ID, or in the
+	// execution for its cache key, or its ShouldRun returned false.
+	Skipped bool
+}
+
+// ExecuteTrace executes the workflow, as described by Execute, and also returns the outcome of every step which was
+// reached, in the execution order, e.g. to find out which step of a transformation produced an unexpected value.
+// The steps following the one stopping the workflow, or the done ctx, are not part of the trace.
+// The trace is recorded only by ExecuteTrace, so Execute doesn't pay for it.
+func (p *Pipe[T]) ExecuteTrace(ctx context.Context, req T) ([]PipeStepOutput[T], error) {
+	ctx = p.execID.context(ctx)
+	trace := make(pipeTrace[T], 0, len(p.stepsConfig))
+	_, err := p.execute(ctx, req, &trace)
+
+	return trace, p.end.end(ctx, wrapWorkflowErr(p.wrapErrs, p.name, err))
+}
+
+// pipeTrace holds the outcomes of the steps of a Pipe execution.
+type pipeTrace[T any] []PipeStepOutput[T]
+
+// add appends the step outcome to the trace.
+// It is a no op on a nil trace, so the workflows executed without a trace don't pay for it.
+func (t *pipeTrace[T]) add(out PipeStepOutput[T]) {
+	if t != nil {
+		*t = append(*t, out)
+	}
+}
@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPipeExecuteTrace(t *testing.T) {
+	anyErr := errors.New("any-err")
+	failing := newPipeStepAppend("step 2", "-b")
+	failing.err = anyErr
+	stopping := newPipeStepAppend("step 4", "-d")
+	stopping.err = anyErr
+	tests := []struct {
+		name           string
+		input          []PipeStepConfig[string]
+		expectedOutput []PipeStepOutput[string]
+	}{
+		{
+			name: "the trace should hold the output of every step",
+			input: []PipeStepConfig[string]{
+				{Step: newPipeStepAppend("step 1", "-a")},
+				{Step: newPipeStepAppend("step 2", "-b")},
+			},
+			expectedOutput: []PipeStepOutput[string]{
+				{StepName: "step 1", Value: "x-a"},
+				{StepName: "step 2", Value: "x-a-b"},
+			},
+		},
+		{
+			name: "the trace should hold the skipped and failing steps, up to the step stopping the workflow",
+			input: []PipeStepConfig[string]{
+				{Step: newPipeStepAppend("step 1", "-a")},
+				{Step: failing, ContinueOnError: true},
+				{Step: newPipeStepAppend("step 3", "-c"), ShouldRun: func(ctx context.Context, req string) bool { return false }},
+				{Step: stopping},
+				{Step: newPipeStepAppend("step 5", "-e")},
+			},
+			expectedOutput: []PipeStepOutput[string]{
+				{StepName: "step 1", Value: "x-a"},
+				{StepName: "step 2", Err: StepError{StepName: "step 2", Attempts: 1, Err: anyErr}},
+				{StepName: "step 3", Value: "x-a", Skipped: true},
+				{StepName: "step 4", Err: StepError{StepName: "step 4", Attempts: 1, Err: anyErr}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualOutput, _ := NewPipe("some-workflow", tt.input).ExecuteTrace(context.TODO(), "x")
+
+			if !reflect.DeepEqual(actualOutput, tt.expectedOutput) {
+				t.Errorf("The trace is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestPipeExecuteTraceBehaviourOnReplay(t *testing.T) {
+	anyErr := errors.New("any-err")
+	step2 := newPipeStepAppend("step 2", "-b")
+	step2.err = anyErr
+	input := []PipeStepConfig[string]{{Step: newPipeStepAppend("step 1", "-a")}, {Step: step2}}
+	p := NewPipe("some-workflow", input, WithPipeStorage(newInMemoryRepo()), WithPipeCorrelationID("id-1"))
+	p.Execute(context.TODO(), "x")
+
+	step2.err = nil
+	actualOutput, err := p.ExecuteTrace(context.TODO(), "x")
+
+	// the succeeded step is skipped, and its stored output is traced.
+	expectedOutput := []PipeStepOutput[string]{
+		{StepName: "step 1", Value: "x-a", Skipped: true},
+		{StepName: "step 2", Value: "x-a-b"},
+	}
+	if err != nil || !reflect.DeepEqual(actualOutput, expectedOutput) {
+		t.Errorf("The replay trace is not as expected: \n expected = %#v, \n actual = %#v, %v", expectedOutput, actualOutput, err)
+	}
+}
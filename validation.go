@@ -0,0 +1,198 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTooManySteps is returned by the workflows configured with WithMaxSteps or WithPipeMaxSteps, when executed with
+// more steps than the max.
+var ErrTooManySteps = errors.New("too many steps")
+
+// WithMaxSteps makes Execute fail with ErrTooManySteps, before running any step, if the number of the configured steps,
+// or of the steps built by the steps provider of a dynamic workflow, exceeds n. It guards the programmatically assembled
+// workflows against a bug producing an enormous steps collection. A n lower or equal to 0 means no limit, which is the
+// default. It has no effect on the DAG and the Parallel workflows.
+func WithMaxSteps(n int) SequentialOption {
+	return func(o *sequentialOptions) {
+		o.maxSteps = n
+	}
+}
+
+// WithPipeMaxSteps is the Pipe version of WithMaxSteps.
+func WithPipeMaxSteps(n int) PipeOption {
+	return func(o *pipeOptions) {
+		o.maxSteps = n
+	}
+}
+
+// WithStrictValidation makes NewSequential validate the workflow configuration, and panic with the Validate error if
+// the configuration is not valid.
+func WithStrictValidation() SequentialOption {
+	return func(o *sequentialOptions) {
+		o.strictValidation = true
+	}
+}
+
+// WithPipeStrictValidation makes NewPipe validate the workflow configuration, and panic with the Validate error if
+// the configuration is not valid.
+func WithPipeStrictValidation() PipeOption {
+	return func(o *pipeOptions) {
+		o.strictValidation = true
+	}
+}
+
+// Validate reports the problems of the workflow configuration: the nil steps, the duplicate step identifiers(the
+// IdempotencyKey if set, otherwise the NameOverride or the step name), the RetryConfigProvider set for the steps
+// implementing neither the RetryDecider nor the ErrorRetryDecider interface, and having no RetryIf, and the max attempts
+// over MaxRetryAttempts.
+// All the problems found are joined in the returned error, which is nil for a valid configuration.
+// The steps of a dynamic workflow are built at execution time, so they are not validated.
+func (s *Sequential[T]) Validate() error {
+	return validateSequentialSteps(s.name, s.stepsConfig)
+}
+
+// Validate reports the problems of the workflow configuration: the nil steps, the duplicate step identifiers(the
+// IdempotencyKey if set, otherwise the NameOverride or the step name), the RetryConfigProvider set for the steps
+// implementing neither the RetryDecider nor the ErrorRetryDecider interface, and having no RetryIf, and the max attempts
+// over MaxRetryAttempts.
+// All the problems found are joined in the returned error, which is nil for a valid configuration.
+func (p *Pipe[T]) Validate() error {
+	return validatePipeSteps(p.name, p.stepsConfig)
+}
+
+// validateSequentialSteps validates the steps configuration of the workflow, as described by Sequential.Validate.
+// It doesn't take the workflow, so that the constructor validating the configuration doesn't move the workflow to the heap.
+func validateSequentialSteps[T any](workflowName string, stepsCfg []SequentialStepConfig[T]) error {
+	v := newStepsValidator(len(stepsCfg))
+	for i, stepCfg := range stepsCfg {
+		v.checkStep(i, stepCfg.checked())
+	}
+
+	return v.err(workflowName)
+}
+
+// validatePipeSteps validates the steps configuration of the workflow, as described by Pipe.Validate.
+func validatePipeSteps[T any](workflowName string, stepsCfg []PipeStepConfig[T]) error {
+	v := newStepsValidator(len(stepsCfg))
+	for i, stepCfg := range stepsCfg {
+		v.checkStep(i, stepCfg.checked())
+	}
+
+	return v.err(workflowName)
+}
+
+// checked describes the step config for the validation.
+func (c SequentialStepConfig[T]) checked() checkedStep {
+	// the nil interface value must be checked before the conversion, which would make it non nil.
+	if c.Step == nil {
+		return checkedStep{}
+	}
+
+	return checkedStep{
+		step:      c.Step,
+		name:      c.name(),
+		key:       c.IdempotencyKey,
+		retry:     c.RetryConfigProvider,
+		retryable: c.retryable(),
+	}
+}
+
+// checked describes the step config for the validation.
+func (c PipeStepConfig[T]) checked() checkedStep {
+	if c.Step == nil {
+		return checkedStep{}
+	}
+
+	return checkedStep{
+		step:      c.Step,
+		name:      c.name(),
+		key:       c.IdempotencyKey,
+		retry:     c.RetryConfigProvider,
+		retryable: c.retryable(),
+	}
+}
+
+// namedStep is the part of the SequentialStep and PipeStep interfaces needed for the validation.
+type namedStep interface {
+	Name() string
+}
+
+// stepsValidator collects the problems of a workflow steps configuration.
+type stepsValidator struct {
+	indexes map[string]int // the index of every step identifier
+	errs    []error
+}
+
+func newStepsValidator(size int) stepsValidator {
+	return stepsValidator{indexes: make(map[string]int, size)}
+}
+
+// checkedStep describes a configured step, for the validation.
+type checkedStep struct {
+	step      namedStep
+	name      string                       // the name of the step in the workflow
+	key       string                       // the IdempotencyKey, if any
+	retry     func() (uint, time.Duration) // the RetryConfigProvider, if any
+	retryable bool                         // the step can be retried
+}
+
+// checkStep validates the step c, found at the index i of the workflow configuration.
+// The steps are identified by their key, if not empty, otherwise by their name, so the steps sharing a name, but having
+// distinct keys, are valid.
+func (v *stepsValidator) checkStep(i int, c checkedStep) {
+	if c.step == nil {
+		v.errs = append(v.errs, nilStepErr(i))
+
+		return
+	}
+	id, idKind := c.name, "name"
+	if c.key != "" {
+		id, idKind = c.key, "idempotency key"
+	}
+	if j, ok := v.indexes[id]; ok {
+		v.errs = append(v.errs, fmt.Errorf("the step %s: %s is duplicated, at indexes: %d and %d", idKind, id, j, i))
+	} else {
+		v.indexes[id] = i
+	}
+	v.checkRetry(c)
+}
+
+// checkRetry validates the retry configuration of the step c.
+func (v *stepsValidator) checkRetry(c checkedStep) {
+	if c.retry == nil {
+		return
+	}
+	if !c.retryable {
+		v.errs = append(v.errs, fmt.Errorf("the step: %s has a RetryConfigProvider, but can't be retried", c.name))
+	}
+	if maxAttempts, _ := c.retry(); maxAttempts > MaxRetryAttempts {
+		v.errs = append(v.errs, fmt.Errorf("the step: %s has %d max attempts, capped to: %d", c.name, maxAttempts, MaxRetryAttempts))
+	}
+}
+
+// nilStepErr is the error of the nil step found at the index i of the workflow configuration, reported by the
+// validation, and returned by the workflows executing it.
+func nilStepErr(i int) error {
+	return fmt.Errorf("the step at index: %d is nil", i)
+}
+
+// maxStepsErr returns an ErrTooManySteps error if the count of the steps of the workflow exceeds the maxSteps, if
+// greater than 0.
+func maxStepsErr(workflowName string, count, maxSteps int) error {
+	if maxSteps <= 0 || count <= maxSteps {
+		return nil
+	}
+
+	return fmt.Errorf("%w: workflow: %s has: %d steps, more than the max: %d", ErrTooManySteps, workflowName, count, maxSteps)
+}
+
+// err returns the problems found, joined in a single error, or nil if there is none.
+func (v *stepsValidator) err(workflowName string) error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("workflow: %s, invalid configuration:\n%w", workflowName, errors.Join(v.errs...))
+}
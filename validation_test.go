@@ -0,0 +1,197 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSequentialValidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          []SequentialStepConfig[any]
+		expectedOutput []string // the problems reported by the error, if any
+	}{
+		{
+			name: "a valid configuration should return a nil error",
+			input: []SequentialStepConfig[any]{
+				{Step: newStepSuccessful("step 1")},
+				{Step: newStepSuccessful("step 2"), RetryConfigProvider: defaultRetryConfigProviderTest},
+			},
+			expectedOutput: nil,
+		},
+		{
+			name: "a step having a RetryIf should be valid with a RetryConfigProvider",
+			input: []SequentialStepConfig[any]{
+				{
+					Step:                stepFuncMock(nil),
+					RetryConfigProvider: defaultRetryConfigProviderTest,
+					RetryIf:             func(ctx context.Context, err error, attempt uint) bool { return true },
+				},
+			},
+			expectedOutput: nil,
+		},
+		{
+			name: "a configuration with many problems should report all of them",
+			input: []SequentialStepConfig[any]{
+				{Step: newStepSuccessful("step 1")},
+				{Step: nil},
+				{Step: newStepSuccessful("step 1")},
+				{Step: stepFuncMock(nil), RetryConfigProvider: defaultRetryConfigProviderTest},
+				{Step: newStepSuccessful("step 5"), RetryConfigProvider: func() (uint, time.Duration) { return MaxRetryAttempts + 1, 0 }},
+			},
+			expectedOutput: []string{
+				"the step at index: 1 is nil",
+				"the step name: step 1 is duplicated, at indexes: 0 and 2",
+				"the step: step-func has a RetryConfigProvider, but can't be retried",
+				"the step: step 5 has 1001 max attempts, capped to: 1000",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSequential("some-workflow", tt.input).Validate()
+
+			if (err == nil) != (tt.expectedOutput == nil) {
+				t.Fatalf("The validation error is not as expected: \n expected = %#v, \n actual = %#v", tt.expectedOutput, err)
+			}
+			for _, problem := range tt.expectedOutput {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("The validation error does not report the problem: \n expected = %#v, \n actual = %#v", problem, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestPipeValidate(t *testing.T) {
+	input := []PipeStepConfig[string]{
+		{Step: newPipeStepAppend("step 1", "-a")},
+		{Step: newPipeStepAppend("step 1", "-b"), RetryConfigProvider: defaultRetryConfigProviderTest},
+	}
+
+	err := NewPipe("some-workflow", input).Validate()
+
+	expectedOutput := []string{
+		"the step name: step 1 is duplicated, at indexes: 0 and 1",
+		"the step: step 1 has a RetryConfigProvider, but can't be retried",
+	}
+	if err == nil {
+		t.Fatalf("The validation error is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, err)
+	}
+	for _, problem := range expectedOutput {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("The validation error does not report the problem: \n expected = %#v, \n actual = %#v", problem, err.Error())
+		}
+	}
+}
+
+func TestNewSequentialBehaviourOnStrictValidation(t *testing.T) {
+	input := []SequentialStepConfig[any]{
+		{Step: newStepSuccessful("step 1")},
+		{Step: newStepSuccessful("step 1")},
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("The constructor did not panic for a configuration which is not valid")
+		}
+	}()
+
+	NewSequential("some-workflow", input, WithStrictValidation())
+}
+
+func TestNewPipeBehaviourOnStrictValidation(t *testing.T) {
+	input := []PipeStepConfig[string]{
+		{Step: nil},
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("The constructor did not panic for a configuration which is not valid")
+		}
+	}()
+
+	NewPipe("some-workflow", input, WithPipeStrictValidation())
+}
+
+func TestExecuteBehaviourOnNilStep(t *testing.T) {
+	expectedOutput := "the step at index: 1 is nil"
+
+	t.Run("sequential", func(t *testing.T) {
+		step1 := newStepSuccessful("step 1")
+		step3 := newStepSuccessful("step 3")
+		input := []SequentialStepConfig[any]{{Step: step1}, {Step: nil}, {Step: step3}}
+
+		err := NewSequential("some-workflow", input).Execute(context.TODO(), nil)
+
+		if err == nil || err.Error() != expectedOutput {
+			t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, err)
+		}
+		if step1.invocationCount != 1 || step3.invocationCount != 0 {
+			t.Errorf("The steps invocation counts are not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", 1, 0, step1.invocationCount, step3.invocationCount)
+		}
+	})
+	t.Run("pipe", func(t *testing.T) {
+		input := []PipeStepConfig[string]{{Step: newPipeStepAppend("step 1", "-a")}, {Step: nil}, {Step: newPipeStepAppend("step 3", "-c")}}
+
+		out, err := NewPipe("some-workflow", input).Execute(context.TODO(), "x")
+
+		if err == nil || err.Error() != expectedOutput || out != "x-a" {
+			t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x-a", expectedOutput, out, err)
+		}
+	})
+	t.Run("parallel", func(t *testing.T) {
+		step1 := newStepSuccessful("step 1")
+		input := []ParallelStepConfig[any]{{Step: step1}, {Step: nil}}
+
+		err := NewParallel("some-workflow", input).Execute(context.TODO(), nil)
+
+		if err == nil || err.Error() != expectedOutput {
+			t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, err)
+		}
+		if step1.invocationCount != 1 {
+			t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", 1, step1.invocationCount)
+		}
+	})
+}
+
+func TestExecuteBehaviourOnMaxSteps(t *testing.T) {
+	expectedOutput := "too many steps: workflow: some-workflow has: 3 steps, more than the max: 2"
+
+	t.Run("dynamic sequential", func(t *testing.T) {
+		step := newStepSuccessful("step 1")
+		provider := func(ctx context.Context, req any) []SequentialStepConfig[any] {
+			return []SequentialStepConfig[any]{{Step: step}, {Step: step}, {Step: step}}
+		}
+
+		err := NewDynamicSequential("some-workflow", provider, WithMaxSteps(2)).Execute(context.TODO(), nil)
+
+		if !errors.Is(err, ErrTooManySteps) || err.Error() != expectedOutput {
+			t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, err)
+		}
+		if step.invocationCount != 0 {
+			t.Errorf("The step invocation count is not as expected: \n expected = %#v, \n actual = %#v", 0, step.invocationCount)
+		}
+	})
+	t.Run("sequential within the limit", func(t *testing.T) {
+		input := []SequentialStepConfig[any]{{Step: newStepSuccessful("step 1")}, {Step: newStepSuccessful("step 2")}}
+
+		if err := NewSequential("some-workflow", input, WithMaxSteps(2)).Execute(context.TODO(), nil); err != nil {
+			t.Errorf("The workflow error is not as expected: \n expected = %#v, \n actual = %#v", nil, err)
+		}
+	})
+	t.Run("pipe", func(t *testing.T) {
+		input := []PipeStepConfig[string]{
+			{Step: newPipeStepAppend("step 1", "-a")},
+			{Step: newPipeStepAppend("step 2", "-b")},
+			{Step: newPipeStepAppend("step 3", "-c")},
+		}
+
+		out, err := NewPipe("some-workflow", input, WithPipeMaxSteps(2)).Execute(context.TODO(), "x")
+
+		if !errors.Is(err, ErrTooManySteps) || err.Error() != expectedOutput || out != "x" {
+			t.Errorf("The workflow result is not as expected: \n expected = %#v, %#v, \n actual = %#v, %#v", "x", expectedOutput, out, err)
+		}
+	})
+}
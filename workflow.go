@@ -2,7 +2,11 @@ package workflow
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"strconv"
 	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -27,14 +31,413 @@ type RetryDecider interface {
 	CanRetry() bool
 }
 
+// ErrorRetryDecider signals if an operation is retryable, for the error returned by its last attempt, e.g. to retry on
+// a 503 response, but not on a 400 one.
+// It takes precedence over the RetryDecider, for the steps implementing both.
+type ErrorRetryDecider interface {
+	RetryableError(err error) bool
+}
+
+// canRetry reports whether the step can run again, after its last attempt failed with the err.
+// Only the steps implementing the ErrorRetryDecider or the RetryDecider interface can be retried, and never after a
+// recovered panic.
+func canRetry(step any, err error) bool {
+	if isPanic(err) {
+		return false
+	}
+	if d, ok := step.(ErrorRetryDecider); ok {
+		return d.RetryableError(err)
+	}
+	if d, ok := step.(RetryDecider); ok {
+		return d.CanRetry()
+	}
+
+	return false
+}
+
+// isRetryable reports whether the step implements the ErrorRetryDecider or the RetryDecider interface.
+func isRetryable(step any) bool {
+	switch step.(type) {
+	case ErrorRetryDecider, RetryDecider:
+		return true
+	}
+
+	return false
+}
+
+// StepError is the error of a failing step, identifying the step and the number of attempts it ran.
+// The workflows wrap the errors of the failing steps in a StepError, which can be extracted from the returned error by
+// using errors.As, while the step error can still be checked using errors.Is or errors.As.
+type StepError struct {
+	StepName string
+	Attempts uint
+	Err      error
+}
+
+// Error implements the error interface.
+func (e StepError) Error() string {
+	return "step: " + e.StepName + ", attempts: " + strconv.FormatUint(uint64(e.Attempts), 10) + ", err: " + e.Err.Error()
+}
+
+// Unwrap returns the step error, so it can be checked using errors.Is or errors.As.
+func (e StepError) Unwrap() error {
+	return e.Err
+}
+
+// WorkflowError is the error of a failing workflow, identifying the workflow, returned by the workflows configured with
+// WithErrorWrapping or WithPipeErrorWrapping, e.g. for a central handler receiving the errors of several workflows.
+// The Err is the error the workflow would return without the wrapping: a single StepError, or the errors of all the
+// failing steps joined in a single error, which can still be checked using errors.Is or errors.As.
+type WorkflowError struct {
+	WorkflowName string
+	Err          error
+}
+
+// Error implements the error interface.
+func (e WorkflowError) Error() string {
+	return "workflow: " + e.WorkflowName + ", err: " + e.Err.Error()
+}
+
+// Unwrap returns the workflow error, so it can be checked using errors.Is or errors.As.
+func (e WorkflowError) Unwrap() error {
+	return e.Err
+}
+
+// wrapWorkflowErr wraps the err in a WorkflowError, if enabled and if the err is not nil, otherwise it returns the err.
+func wrapWorkflowErr(enabled bool, workflowName string, err error) error {
+	if !enabled || err == nil {
+		return err
+	}
+
+	return WorkflowError{WorkflowName: workflowName, Err: err}
+}
+
+// endHooks holds the hooks called once per execution, when the workflow ends, see WithOnComplete and WithOnError.
+type endHooks struct {
+	onComplete func(ctx context.Context, err error)
+	onError    func(ctx context.Context, err error)
+}
+
+// end calls the hooks with the final err of the execution, nil on success, and returns it.
+func (h endHooks) end(ctx context.Context, err error) error {
+	if h.onComplete != nil {
+		h.onComplete(ctx, err)
+	}
+	if err != nil && h.onError != nil {
+		h.onError(ctx, err)
+	}
+
+	return err
+}
+
+// AllErrors flattens the err returned by a workflow into the list of the errors it joins, e.g. a StepError for every
+// failing step, and the ctx error, in the order they occurred. The StepError, the CompensationError and the
+// AbortedError are not flattened, as they describe a single failure, so the errors of the steps of a nested workflow are
+// held by the StepError of the nested workflow. A WorkflowError is unwrapped, as it describes the whole workflow.
+// It returns nil for a nil err, and a single element list for an err joining no errors.
+func AllErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	return appendLeafErrs(nil, err)
+}
+
+// appendLeafErrs appends to errs the errors joined by the err, recursively, or the err itself if it joins no errors.
+func appendLeafErrs(errs []error, err error) []error {
+	switch e := err.(type) {
+	case *CompensationError, *AbortedError:
+		return append(errs, err)
+	case WorkflowError:
+		return appendLeafErrs(errs, e.Err)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return append(errs, err)
+	}
+	for _, e := range joined.Unwrap() {
+		errs = appendLeafErrs(errs, e)
+	}
+
+	return errs
+}
+
+// FailedSteps returns the names of the failing steps, extracted from the StepError values found in the err returned by
+// a workflow, in the order they failed. The steps of a nested workflow are not listed, only the nested workflow is, and
+// neither are the steps aborted by a failing step, held by an AbortedError.
+func FailedSteps(err error) []string {
+	var names []string
+	for _, e := range AllErrors(err) {
+		if _, ok := e.(*AbortedError); ok {
+			continue
+		}
+		var stepErr StepError
+		if errors.As(e, &stepErr) {
+			names = append(names, stepErr.StepName)
+		}
+	}
+
+	return names
+}
+
+// FailedStepPaths returns the paths of the failing steps, extracted from the StepError values found in the err returned
+// by a workflow, in the order they failed. Unlike FailedSteps, it lists the failing steps of the nested workflows, a path
+// joining with "/" the names of the nested workflows and of the failing step, e.g. "extract-data/get-raw-data-from-db".
+// For an err wrapped in a WorkflowError, see WithErrorWrapping, the paths start with the workflow name, e.g.
+// "ETL/extract-data/get-raw-data-from-db". The steps aborted by a failing step are not listed.
+func FailedStepPaths(err error) []string {
+	var prefix string
+	if wfErr, ok := err.(WorkflowError); ok {
+		prefix = wfErr.WorkflowName + "/"
+	}
+
+	return appendStepPaths(nil, prefix, err)
+}
+
+// appendStepPaths appends to paths the paths of the failing steps found in the err, each starting with the prefix.
+func appendStepPaths(paths []string, prefix string, err error) []string {
+	for _, e := range AllErrors(err) {
+		if _, ok := e.(*AbortedError); ok {
+			continue
+		}
+		var stepErr StepError
+		if !errors.As(e, &stepErr) {
+			continue
+		}
+		n := len(paths)
+		paths = appendStepPaths(paths, prefix+stepErr.StepName+"/", stepErr.Err)
+		// the step is not a workflow, or its failure is not a step failure, e.g. its ctx is done.
+		if len(paths) == n {
+			paths = append(paths, prefix+stepErr.StepName)
+		}
+	}
+
+	return paths
+}
+
 // Logger is the workflow supported logger.
 type Logger interface {
 	Info(msg string)
 	Error(msg string)
 }
 
+// Level is the severity of a log message.
+type Level int
+
+const (
+	LevelDebug Level = iota - 1
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// DebugLogger is a Logger supporting the Debug level, used for the noisy messages, like the retry attempts and waits.
+// If the Logger provided to a workflow doesn't implement it, the Debug level messages are sent to Info.
+type DebugLogger interface {
+	Debug(msg string)
+}
+
+// WarnLogger is a Logger supporting the Warn level, used for the step failures that will be retried.
+// If the Logger provided to a workflow doesn't implement it, the Warn level messages are sent to Error.
+type WarnLogger interface {
+	Warn(msg string)
+}
+
+// Field is a key-value pair attached to a structured log message.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// StructuredLogger is a Logger that also accepts structured fields.
+// If the Logger provided to a workflow implements it, the workflow calls Log instead of Info and Error, and attaches
+// fields like the workflow name, the step name and the attempt to the message.
+type StructuredLogger interface {
+	Logger
+	Log(ctx context.Context, level Level, msg string, fields ...Field)
+}
+
+// Flusher is a Logger buffering the messages, e.g. an asynchronous Logger, which can write the buffered messages.
+// If the Logger provided to a workflow implements it, the workflow calls Flush at the end of every execution, after
+// logging its end, so the last messages are not lost if the process exits right after the execution.
+type Flusher interface {
+	Flush() error
+}
+
+// logger is the internal logging system of the workflows, wrapping the Logger provided by the user.
+type logger struct {
+	log          Logger
+	workflowName string
+	// formatPayload formats the payloads of the steps, logged only if it's not nil, see WithPayloadLogger
+	formatPayload func(stepName string, payload any) string
+}
+
+// override returns the logger sending the messages to the log, instead of the Logger of the workflow, if the log is not
+// nil, e.g. for the steps having a Logger of their own.
+func (l logger) override(log Logger) logger {
+	if log != nil {
+		l.log = log
+	}
+
+	return l
+}
+
+// print sends the msg to the log, at the lvl level, and releases it.
+func (l logger) print(ctx context.Context, lvl Level, msg pooledStr) {
+	defer msg.release()
+	if sl, ok := l.log.(StructuredLogger); ok {
+		sl.Log(ctx, lvl, msg.String(), withExecutionID(ctx, Field{Key: "workflow", Value: l.workflowName})...)
+
+		return
+	}
+	writeExecutionID(ctx, msg.b)
+	l.printUnstructured(lvl, msg.String())
+}
+
+// printDone logs the end of the workflow execution, and flushes the log, if it implements Flusher.
+// The Flush error is dropped, as there is no log to report it to.
+func (l logger) printDone(ctx context.Context) {
+	l.print(ctx, LevelInfo, concatStr("[DONE] executing workflow: ", l.workflowName))
+	if f, ok := l.log.(Flusher); ok {
+		_ = f.Flush()
+	}
+}
+
+// printStep sends the msg, related to the execution of a step, to the log, at the lvl level.
+// The fields are built only for a StructuredLogger, so the unstructured logging produces no allocations.
+// The msg is released once logged.
+func (l logger) printStep(ctx context.Context, lvl Level, msg pooledStr, stepName string, attempt uint) {
+	defer msg.release()
+	if sl, ok := l.log.(StructuredLogger); ok {
+		sl.Log(
+			ctx,
+			lvl,
+			msg.String(),
+			withExecutionID(
+				ctx,
+				Field{Key: "workflow", Value: l.workflowName},
+				Field{Key: "step", Value: stepName},
+				Field{Key: "attempt", Value: attempt},
+			)...,
+		)
+
+		return
+	}
+	writeExecutionID(ctx, msg.b)
+	l.printUnstructured(lvl, msg.String())
+}
+
+// attemptResult describes the outcome of a step execution attempt, for the logs.
+type attemptResult struct {
+	stepName string
+	attempt  uint
+	elapsed  time.Duration
+	err      error // nil for a succeeded attempt
+}
+
+// printAttempt sends the res to the log, at the lvl level, along with the time the attempt took: as a field for a
+// StructuredLogger, and in the message otherwise, e.g. "✓ executing step: X in 42ms".
+func (l logger) printAttempt(ctx context.Context, lvl Level, res attemptResult) {
+	sl, structured := l.log.(StructuredLogger)
+	status := succeed
+	if res.err != nil {
+		status = failed
+	}
+	msg := concatStr(status, " executing step: ", res.stepName)
+	defer msg.release()
+	if !structured {
+		msg.b.WriteString(" in ")
+		msg.b.WriteString(strconv.FormatInt(res.elapsed.Milliseconds(), 10))
+		msg.b.WriteString("ms")
+	}
+	if res.err != nil {
+		msg.b.WriteString(", err: ")
+		msg.b.WriteString(res.err.Error())
+		l.writeNestedFailures(msg.b, res)
+	}
+	if structured {
+		sl.Log(
+			ctx,
+			lvl,
+			msg.String(),
+			withExecutionID(
+				ctx,
+				Field{Key: "workflow", Value: l.workflowName},
+				Field{Key: "step", Value: res.stepName},
+				Field{Key: "attempt", Value: res.attempt},
+				Field{Key: "duration", Value: res.elapsed},
+			)...,
+		)
+
+		return
+	}
+	writeExecutionID(ctx, msg.b)
+	l.printUnstructured(lvl, msg.String())
+}
+
+// writeNestedFailures writes to b the paths of the failing steps of the nested workflow run by the step, starting with
+// the workflow name, e.g. "ETL/extract-data/get-raw-data-from-db", if the step is a workflow. Nothing is written for a
+// disabled logging, as the paths are computed from the error.
+func (l logger) writeNestedFailures(b *bytes.Buffer, res attemptResult) {
+	if _, ok := l.log.(noOpLogger); ok {
+		return
+	}
+	for i, path := range appendStepPaths(nil, l.workflowName+"/"+res.stepName+"/", res.err) {
+		if i == 0 {
+			b.WriteString(", failed steps: ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(path)
+	}
+}
+
+// startTimer returns the start time of a step attempt, read from the clock. The time is not read if the logging is
+// disabled, as only the logs use it.
+func (l logger) startTimer(clock Clock) time.Time {
+	if _, ok := l.log.(noOpLogger); ok {
+		return time.Time{}
+	}
+
+	return now(clock)
+}
+
+// elapsed returns the time elapsed since the start of a step attempt, or 0 if the logging is disabled.
+func (l logger) elapsed(clock Clock, start time.Time) time.Duration {
+	if _, ok := l.log.(noOpLogger); ok {
+		return 0
+	}
+
+	return now(clock).Sub(start)
+}
+
+// printUnstructured sends the msg to the Logger method matching the lvl level.
+// The Debug and Warn levels fall back to Info and Error, if the Logger doesn't implement DebugLogger and WarnLogger.
+func (l logger) printUnstructured(lvl Level, msg string) {
+	switch lvl {
+	case LevelDebug:
+		if dl, ok := l.log.(DebugLogger); ok {
+			dl.Debug(msg)
+
+			return
+		}
+		l.log.Info(msg)
+	case LevelWarn:
+		if wl, ok := l.log.(WarnLogger); ok {
+			wl.Warn(msg)
+
+			return
+		}
+		l.log.Error(msg)
+	case LevelError:
+		l.log.Error(msg)
+	default:
+		l.log.Info(msg)
+	}
+}
+
 // noOpLogger is the internal, default logger, and is a no op.
-// It exists only to allow the user to disable logging, by providing a nil logger to the Sequential constructor.
+// It exists only to allow the user to disable logging, by not providing a logger to the workflow constructors.
 type noOpLogger struct{}
 
 // Info is the Info level log.
@@ -44,18 +447,79 @@ func (n noOpLogger) Info(_ string) {}
 func (n noOpLogger) Error(_ string) {}
 
 // concatStr produces a 0 allocation string concatenation, by taking the best parts from both bytes.Buffer and strings.Builder.
-// The resulting string must be consumed ASAP, otherwise the content is not guaranteed to stay the same.
-func concatStr(in ...string) string {
+// The resulting message holds a pooled buffer, which returns to the pool only once the message is released, after
+// being logged, so the concurrent executions can't overwrite the content of a message still in use.
+func concatStr(in ...string) pooledStr {
 	b := bufPool.Get().(*bytes.Buffer)
 	b.Reset()
-	defer bufPool.Put(b)
-
 	for _, v := range in {
 		b.WriteString(v)
 	}
-	// keep in mind that, as the package name suggests, this approach is not safe, and the string should be
-	// "consumed" ASAP after this return, otherwise the content is not guaranteed.
-	// it works well in the non-concurrent pre logging string composition, as we send the content to the writer, right
-	// after this return.
-	return unsafe.String(unsafe.SliceData(b.Bytes()), len(b.Bytes()))
+
+	return pooledStr{b: b}
+}
+
+// pooledStr is a string built in a pooled buffer.
+type pooledStr struct {
+	b *bytes.Buffer
+}
+
+// String returns the content of the buffer, without a copy.
+// keep in mind that, as the package name suggests, this approach is not safe, and the string must not be used after
+// the release, as the content is not guaranteed to stay the same.
+func (s pooledStr) String() string {
+	return unsafe.String(unsafe.SliceData(s.b.Bytes()), s.b.Len())
+}
+
+// release returns the buffer to the pool.
+func (s pooledStr) release() {
+	bufPool.Put(s.b)
+}
+
+// Clock provides the time to the workflows, for the retries waiting and budget, and the steps durations logged, so they
+// can be tested without waiting, e.g. by using the workflowtest.FakeClock.
+type Clock interface {
+	Now() time.Time
+	// After waits for the duration d to elapse, and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// now returns the current time of the clock, or the system time if the clock is nil.
+func now(clock Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+
+	return clock.Now()
+}
+
+// sleep pauses the current goroutine for the duration d, or until the ctx is done, in which case it returns the ctx error.
+// The duration is measured by the clock, or by the system time if the clock is nil.
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if clock != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(d):
+			return nil
+		}
+	}
+	done := ctx.Done()
+	if done == nil {
+		// the ctx can never be cancelled, so there is no need for a timer allocation.
+		time.Sleep(d)
+
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-done:
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
 }
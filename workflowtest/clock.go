@@ -0,0 +1,102 @@
+// Package workflowtest provides utilities for testing the workflows, and the code using them.
+package workflowtest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a workflow.Clock whose time moves only when told to, so the retries delays and budgets can be tested
+// deterministically, without waiting. It is safe for concurrent use.
+type FakeClock struct {
+	mu          sync.Mutex
+	cond        *sync.Cond // signals the waiters changes
+	now         time.Time
+	autoAdvance bool
+	waiters     []waiter
+}
+
+// waiter is a pending After call.
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set at now, whose time moves only by Advance.
+// The workflow waiting on the FakeClock is blocked until the time is advanced, which allows testing the behaviour during
+// the wait, e.g. a cancellation, by using BlockUntil to know when the workflow waits.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+
+	return &c
+}
+
+// NewAutoAdvancingFakeClock returns a FakeClock set at now, whose time moves by d on every After(d) call, so the
+// workflow waiting on the FakeClock goes on at once, as if the d elapsed.
+func NewAutoAdvancingFakeClock(now time.Time) *FakeClock {
+	c := NewFakeClock(now)
+	c.autoAdvance = true
+
+	return c
+}
+
+// Now implements the workflow.Clock interface.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After implements the workflow.Clock interface.
+// The returned channel receives the time once the FakeClock is advanced by d, or at once for an auto advancing FakeClock,
+// or for a d lower than or equal to 0.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if c.autoAdvance && d > 0 {
+		c.now = c.now.Add(d)
+	}
+	if c.autoAdvance || d <= 0 {
+		ch <- c.now
+
+		return ch
+	}
+	c.waiters = append(c.waiters, waiter{deadline: c.now.Add(d), ch: ch})
+	c.cond.Broadcast()
+
+	return ch
+}
+
+// Advance moves the time forward by d, and ends the waits whose duration elapsed.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			pending = append(pending, w)
+
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+	c.cond.Broadcast()
+}
+
+// BlockUntil blocks until n waits are pending, e.g. until the workflow waits before a retry, so the test can then
+// advance the time, or cancel the workflow.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) != n {
+		c.cond.Wait()
+	}
+}
@@ -0,0 +1,64 @@
+package workflowtest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClockAdvance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	ch := c.After(2 * time.Second)
+	c.Advance(time.Second)
+	select {
+	case <-ch:
+		t.Fatalf("The wait ended before its duration elapsed")
+	default:
+	}
+	c.Advance(time.Second)
+
+	select {
+	case now := <-ch:
+		expectedOutput := start.Add(2 * time.Second)
+		if !now.Equal(expectedOutput) {
+			t.Errorf("The wait end time is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, now)
+		}
+	default:
+		t.Fatalf("The wait did not end once its duration elapsed")
+	}
+	if now := c.Now(); !now.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("The time is not as expected: \n expected = %#v, \n actual = %#v", start.Add(2*time.Second), now)
+	}
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	c := NewFakeClock(time.Time{})
+	done := make(chan struct{})
+
+	go func() {
+		<-c.After(time.Second)
+		close(done)
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("The waiting goroutine was not released")
+	}
+}
+
+func TestAutoAdvancingFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewAutoAdvancingFakeClock(start)
+
+	<-c.After(time.Second)
+	<-c.After(2 * time.Second)
+
+	expectedOutput := start.Add(3 * time.Second)
+	if now := c.Now(); !now.Equal(expectedOutput) {
+		t.Errorf("The time is not as expected: \n expected = %#v, \n actual = %#v", expectedOutput, now)
+	}
+}
@@ -0,0 +1,53 @@
+package workflowtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/silviutanasa/workflow"
+	"github.com/silviutanasa/workflow/workflowtest"
+)
+
+func ExampleNewAutoAdvancingFakeClock() {
+	clock := workflowtest.NewAutoAdvancingFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	step := &flakyStep{failures: 2}
+	wf := workflow.NewSequential(
+		"example",
+		[]workflow.SequentialStepConfig[any]{{
+			Step:                step,
+			RetryConfigProvider: func() (uint, time.Duration) { return 3, time.Hour },
+		}},
+		workflow.WithClock(clock),
+	)
+
+	// the retries wait for an hour each, on the clock, so the execution ends at once.
+	err := wf.Execute(context.Background(), nil)
+	fmt.Println(err, clock.Now().Format(time.TimeOnly))
+	// Output:
+	// <nil> 02:00:00
+}
+
+// flakyStep fails its first executions.
+type flakyStep struct {
+	failures int
+}
+
+func (s *flakyStep) Name() string {
+	return "flaky"
+}
+
+func (s *flakyStep) Execute(_ context.Context, _ any) error {
+	if s.failures > 0 {
+		s.failures--
+
+		return errors.New("temporary failure")
+	}
+
+	return nil
+}
+
+func (s *flakyStep) CanRetry() bool {
+	return true
+}